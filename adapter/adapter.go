@@ -0,0 +1,426 @@
+// Package adapter 将聊天平台接入 xgpt3。各平台的实现位于子包中：
+// wechat 使用公众号的被动回复协议，slack、telegram、feishu 基于 Bot，只需要实现平台相关的 Platform
+package adapter
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultModel        = openai.GPT3Dot5Turbo
+	defaultTimeout      = 2 * time.Minute
+	defaultEditInterval = time.Second
+	defaultErrorReply   = "服务繁忙，请稍后再试"
+	// 与会话表 channel 字段的长度一致
+	maxChannelLength = 50
+	maxBodyBytes     = 1 << 20
+)
+
+// ErrInvalidSignature 推送的签名或令牌校验失败
+var ErrInvalidSignature = errors.New("adapter: invalid signature")
+
+// Adapter 接入 xgpt3 的聊天平台，接收平台推送的 HTTP 请求
+type Adapter interface {
+	http.Handler
+	// Name 平台名称，同时是默认的消息渠道
+	Name() string
+}
+
+// Message 平台推送的用户消息
+type Message struct {
+	// 平台的消息或事件Id，作为幂等键
+	ID string
+	// 发送者Id
+	UserID string
+	// 消息所在的私聊、群聊或频道，回复发送到这里
+	ChatID string
+	// 消息所在的话题，为空时不在话题中
+	ThreadID string
+	// 是否为私聊
+	Private bool
+	// 群聊中的消息是否 @机器人 或回复机器人，私聊中忽略
+	Mentioned bool
+	// 消息内容，已去掉 @机器人 等平台标记
+	Text string
+}
+
+// Webhook 推送的解析结果
+type Webhook struct {
+	// 需要直接返回给平台的 JSON 响应，例如 URL 验证的 challenge。为空时返回空的 200 响应
+	Response interface{}
+	// 需要处理的消息，为空时不处理
+	Message *Message
+}
+
+// Platform 平台相关的部分：校验和解析推送，发送消息
+type Platform interface {
+	// Name 平台名称
+	Name() string
+	// Parse 校验并解析推送。签名或令牌错误时返回 ErrInvalidSignature
+	Parse(r *http.Request, body []byte) (*Webhook, error)
+	// Send 向消息所在的位置发送文本，返回平台的消息Id
+	Send(ctx context.Context, to *Message, text string) (string, error)
+	// MaxMessageBytes 单条消息的最大字节数
+	MaxMessageBytes() int
+}
+
+// Editor 支持编辑已发送消息的平台。流式输出时先发送一条消息，再随着生成逐步更新
+type Editor interface {
+	Edit(ctx context.Context, to *Message, messageId, text string) error
+}
+
+// Client 发起对话、记录群聊发言和关闭会话，*xgpt3.Client 满足该接口
+type Client interface {
+	CreateChatCompletionWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (openai.ChatCompletionResponse, error)
+	CreateChatCompletionStreamWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (*xgpt3.ChatCompletionStream, error)
+	RecordGroupMessage(ctx context.Context, groupId, channel, userId, content string) error
+	CloseConversationWithChannel(ctx context.Context, userId, channel string) error
+}
+
+// Bot 基于 Platform 的通用实现：收到推送后立即响应，在后台生成回复；
+// 平台支持编辑消息时流式更新回复；以 / 开头的消息作为命令处理。
+// 群聊中只回复 @机器人 的消息
+type Bot struct {
+	client       Client
+	platform     Platform
+	sessions     conversation.SessionManager
+	channel      string
+	model        string
+	errorReply   string
+	timeout      time.Duration
+	editInterval time.Duration
+	stream       bool
+	groupChat    bool
+	logger       zerolog.Logger
+
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+func New(client Client, platform Platform) *Bot {
+	return &Bot{
+		client:       client,
+		platform:     platform,
+		channel:      platform.Name(),
+		model:        defaultModel,
+		errorReply:   defaultErrorReply,
+		timeout:      defaultTimeout,
+		editInterval: defaultEditInterval,
+		stream:       true,
+		logger:       log.Logger,
+		pending:      make(map[string]bool),
+	}
+}
+
+// WithChannel 设置消息渠道，默认为平台名称。群聊和话题中的消息使用 "渠道:群聊Id/话题Id" 作为渠道
+func (b *Bot) WithChannel(channel string) *Bot {
+	b.channel = channel
+	return b
+}
+
+// WithModel 设置对话使用的模型
+func (b *Bot) WithModel(model string) *Bot {
+	b.model = model
+	return b
+}
+
+// WithSessions 开启 /sessions 和 /switch 命令
+func (b *Bot) WithSessions(s conversation.SessionManager) *Bot {
+	b.sessions = s
+	return b
+}
+
+// WithErrorReply 设置生成回复失败时的提示
+func (b *Bot) WithErrorReply(reply string) *Bot {
+	b.errorReply = reply
+	return b
+}
+
+// WithTimeout 设置生成回复的最长时间
+func (b *Bot) WithTimeout(d time.Duration) *Bot {
+	b.timeout = d
+	return b
+}
+
+// WithEditInterval 设置流式输出时编辑消息的最小间隔，避免触发平台的频率限制
+func (b *Bot) WithEditInterval(d time.Duration) *Bot {
+	b.editInterval = d
+	return b
+}
+
+// WithStream 设置平台支持编辑消息时是否流式输出
+func (b *Bot) WithStream(stream bool) *Bot {
+	b.stream = stream
+	return b
+}
+
+// WithGroupChat 开启群聊模式：群内所有成员共用一个会话，没有 @机器人 的发言也会保存，
+// 作为之后回复的上下文。会话后端需要实现 conversation.GroupHandler
+func (b *Bot) WithGroupChat() *Bot {
+	b.groupChat = true
+	return b
+}
+
+func (b *Bot) WithLogger(l zerolog.Logger) *Bot {
+	b.logger = l
+	return b
+}
+
+func (b *Bot) Name() string {
+	return b.platform.Name()
+}
+
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+	hook, err := b.platform.Parse(r, body)
+	if errors.Is(err, ErrInvalidSignature) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		b.logger.Warn().Msgf("Parse %s webhook failed: %s", b.Name(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 平台要求在几秒内响应，回复在后台生成。处理中的重复推送直接忽略
+	if msg := hook.Message; msg != nil && b.begin(msg.ID) {
+		go func() {
+			defer b.end(msg.ID)
+			b.handle(msg)
+		}()
+	}
+	if hook.Response == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(hook.Response); err != nil {
+		b.logger.Warn().Msgf("Write %s response failed: %s", b.Name(), err)
+	}
+}
+
+func (b *Bot) handle(msg *Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	defer cancel()
+
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		return
+	}
+	if strings.HasPrefix(text, "/") {
+		b.send(ctx, msg, b.command(ctx, msg, text))
+		return
+	}
+	if !msg.Private && !msg.Mentioned {
+		if b.groupChat {
+			b.record(ctx, msg, text)
+		}
+		return
+	}
+
+	ctx = xgpt3.WithIdempotencyKey(ctx, msg.ID)
+	if b.isGroup(msg) {
+		ctx = xgpt3.WithGroup(ctx, msg.ChatID)
+	}
+	request := openai.ChatCompletionRequest{
+		Model:    b.model,
+		User:     msg.UserID,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: text}},
+	}
+	if editor, ok := b.platform.(Editor); ok && b.stream {
+		b.streamReply(ctx, editor, msg, request)
+		return
+	}
+	resp, err := b.client.CreateChatCompletionWithChannel(ctx, request, b.ChannelOf(msg))
+	if err != nil {
+		b.logger.Warn().Msgf("%s chat completion for %s failed: %s", b.Name(), msg.UserID, err)
+		b.send(ctx, msg, b.errorReply)
+		return
+	}
+	if len(resp.Choices) > 0 {
+		b.send(ctx, msg, resp.Choices[0].Message.Content)
+	}
+}
+
+// record 保存群聊中没有 @机器人 的发言
+func (b *Bot) record(ctx context.Context, msg *Message, text string) {
+	if err := b.client.RecordGroupMessage(ctx, msg.ChatID, b.ChannelOf(msg), msg.UserID, text); err != nil {
+		b.logger.Warn().Msgf("Record %s group message of %s failed: %s", b.Name(), msg.ChatID, err)
+	}
+}
+
+// streamReply 流式生成回复，按间隔编辑已发送的消息。超过单条消息长度时另起一条消息
+func (b *Bot) streamReply(ctx context.Context, editor Editor, msg *Message, request openai.ChatCompletionRequest) {
+	stream, err := b.client.CreateChatCompletionStreamWithChannel(ctx, request, b.ChannelOf(msg))
+	if errors.Is(err, xgpt3.ErrDuplicateRequest) {
+		return
+	}
+	if err != nil {
+		b.logger.Warn().Msgf("%s chat completion stream for %s failed: %s", b.Name(), msg.UserID, err)
+		b.send(ctx, msg, b.errorReply)
+		return
+	}
+	defer stream.Close()
+
+	p := &progressive{bot: b, editor: editor, to: msg}
+	last := time.Now()
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			b.logger.Warn().Msgf("%s chat completion stream for %s failed: %s", b.Name(), msg.UserID, err)
+			p.text += "\n\n" + b.errorReply
+			break
+		}
+		if len(resp.Choices) > 0 {
+			p.text += resp.Choices[0].Delta.Content
+		}
+		if time.Since(last) >= b.editInterval {
+			p.flush(ctx)
+			last = time.Now()
+		}
+	}
+	p.flush(ctx)
+}
+
+// progressive 流式输出中正在更新的消息
+type progressive struct {
+	bot    *Bot
+	editor Editor
+	to     *Message
+	// 当前消息的平台Id，为空时尚未发送
+	id string
+	// 当前消息的内容及已经发送的内容
+	text, sent string
+}
+
+func (p *progressive) flush(ctx context.Context) {
+	if strings.TrimSpace(p.text) == "" || p.text == p.sent {
+		return
+	}
+	parts := SplitText(p.text, p.bot.platform.MaxMessageBytes())
+	for i, part := range parts {
+		if i > 0 {
+			p.id = ""
+		}
+		var err error
+		if p.id == "" {
+			p.id, err = p.bot.platform.Send(ctx, p.to, part)
+		} else {
+			err = p.editor.Edit(ctx, p.to, p.id, part)
+		}
+		if err != nil {
+			p.bot.logger.Warn().Msgf("Deliver %s message to %s failed: %s", p.bot.Name(), p.to.ChatID, err)
+			return
+		}
+	}
+	p.text = parts[len(parts)-1]
+	p.sent = p.text
+}
+
+// send 发送文本，超长时拆成多条
+func (b *Bot) send(ctx context.Context, to *Message, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	for _, part := range SplitText(text, b.platform.MaxMessageBytes()) {
+		if _, err := b.platform.Send(ctx, to, part); err != nil {
+			b.logger.Warn().Msgf("Send %s message to %s failed: %s", b.Name(), to.ChatID, err)
+			return
+		}
+	}
+}
+
+// ChannelOf 返回消息对应的消息渠道。私聊使用 Bot 的渠道，群聊和话题中的消息各自使用独立的渠道。
+// 群聊模式下群内所有话题共用群的渠道
+func (b *Bot) ChannelOf(msg *Message) string {
+	if msg.Private && msg.ThreadID == "" {
+		return b.channel
+	}
+	scope := msg.ChatID
+	if msg.ThreadID != "" && !b.isGroup(msg) {
+		scope += "/" + msg.ThreadID
+	}
+	channel := b.channel + ":" + scope
+	if len(channel) > maxChannelLength {
+		h := sha1.Sum([]byte(scope))
+		channel = b.channel + ":" + hex.EncodeToString(h[:8])
+	}
+	return channel
+}
+
+// isGroup 消息是否属于群聊模式下的群会话
+func (b *Bot) isGroup(msg *Message) bool {
+	return b.groupChat && !msg.Private
+}
+
+// ownerOf 返回消息所属会话的用户Id。群聊模式下群会话属于群
+func (b *Bot) ownerOf(msg *Message) string {
+	if b.isGroup(msg) {
+		return msg.ChatID
+	}
+	return msg.UserID
+}
+
+func (b *Bot) begin(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pending[id] {
+		return false
+	}
+	b.pending[id] = true
+	return true
+}
+
+func (b *Bot) end(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.pending, id)
+}
+
+// SplitText 按字节数切分文本，尽量在换行处切分，不会截断 UTF-8 字符
+func SplitText(s string, maxBytes int) []string {
+	var parts []string
+	for len(s) > maxBytes {
+		end := maxBytes
+		for end > 0 && !utf8.RuneStart(s[end]) {
+			end--
+		}
+		if i := strings.LastIndexByte(s[:end], '\n'); i >= end/2 {
+			end = i + 1
+		}
+		parts = append(parts, strings.TrimRight(s[:end], "\n"))
+		s = strings.TrimLeft(s[end:], "\n")
+	}
+	if s != "" || len(parts) == 0 {
+		parts = append(parts, s)
+	}
+	return parts
+}
@@ -0,0 +1,107 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+const help = `命令：
+/new 开启新会话
+/sessions 列出历史会话
+/switch <id> 切换到指定会话
+/help 显示帮助`
+
+// command 处理以 / 开头的命令，返回需要回复的内容
+func (b *Bot) command(ctx context.Context, msg *Message, text string) string {
+	cmd, arg, _ := strings.Cut(text, " ")
+	arg = strings.TrimSpace(arg)
+	// Telegram 群聊中的命令带有机器人名称，例如 /new@xgpt3_bot
+	cmd, _, _ = strings.Cut(cmd, "@")
+
+	var reply string
+	var err error
+	switch cmd {
+	case "/new":
+		reply, err = b.newSession(ctx, msg)
+	case "/sessions":
+		reply, err = b.listSessions(ctx, msg)
+	case "/switch":
+		reply, err = b.switchSession(ctx, msg, arg)
+	case "/help":
+		reply = help
+	default:
+		err = fmt.Errorf("未知命令 %s，发送 /help 查看命令", cmd)
+	}
+	if err != nil {
+		return fmt.Sprintf("错误：%s", err)
+	}
+	return reply
+}
+
+func (b *Bot) newSession(ctx context.Context, msg *Message) (string, error) {
+	owner := b.ownerOf(msg)
+	if err := b.client.CloseConversationWithChannel(ctx, owner, b.ChannelOf(msg)); err != nil {
+		b.logger.Warn().Msgf("Close %s conversation of %s failed: %s", b.Name(), owner, err)
+		return "", errors.New("开启新会话失败")
+	}
+	return "已开启新会话", nil
+}
+
+func (b *Bot) listSessions(ctx context.Context, msg *Message) (string, error) {
+	if b.sessions == nil {
+		return "", errors.New("不支持会话管理")
+	}
+	owner := b.ownerOf(msg)
+	sessions, err := b.sessions.ListSessions(ctx, owner, conversation.Page{Limit: 50})
+	if err != nil {
+		b.logger.Warn().Msgf("List %s sessions of %s failed: %s", b.Name(), owner, err)
+		return "", errors.New("获取会话列表失败")
+	}
+
+	var sb strings.Builder
+	channel := b.ChannelOf(msg)
+	for _, s := range sessions {
+		if s.Channel != channel {
+			continue
+		}
+		mark := " "
+		if s.Status {
+			mark = "*"
+		}
+		title := s.Title
+		if title == "" {
+			title = "(无标题)"
+		}
+		fmt.Fprintf(&sb, "%s %d  %s  %s\n", mark, s.ID, s.CreatedAt.Local().Format("2006-01-02 15:04"), title)
+	}
+	if sb.Len() == 0 {
+		return "没有历史会话", nil
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+func (b *Bot) switchSession(ctx context.Context, msg *Message, arg string) (string, error) {
+	if b.sessions == nil {
+		return "", errors.New("不支持会话管理")
+	}
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return "", errors.New("用法：/switch <id>")
+	}
+	// 只能切换到当前渠道中的会话
+	owner := b.ownerOf(msg)
+	s, err := b.sessions.GetSession(ctx, owner, id)
+	if err != nil || s.Channel != b.ChannelOf(msg) {
+		return "", fmt.Errorf("会话 %d 不存在", id)
+	}
+	if err := b.sessions.ReopenSession(ctx, owner, id); err != nil {
+		b.logger.Warn().Msgf("Reopen %s session %d of %s failed: %s", b.Name(), id, owner, err)
+		return "", errors.New("切换会话失败")
+	}
+	return fmt.Sprintf("已切换到会话 %d", id), nil
+}
@@ -0,0 +1,348 @@
+// Package feishu 通过事件订阅将飞书 (Lark) 机器人接入 xgpt3
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3/adapter"
+)
+
+const (
+	name = "feishu"
+	// Lark 国际版使用 https://open.larksuite.com
+	defaultBaseURL  = "https://open.feishu.cn"
+	maxMessageBytes = 30000
+	chatTypeP2P     = "p2p"
+	msgTypeText     = "text"
+	typeURLVerify   = "url_verification"
+	eventReceive    = "im.message.receive_v1"
+	senderTypeUser  = "user"
+)
+
+var errInvalidEncrypt = errors.New("feishu: invalid encrypted event")
+
+// Config 飞书应用配置
+type Config struct {
+	AppID     string
+	AppSecret string
+	// 事件订阅的 Verification Token，必填
+	VerificationToken string
+	// 事件订阅的 Encrypt Key，为空时事件不加密
+	EncryptKey string
+	// 机器人的 open_id，用于识别 @机器人 的消息。为空时 @任何人 都作为 @机器人 处理
+	BotOpenID string
+}
+
+// Feishu 实现 adapter.Platform 和 adapter.Editor
+type Feishu struct {
+	cfg        Config
+	baseURL    string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// ErrVerificationTokenRequired 没有配置 VerificationToken 时无法校验推送来自飞书
+var ErrVerificationTokenRequired = errors.New("feishu: VerificationToken is required")
+
+func New(cfg Config) (*Feishu, error) {
+	if cfg.VerificationToken == "" {
+		return nil, ErrVerificationTokenRequired
+	}
+	return &Feishu{
+		cfg:        cfg,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// WithAPI 设置开放平台地址和 HTTP client，用于 Lark 国际版、代理或测试
+func (f *Feishu) WithAPI(baseURL string, client *http.Client) *Feishu {
+	f.baseURL = strings.TrimRight(baseURL, "/")
+	f.httpClient = client
+	return f
+}
+
+func (f *Feishu) Name() string {
+	return name
+}
+
+func (f *Feishu) MaxMessageBytes() int {
+	return maxMessageBytes
+}
+
+// callback 事件回调。URL 验证使用 1.0 格式，消息事件使用 2.0 格式
+type callback struct {
+	Encrypt   string `json:"encrypt"`
+	Type      string `json:"type"`
+	Token     string `json:"token"`
+	Challenge string `json:"challenge"`
+	Header    struct {
+		EventID   string `json:"event_id"`
+		EventType string `json:"event_type"`
+		Token     string `json:"token"`
+	} `json:"header"`
+	Event json.RawMessage `json:"event"`
+}
+
+type receiveEvent struct {
+	Sender struct {
+		SenderID struct {
+			OpenID string `json:"open_id"`
+		} `json:"sender_id"`
+		SenderType string `json:"sender_type"`
+	} `json:"sender"`
+	Message struct {
+		MessageID   string `json:"message_id"`
+		ThreadID    string `json:"thread_id"`
+		ChatID      string `json:"chat_id"`
+		ChatType    string `json:"chat_type"`
+		MessageType string `json:"message_type"`
+		Content     string `json:"content"`
+		Mentions    []struct {
+			Key string `json:"key"`
+			ID  struct {
+				OpenID string `json:"open_id"`
+			} `json:"id"`
+		} `json:"mentions"`
+	} `json:"message"`
+}
+
+type textContent struct {
+	Text string `json:"text"`
+}
+
+func (f *Feishu) Parse(r *http.Request, body []byte) (*adapter.Webhook, error) {
+	var cb callback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	if cb.Encrypt != "" {
+		if !f.verify(r, body) {
+			return nil, adapter.ErrInvalidSignature
+		}
+		plain, err := f.decrypt(cb.Encrypt)
+		if err != nil {
+			return nil, err
+		}
+		cb = callback{}
+		if err := json.Unmarshal(plain, &cb); err != nil {
+			return nil, fmt.Errorf("invalid event: %w", err)
+		}
+	}
+
+	if cb.Type == typeURLVerify {
+		if !f.checkToken(cb.Token) {
+			return nil, adapter.ErrInvalidSignature
+		}
+		return &adapter.Webhook{Response: map[string]string{"challenge": cb.Challenge}}, nil
+	}
+	if !f.checkToken(cb.Header.Token) {
+		return nil, adapter.ErrInvalidSignature
+	}
+	if cb.Header.EventType != eventReceive {
+		return &adapter.Webhook{}, nil
+	}
+
+	var e receiveEvent
+	if err := json.Unmarshal(cb.Event, &e); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	m := e.Message
+	if e.Sender.SenderType != senderTypeUser || m.MessageType != msgTypeText {
+		return &adapter.Webhook{}, nil
+	}
+	var content textContent
+	if err := json.Unmarshal([]byte(m.Content), &content); err != nil {
+		return nil, fmt.Errorf("invalid message content: %w", err)
+	}
+	// 去掉 @机器人 的占位符，例如 @_user_1
+	text := content.Text
+	mentioned := false
+	for _, mention := range m.Mentions {
+		text = strings.ReplaceAll(text, mention.Key, "")
+		if f.cfg.BotOpenID == "" || mention.ID.OpenID == f.cfg.BotOpenID {
+			mentioned = true
+		}
+	}
+	return &adapter.Webhook{Message: &adapter.Message{
+		// 飞书重试推送时消息Id 不变，同时用于在话题中回复
+		ID:        m.MessageID,
+		UserID:    e.Sender.SenderID.OpenID,
+		ChatID:    m.ChatID,
+		ThreadID:  m.ThreadID,
+		Private:   m.ChatType == chatTypeP2P,
+		Mentioned: mentioned,
+		Text:      strings.TrimSpace(text),
+	}}, nil
+}
+
+func (f *Feishu) checkToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(f.cfg.VerificationToken)) == 1
+}
+
+// verify 校验签名：SHA256(时间戳 + Nonce + Encrypt Key + 请求体)。没有签名头时依赖 Verification Token 校验
+func (f *Feishu) verify(r *http.Request, body []byte) bool {
+	sig := r.Header.Get("X-Lark-Signature")
+	if sig == "" {
+		return true
+	}
+	h := sha256.New()
+	h.Write([]byte(r.Header.Get("X-Lark-Request-Timestamp") + r.Header.Get("X-Lark-Request-Nonce") + f.cfg.EncryptKey))
+	h.Write(body)
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(h.Sum(nil))), []byte(sig)) == 1
+}
+
+// decrypt 解密事件：密钥为 Encrypt Key 的 SHA256，密文前 16 字节为 IV，AES-256-CBC + PKCS#7 填充
+func (f *Feishu) decrypt(s string) ([]byte, error) {
+	if f.cfg.EncryptKey == "" {
+		return nil, errors.New("feishu: Encrypt Key is not configured")
+	}
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(buf) < 2*aes.BlockSize || len(buf)%aes.BlockSize != 0 {
+		return nil, errInvalidEncrypt
+	}
+	key := sha256.Sum256([]byte(f.cfg.EncryptKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(buf)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, buf[:aes.BlockSize]).CryptBlocks(plain, buf[aes.BlockSize:])
+	n := int(plain[len(plain)-1])
+	if n < 1 || n > aes.BlockSize {
+		return nil, errInvalidEncrypt
+	}
+	return plain[:len(plain)-n], nil
+}
+
+// Send 私聊和群聊中直接发送消息，话题中回复用户的消息
+func (f *Feishu) Send(ctx context.Context, to *adapter.Message, text string) (string, error) {
+	content, err := json.Marshal(textContent{Text: text})
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		MessageID string `json:"message_id"`
+	}
+	if to.ThreadID != "" {
+		err = f.call(ctx, http.MethodPost, "/open-apis/im/v1/messages/"+to.ID+"/reply", map[string]interface{}{
+			"msg_type":        msgTypeText,
+			"content":         string(content),
+			"reply_in_thread": true,
+		}, &result)
+	} else {
+		err = f.call(ctx, http.MethodPost, "/open-apis/im/v1/messages?receive_id_type=chat_id", map[string]interface{}{
+			"receive_id": to.ChatID,
+			"msg_type":   msgTypeText,
+			"content":    string(content),
+		}, &result)
+	}
+	return result.MessageID, err
+}
+
+func (f *Feishu) Edit(ctx context.Context, to *adapter.Message, messageId, text string) error {
+	content, err := json.Marshal(textContent{Text: text})
+	if err != nil {
+		return err
+	}
+	return f.call(ctx, http.MethodPut, "/open-apis/im/v1/messages/"+messageId, map[string]interface{}{
+		"msg_type": msgTypeText,
+		"content":  string(content),
+	}, nil)
+}
+
+// tenantAccessToken 获取并缓存 tenant_access_token
+func (f *Feishu) tenantAccessToken(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.token != "" && time.Now().Before(f.expiresAt) {
+		return f.token, nil
+	}
+	body, err := json.Marshal(map[string]string{"app_id": f.cfg.AppID, "app_secret": f.cfg.AppSecret})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.baseURL+"/open-apis/auth/v3/tenant_access_token/internal", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	var result struct {
+		Code              int    `json:"code"`
+		Msg               string `json:"msg"`
+		TenantAccessToken string `json:"tenant_access_token"`
+		Expire            int    `json:"expire"`
+	}
+	if err := f.do(req, &result); err != nil {
+		return "", fmt.Errorf("get tenant access token failed: %w", err)
+	}
+	if result.Code != 0 {
+		return "", fmt.Errorf("get tenant access token failed: %d %s", result.Code, result.Msg)
+	}
+	// 提前一分钟刷新
+	f.token = result.TenantAccessToken
+	f.expiresAt = time.Now().Add(time.Duration(result.Expire)*time.Second - time.Minute)
+	return f.token, nil
+}
+
+func (f *Feishu) call(ctx context.Context, method, path string, params interface{}, v interface{}) error {
+	token, err := f.tenantAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, f.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+token)
+	var result struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := f.do(req, &result); err != nil {
+		return fmt.Errorf("feishu %s failed: %w", path, err)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("feishu %s failed: %d %s", path, result.Code, result.Msg)
+	}
+	if v != nil {
+		return json.Unmarshal(result.Data, v)
+	}
+	return nil
+}
+
+func (f *Feishu) do(req *http.Request, v interface{}) error {
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return nil
+}
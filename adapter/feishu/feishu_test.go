@@ -0,0 +1,190 @@
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/adapter"
+)
+
+const (
+	testToken      = "xxxxxx"
+	testEncryptKey = "kudryavka"
+)
+
+func newTestFeishu(t *testing.T) *Feishu {
+	t.Helper()
+	f, err := New(Config{AppID: "cli_9f5343c580712544", AppSecret: "secret", VerificationToken: testToken, EncryptKey: testEncryptKey, BotOpenID: "ou_bot0123456789"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func parse(f *Feishu, body []byte, header http.Header) (*adapter.Webhook, error) {
+	r := httptest.NewRequest(http.MethodPost, "/feishu", bytes.NewReader(body))
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	return f.Parse(r, body)
+}
+
+// encrypt 按飞书的方式加密事件并计算签名
+func encrypt(t *testing.T, plain []byte) ([]byte, http.Header) {
+	t.Helper()
+	key := sha256.Sum256([]byte(testEncryptKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := aes.BlockSize - len(plain)%aes.BlockSize
+	padded := append(append([]byte(nil), plain...), bytes.Repeat([]byte{byte(n)}, n)...)
+	buf := make([]byte, aes.BlockSize+len(padded))
+	copy(buf, "0123456789abcdef")
+	cipher.NewCBCEncrypter(block, buf[:aes.BlockSize]).CryptBlocks(buf[aes.BlockSize:], padded)
+	body, _ := json.Marshal(map[string]string{"encrypt": base64.StdEncoding.EncodeToString(buf)})
+
+	h := sha256.New()
+	h.Write([]byte("1608725989" + "nonce" + testEncryptKey))
+	h.Write(body)
+	header := http.Header{}
+	header.Set("X-Lark-Request-Timestamp", "1608725989")
+	header.Set("X-Lark-Request-Nonce", "nonce")
+	header.Set("X-Lark-Signature", hex.EncodeToString(h.Sum(nil)))
+	return body, header
+}
+
+func TestNewRequiresVerificationToken(t *testing.T) {
+	if _, err := New(Config{AppID: "cli", AppSecret: "secret"}); !errors.Is(err, ErrVerificationTokenRequired) {
+		t.Fatalf("New() error = %v, want ErrVerificationTokenRequired", err)
+	}
+}
+
+func TestParseURLVerification(t *testing.T) {
+	hook, err := parse(newTestFeishu(t), fixture(t, "url_verification.json"), nil)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	b, _ := json.Marshal(hook.Response)
+	if string(b) != `{"challenge":"ajls384kdjx98XX"}` {
+		t.Fatalf("Parse() response = %s", b)
+	}
+}
+
+func TestParseRejectsInvalidToken(t *testing.T) {
+	f, err := New(Config{VerificationToken: "other-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"url_verification.json", "p2p_message.json"} {
+		if _, err := parse(f, fixture(t, name), nil); !errors.Is(err, adapter.ErrInvalidSignature) {
+			t.Fatalf("Parse(%s) error = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	f := newTestFeishu(t)
+	tests := []struct {
+		fixture string
+		want    *adapter.Message
+	}{
+		{"p2p_message.json", &adapter.Message{ID: "om_5ce6d572455d361153b7cb51da133945", UserID: "ou_84aad35d084aa403a838cf73ee18467", ChatID: "oc_5ce6d572455d361153b7xx51da133945", Private: true, Text: "京都有哪些值得去的寺庙"}},
+		// 去掉 @机器人 的占位符
+		{"group_mention.json", &adapter.Message{ID: "om_6df7e683566e472264c8dc62eb244a56", UserID: "ou_84aad35d084aa403a838cf73ee18467", ChatID: "oc_7fa8b2e3c4d5e6f708192a3b4c5d6e7f", ThreadID: "omt_1a2b3c4d5e6f", Mentioned: true, Text: "明天去京都还是大阪？"}},
+		// 非文本消息不处理
+		{"image_message.json", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			hook, err := parse(f, fixture(t, tt.fixture), nil)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.want == nil {
+				if hook.Message != nil {
+					t.Fatalf("Parse() message = %+v, want nil", hook.Message)
+				}
+				return
+			}
+			if hook.Message == nil || *hook.Message != *tt.want {
+				t.Fatalf("Parse() message = %+v, want %+v", hook.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEncrypted(t *testing.T) {
+	f := newTestFeishu(t)
+	body, header := encrypt(t, fixture(t, "p2p_message.json"))
+
+	hook, err := parse(f, body, header)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if hook.Message == nil || hook.Message.Text != "京都有哪些值得去的寺庙" {
+		t.Fatalf("Parse() message = %+v", hook.Message)
+	}
+
+	header.Set("X-Lark-Signature", strings.Repeat("0", 64))
+	if _, err := parse(f, body, header); !errors.Is(err, adapter.ErrInvalidSignature) {
+		t.Fatalf("Parse() with invalid signature error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestSend(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/open-apis/auth/v3/tenant_access_token/internal" {
+			io.WriteString(w, `{"code":0,"msg":"ok","tenant_access_token":"t-g1044ghJRUIJJ5ELPPBIPZV6S","expire":7200}`)
+			return
+		}
+		calls = append(calls, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("Authorization")+" "+string(b))
+		io.WriteString(w, `{"code":0,"msg":"success","data":{"message_id":"om_dc13264520392913993dd051dba21dcf"}}`)
+	}))
+	defer srv.Close()
+
+	f := newTestFeishu(t).WithAPI(srv.URL, srv.Client())
+	ctx := context.Background()
+	if _, err := f.Send(ctx, &adapter.Message{ChatID: "oc_5ce6"}, "清水寺"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	// 话题中回复用户的消息
+	id, err := f.Send(ctx, &adapter.Message{ID: "om_6df7", ChatID: "oc_7fa8", ThreadID: "omt_1a2b"}, "去京都")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if id != "om_dc13264520392913993dd051dba21dcf" {
+		t.Fatalf("Send() = %q", id)
+	}
+	want := []string{
+		`POST /open-apis/im/v1/messages?receive_id_type=chat_id Bearer t-g1044ghJRUIJJ5ELPPBIPZV6S {"content":"{\"text\":\"清水寺\"}","msg_type":"text","receive_id":"oc_5ce6"}`,
+		`POST /open-apis/im/v1/messages/om_6df7/reply Bearer t-g1044ghJRUIJJ5ELPPBIPZV6S {"content":"{\"text\":\"去京都\"}","msg_type":"text","reply_in_thread":true}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+}
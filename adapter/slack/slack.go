@@ -0,0 +1,276 @@
+// Package slack 通过 Events API 和斜杠命令将 Slack 接入 xgpt3
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fanchunke/xgpt3/adapter"
+)
+
+const (
+	name              = "slack"
+	defaultBaseURL    = "https://slack.com/api"
+	defaultCommand    = "/xgpt3"
+	defaultMaxSkew    = 5 * time.Minute
+	maxMessageBytes   = 4000
+	channelTypeIM     = "im"
+	channelTypePublic = "channel"
+	channelTypeGroup  = "group"
+	directMessage     = "directmessage"
+	eventMessage      = "message"
+	eventAppMention   = "app_mention"
+	typeURLVerify     = "url_verification"
+	typeEventCallback = "event_callback"
+)
+
+// mention 消息中 @用户 的标记，例如 <@U012AB3CD>
+var mention = regexp.MustCompile(`<@[A-Z0-9]+(\|[^>]*)?>`)
+
+// Config Slack App 配置
+type Config struct {
+	// Bot User OAuth Token，以 xoxb- 开头
+	BotToken string
+	// 校验请求签名的 Signing Secret，必填
+	SigningSecret string
+	// 斜杠命令名称，默认为 /xgpt3。例如 /xgpt3 new 等同于发送 /new
+	Command string
+	// 机器人的用户Id。设置后频道中没有 @机器人 的消息也会推送给 Bot，用于群聊模式。
+	// @机器人 的消息同时会推送 app_mention 事件，频道消息事件中带有该 Id 的消息会被忽略
+	BotUserID string
+}
+
+// Slack 实现 adapter.Platform 和 adapter.Editor
+type Slack struct {
+	cfg        Config
+	baseURL    string
+	httpClient *http.Client
+	maxSkew    time.Duration
+}
+
+// ErrSigningSecretRequired 没有配置 SigningSecret 时任何人都可以用空密钥伪造签名
+var ErrSigningSecretRequired = errors.New("slack: SigningSecret is required")
+
+func New(cfg Config) (*Slack, error) {
+	if cfg.SigningSecret == "" {
+		return nil, ErrSigningSecretRequired
+	}
+	if cfg.Command == "" {
+		cfg.Command = defaultCommand
+	}
+	return &Slack{
+		cfg:        cfg,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+		maxSkew:    defaultMaxSkew,
+	}, nil
+}
+
+// WithAPI 设置 Slack 接口地址和 HTTP client，用于代理或测试
+func (s *Slack) WithAPI(baseURL string, client *http.Client) *Slack {
+	s.baseURL = strings.TrimRight(baseURL, "/")
+	s.httpClient = client
+	return s
+}
+
+// WithMaxSkew 设置请求时间戳允许的最大偏差，用于防止重放。为 0 时不校验，便于回放录制的推送
+func (s *Slack) WithMaxSkew(d time.Duration) *Slack {
+	s.maxSkew = d
+	return s
+}
+
+func (s *Slack) Name() string {
+	return name
+}
+
+func (s *Slack) MaxMessageBytes() int {
+	return maxMessageBytes
+}
+
+type envelope struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	EventID   string `json:"event_id"`
+	Event     event  `json:"event"`
+}
+
+type event struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype"`
+	BotID       string `json:"bot_id"`
+	User        string `json:"user"`
+	Text        string `json:"text"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type"`
+}
+
+func (s *Slack) Parse(r *http.Request, body []byte) (*adapter.Webhook, error) {
+	if !s.verify(r, body) {
+		return nil, adapter.ErrInvalidSignature
+	}
+
+	// 斜杠命令使用表单格式
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid slash command: %w", err)
+		}
+		return &adapter.Webhook{Message: s.commandMessage(form)}, nil
+	}
+
+	var e envelope
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	switch e.Type {
+	case typeURLVerify:
+		return &adapter.Webhook{Response: map[string]string{"challenge": e.Challenge}}, nil
+	case typeEventCallback:
+		return &adapter.Webhook{Message: s.eventMessage(e)}, nil
+	}
+	return &adapter.Webhook{}, nil
+}
+
+// verify 校验签名：v0= 加上 HMAC-SHA256(Signing Secret, "v0:时间戳:请求体")
+func (s *Slack) verify(r *http.Request, body []byte) bool {
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	if s.maxSkew > 0 {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false
+		}
+		if d := time.Since(time.Unix(ts, 0)); d > s.maxSkew || d < -s.maxSkew {
+			return false
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(s.cfg.SigningSecret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Slack-Signature")))
+}
+
+func (s *Slack) commandMessage(form url.Values) *adapter.Message {
+	text := strings.TrimSpace(form.Get("text"))
+	if form.Get("command") == s.cfg.Command {
+		text = "/" + text
+	} else {
+		text = form.Get("command") + " " + text
+	}
+	return &adapter.Message{
+		ID:      form.Get("trigger_id"),
+		UserID:  form.Get("user_id"),
+		ChatID:  form.Get("channel_id"),
+		Private: form.Get("channel_name") == directMessage,
+		Text:    text,
+	}
+}
+
+// eventMessage 处理私聊消息、频道中 @机器人 的消息，以及设置了 BotUserID 时频道中的其他消息。
+// 忽略机器人自己发送的消息和消息变更
+func (s *Slack) eventMessage(e envelope) *adapter.Message {
+	ev := e.Event
+	if ev.BotID != "" || ev.Subtype != "" || ev.User == "" {
+		return nil
+	}
+	msg := &adapter.Message{
+		ID:       e.EventID,
+		UserID:   ev.User,
+		ChatID:   ev.Channel,
+		ThreadID: ev.ThreadTS,
+		Text:     strings.TrimSpace(mention.ReplaceAllString(ev.Text, "")),
+	}
+	switch {
+	case ev.Type == eventMessage && ev.ChannelType == channelTypeIM:
+		msg.Private = true
+	case ev.Type == eventAppMention:
+		// 频道中的对话在话题中进行，每个话题是独立的会话
+		if msg.ThreadID == "" {
+			msg.ThreadID = ev.TS
+		}
+		msg.Mentioned = true
+	case ev.Type == eventMessage && (ev.ChannelType == channelTypePublic || ev.ChannelType == channelTypeGroup):
+		if s.cfg.BotUserID == "" || strings.Contains(ev.Text, "<@"+s.cfg.BotUserID) {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return msg
+}
+
+func (s *Slack) Send(ctx context.Context, to *adapter.Message, text string) (string, error) {
+	var result struct {
+		TS string `json:"ts"`
+	}
+	err := s.call(ctx, "chat.postMessage", map[string]string{
+		"channel":   to.ChatID,
+		"thread_ts": to.ThreadID,
+		"text":      text,
+	}, &result)
+	return result.TS, err
+}
+
+func (s *Slack) Edit(ctx context.Context, to *adapter.Message, messageId, text string) error {
+	return s.call(ctx, "chat.update", map[string]string{
+		"channel": to.ChatID,
+		"ts":      messageId,
+		"text":    text,
+	}, nil)
+}
+
+func (s *Slack) call(ctx context.Context, method string, params map[string]string, v interface{}) error {
+	for k, p := range params {
+		if p == "" {
+			delete(params, k)
+		}
+	}
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.cfg.BotToken)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return fmt.Errorf("slack %s failed: %s", method, resp.Status)
+	}
+	var status struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return err
+	}
+	if !status.OK {
+		return fmt.Errorf("slack %s failed: %s", method, status.Error)
+	}
+	if v != nil {
+		return json.Unmarshal(raw, v)
+	}
+	return nil
+}
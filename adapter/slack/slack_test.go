@@ -0,0 +1,160 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fanchunke/xgpt3/adapter"
+)
+
+const testSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+func newTestSlack(t *testing.T) *Slack {
+	t.Helper()
+	s, err := New(Config{BotToken: "xoxb-test", SigningSecret: testSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// signedRequest 按 Slack 的方式为推送签名
+func signedRequest(t *testing.T, fixture, contentType, secret string, timestamp time.Time) (*http.Request, []byte) {
+	t.Helper()
+	body, err := os.ReadFile("testdata/" + fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+	r := httptest.NewRequest(http.MethodPost, "/slack", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", contentType)
+	r.Header.Set("X-Slack-Request-Timestamp", ts)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return r, body
+}
+
+func TestNewRequiresSigningSecret(t *testing.T) {
+	if _, err := New(Config{BotToken: "xoxb-test"}); !errors.Is(err, ErrSigningSecretRequired) {
+		t.Fatalf("New() error = %v, want ErrSigningSecretRequired", err)
+	}
+}
+
+func TestParseRejectsInvalidSignature(t *testing.T) {
+	s := newTestSlack(t)
+
+	r, body := signedRequest(t, "im_message.json", "application/json", "wrong-secret", time.Now())
+	if _, err := s.Parse(r, body); !errors.Is(err, adapter.ErrInvalidSignature) {
+		t.Fatalf("Parse() with wrong secret error = %v, want ErrInvalidSignature", err)
+	}
+
+	// 过期的推送可能是重放
+	r, body = signedRequest(t, "im_message.json", "application/json", testSecret, time.Now().Add(-time.Hour))
+	if _, err := s.Parse(r, body); !errors.Is(err, adapter.ErrInvalidSignature) {
+		t.Fatalf("Parse() with stale timestamp error = %v, want ErrInvalidSignature", err)
+	}
+	r, body = signedRequest(t, "im_message.json", "application/json", testSecret, time.Now().Add(-time.Hour))
+	if _, err := s.WithMaxSkew(0).Parse(r, body); err != nil {
+		t.Fatalf("Parse() without skew check error = %v", err)
+	}
+}
+
+func TestParseURLVerification(t *testing.T) {
+	r, body := signedRequest(t, "url_verification.json", "application/json", testSecret, time.Now())
+	hook, err := newTestSlack(t).Parse(r, body)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	b, _ := json.Marshal(hook.Response)
+	if string(b) != `{"challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"}` {
+		t.Fatalf("Parse() response = %s", b)
+	}
+}
+
+func TestParse(t *testing.T) {
+	s := newTestSlack(t)
+	tests := []struct {
+		fixture     string
+		contentType string
+		want        *adapter.Message
+	}{
+		{"im_message.json", "application/json", &adapter.Message{ID: "Ev0PV52K21", UserID: "U061F7AUR", ChatID: "D024BE91L", Private: true, Text: "京都有哪些值得去的寺庙"}},
+		// 频道中 @机器人 的消息在话题中回复，去掉 @ 标记
+		{"app_mention.json", "application/json", &adapter.Message{ID: "Ev0LAN670R", UserID: "U061F7AUR", ChatID: "C0LAN2Q65", ThreadID: "1515449522.000016", Mentioned: true, Text: "明天去京都还是大阪？"}},
+		// 机器人自己发送的消息不处理
+		{"bot_message.json", "application/json", nil},
+		// 斜杠命令转换为对应的命令
+		{"slash_command.txt", "application/x-www-form-urlencoded", &adapter.Message{ID: "13345224609.738474920.8088930838d88f008e0", UserID: "U2147483697", ChatID: "C2147483705", Text: "/new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			r, body := signedRequest(t, tt.fixture, tt.contentType, testSecret, time.Now())
+			hook, err := s.Parse(r, body)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.want == nil {
+				if hook.Message != nil {
+					t.Fatalf("Parse() message = %+v, want nil", hook.Message)
+				}
+				return
+			}
+			if hook.Message == nil || *hook.Message != *tt.want {
+				t.Fatalf("Parse() message = %+v, want %+v", hook.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendAndEdit(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		calls = append(calls, r.URL.Path+" "+r.Header.Get("Authorization")+" "+string(b))
+		io.WriteString(w, `{"ok":true,"channel":"C0LAN2Q65","ts":"1515449530.000100","message":{"text":"去京都"}}`)
+	}))
+	defer srv.Close()
+
+	s := newTestSlack(t).WithAPI(srv.URL, srv.Client())
+	to := &adapter.Message{ChatID: "C0LAN2Q65", ThreadID: "1515449522.000016"}
+	ts, err := s.Send(context.Background(), to, "去")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := s.Edit(context.Background(), to, ts, "去京都"); err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	want := []string{
+		`/chat.postMessage Bearer xoxb-test {"channel":"C0LAN2Q65","text":"去","thread_ts":"1515449522.000016"}`,
+		`/chat.update Bearer xoxb-test {"channel":"C0LAN2Q65","text":"去京都","ts":"1515449530.000100"}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"ok":false,"error":"channel_not_found"}`)
+	}))
+	defer srv.Close()
+
+	_, err := newTestSlack(t).WithAPI(srv.URL, srv.Client()).Send(context.Background(), &adapter.Message{ChatID: "C1"}, "hi")
+	if err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("Send() error = %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package telegram 通过 Bot API 的 webhook 将 Telegram 接入 xgpt3
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/adapter"
+)
+
+const (
+	name            = "telegram"
+	defaultBaseURL  = "https://api.telegram.org"
+	maxMessageBytes = 4096
+	chatTypePrivate = "private"
+	secretHeader    = "X-Telegram-Bot-Api-Secret-Token"
+)
+
+// Config Telegram Bot 配置
+type Config struct {
+	// BotFather 生成的 Bot Token
+	Token string
+	// 调用 setWebhook 时设置的 secret_token，必填。没有 secret_token 时任何人都可以伪造推送
+	SecretToken string
+	// 机器人的用户名，群聊中用于识别 @机器人 的消息并去掉消息里的 @用户名。
+	// 为空时群聊中收到的消息都作为 @机器人 处理
+	Username string
+}
+
+// Telegram 实现 adapter.Platform 和 adapter.Editor
+type Telegram struct {
+	cfg        Config
+	baseURL    string
+	httpClient *http.Client
+}
+
+// ErrSecretTokenRequired 没有配置 SecretToken 时无法校验推送来自 Telegram
+var ErrSecretTokenRequired = errors.New("telegram: SecretToken is required")
+
+func New(cfg Config) (*Telegram, error) {
+	if cfg.SecretToken == "" {
+		return nil, ErrSecretTokenRequired
+	}
+	return &Telegram{
+		cfg:        cfg,
+		baseURL:    defaultBaseURL,
+		httpClient: http.DefaultClient,
+	}, nil
+}
+
+// WithAPI 设置 Bot API 地址和 HTTP client，用于自建的 Bot API 服务或测试
+func (t *Telegram) WithAPI(baseURL string, client *http.Client) *Telegram {
+	t.baseURL = strings.TrimRight(baseURL, "/")
+	t.httpClient = client
+	return t
+}
+
+func (t *Telegram) Name() string {
+	return name
+}
+
+func (t *Telegram) MaxMessageBytes() int {
+	return maxMessageBytes
+}
+
+type update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *message `json:"message"`
+}
+
+type message struct {
+	MessageID       int64  `json:"message_id"`
+	MessageThreadID int64  `json:"message_thread_id"`
+	IsTopicMessage  bool   `json:"is_topic_message"`
+	From            *user  `json:"from"`
+	Chat            chat   `json:"chat"`
+	Text            string `json:"text"`
+	// 话题中的消息同时带有话题的首条消息，只有回复机器人时才视为 @机器人
+	ReplyToMessage *struct {
+		MessageID int64 `json:"message_id"`
+		From      *user `json:"from"`
+	} `json:"reply_to_message"`
+}
+
+type user struct {
+	ID       int64  `json:"id"`
+	IsBot    bool   `json:"is_bot"`
+	Username string `json:"username"`
+}
+
+type chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
+}
+
+func (t *Telegram) Parse(r *http.Request, body []byte) (*adapter.Webhook, error) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretHeader)), []byte(t.cfg.SecretToken)) != 1 {
+		return nil, adapter.ErrInvalidSignature
+	}
+	var u update
+	if err := json.Unmarshal(body, &u); err != nil {
+		return nil, fmt.Errorf("invalid update: %w", err)
+	}
+
+	// 只处理用户发送的文本消息，忽略编辑消息、频道消息等其他更新
+	m := u.Message
+	if m == nil || m.From == nil || m.From.IsBot || m.Text == "" {
+		return &adapter.Webhook{}, nil
+	}
+	msg := &adapter.Message{
+		ID:      strconv.FormatInt(u.UpdateID, 10),
+		UserID:  strconv.FormatInt(m.From.ID, 10),
+		ChatID:  strconv.FormatInt(m.Chat.ID, 10),
+		Private: m.Chat.Type == chatTypePrivate,
+		Text:    m.Text,
+	}
+	// 开启话题的超级群组中，每个话题是独立的会话
+	if m.IsTopicMessage && m.MessageThreadID != 0 {
+		msg.ThreadID = strconv.FormatInt(m.MessageThreadID, 10)
+	}
+	msg.Mentioned = t.mentioned(m)
+	if t.cfg.Username != "" {
+		msg.Text = strings.TrimSpace(strings.ReplaceAll(msg.Text, "@"+t.cfg.Username, ""))
+	}
+	return &adapter.Webhook{Message: msg}, nil
+}
+
+// mentioned 消息是否 @机器人 或回复机器人的消息
+func (t *Telegram) mentioned(m *message) bool {
+	if t.cfg.Username == "" {
+		return true
+	}
+	if strings.Contains(m.Text, "@"+t.cfg.Username) {
+		return true
+	}
+	reply := m.ReplyToMessage
+	return reply != nil && reply.From != nil && reply.From.IsBot && strings.EqualFold(reply.From.Username, t.cfg.Username)
+}
+
+func (t *Telegram) Send(ctx context.Context, to *adapter.Message, text string) (string, error) {
+	params := map[string]interface{}{"chat_id": to.ChatID, "text": text}
+	if to.ThreadID != "" {
+		params["message_thread_id"] = to.ThreadID
+	}
+	var result struct {
+		MessageID int64 `json:"message_id"`
+	}
+	if err := t.call(ctx, "sendMessage", params, &result); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(result.MessageID, 10), nil
+}
+
+func (t *Telegram) Edit(ctx context.Context, to *adapter.Message, messageId, text string) error {
+	return t.call(ctx, "editMessageText", map[string]interface{}{
+		"chat_id":    to.ChatID,
+		"message_id": messageId,
+		"text":       text,
+	}, nil)
+}
+
+func (t *Telegram) call(ctx context.Context, method string, params map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/bot%s/%s", t.baseURL, t.cfg.Token, method), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		// 请求地址包含 Token，不能直接输出 *url.Error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram %s failed: %s", method, resp.Status)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram %s failed: %s", method, result.Description)
+	}
+	if v != nil {
+		return json.Unmarshal(result.Result, v)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/adapter"
+)
+
+const testSecret = "xgpt3-secret"
+
+func newTestTelegram(t *testing.T) *Telegram {
+	t.Helper()
+	tg, err := New(Config{Token: "123:abc", SecretToken: testSecret, Username: "xgpt3_bot"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tg
+}
+
+func parseFixture(t *testing.T, tg *Telegram, name, secret string) (*adapter.Webhook, error) {
+	t.Helper()
+	body, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(string(body)))
+	if secret != "" {
+		r.Header.Set(secretHeader, secret)
+	}
+	return tg.Parse(r, body)
+}
+
+func TestNewRequiresSecretToken(t *testing.T) {
+	if _, err := New(Config{Token: "123:abc"}); !errors.Is(err, ErrSecretTokenRequired) {
+		t.Fatalf("New() error = %v, want ErrSecretTokenRequired", err)
+	}
+}
+
+func TestParseRejectsInvalidSecret(t *testing.T) {
+	tg := newTestTelegram(t)
+	for _, secret := range []string{"", "wrong-secret"} {
+		if _, err := parseFixture(t, tg, "private_message.json", secret); !errors.Is(err, adapter.ErrInvalidSignature) {
+			t.Fatalf("Parse() with secret %q error = %v, want ErrInvalidSignature", secret, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tg := newTestTelegram(t)
+	tests := []struct {
+		fixture string
+		want    *adapter.Message
+	}{
+		{"private_message.json", &adapter.Message{ID: "815239471", UserID: "184203817", ChatID: "184203817", Private: true, Text: "京都有哪些值得去的寺庙"}},
+		// 话题中的消息带有话题的首条消息，@机器人 后去掉用户名
+		{"group_mention.json", &adapter.Message{ID: "815239472", UserID: "184203817", ChatID: "-1001834729341", ThreadID: "1024", Mentioned: true, Text: "明天去京都还是大阪？"}},
+		// 回复话题首条消息不算 @机器人
+		{"group_message.json", &adapter.Message{ID: "815239473", UserID: "927461033", ChatID: "-1001834729341", ThreadID: "1024", Text: "我想去大阪"}},
+		// 编辑消息不处理
+		{"edited_message.json", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			hook, err := parseFixture(t, tg, tt.fixture, testSecret)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if tt.want == nil {
+				if hook.Message != nil {
+					t.Fatalf("Parse() message = %+v, want nil", hook.Message)
+				}
+				return
+			}
+			if hook.Message == nil || *hook.Message != *tt.want {
+				t.Fatalf("Parse() message = %+v, want %+v", hook.Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestSend(t *testing.T) {
+	var path string
+	var params map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &params)
+		io.WriteString(w, `{"ok":true,"result":{"message_id":1189,"chat":{"id":-1001834729341,"type":"supergroup"},"date":1700000100,"text":"去京都"}}`)
+	}))
+	defer srv.Close()
+
+	tg := newTestTelegram(t).WithAPI(srv.URL, srv.Client())
+	id, err := tg.Send(context.Background(), &adapter.Message{ChatID: "-1001834729341", ThreadID: "1024"}, "去京都")
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if id != "1189" || path != "/bot123:abc/sendMessage" {
+		t.Fatalf("Send() = %q, path = %q", id, path)
+	}
+	if params["chat_id"] != "-1001834729341" || params["message_thread_id"] != "1024" || params["text"] != "去京都" {
+		t.Fatalf("sendMessage params = %v", params)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	}))
+	defer srv.Close()
+
+	tg := newTestTelegram(t).WithAPI(srv.URL, srv.Client())
+	_, err := tg.Send(context.Background(), &adapter.Message{ChatID: "1"}, "hi")
+	if err == nil || !strings.Contains(err.Error(), "chat not found") || strings.Contains(err.Error(), "123:abc") {
+		t.Fatalf("Send() error = %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package wechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const defaultAPIBaseURL = "https://api.weixin.qq.com"
+
+// access_token 无效或过期的错误码
+const (
+	errCodeInvalidToken = 40001
+	errCodeExpiredToken = 42001
+)
+
+// APIError 微信接口返回的错误
+type APIError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("wechat api error %d: %s", e.ErrCode, e.ErrMsg)
+}
+
+// apiClient 调用公众号接口，自动获取和刷新 access_token
+type apiClient struct {
+	baseURL    string
+	appId      string
+	secret     string
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (a *apiClient) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && time.Now().Before(a.expiresAt) {
+		return a.token, nil
+	}
+	q := url.Values{"grant_type": {"client_credential"}, "appid": {a.appId}, "secret": {a.secret}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+"/cgi-bin/token?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		APIError
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := a.do(req, &result); err != nil {
+		return "", fmt.Errorf("get access token failed: %w", err)
+	}
+	if result.ErrCode != 0 {
+		return "", fmt.Errorf("get access token failed: %w", &result.APIError)
+	}
+	// 提前一分钟刷新
+	a.token = result.AccessToken
+	a.expiresAt = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return a.token, nil
+}
+
+func (a *apiClient) resetToken(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token == token {
+		a.token = ""
+	}
+}
+
+// sendText 通过客服消息接口发送文本消息。access_token 失效时刷新后重试一次
+func (a *apiClient) sendText(ctx context.Context, openId, content string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"touser":  openId,
+		"msgtype": msgTypeText,
+		"text":    map[string]string{"content": content},
+	})
+	if err != nil {
+		return err
+	}
+
+	for retry := 0; ; retry++ {
+		token, err := a.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/cgi-bin/message/custom/send?access_token="+url.QueryEscape(token), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		var result APIError
+		if err := a.do(req, &result); err != nil {
+			return fmt.Errorf("send custom message failed: %w", err)
+		}
+		if (result.ErrCode == errCodeInvalidToken || result.ErrCode == errCodeExpiredToken) && retry == 0 {
+			a.resetToken(token)
+			continue
+		}
+		if result.ErrCode != 0 {
+			return fmt.Errorf("send custom message failed: %w", &result)
+		}
+		return nil
+	}
+}
+
+func (a *apiClient) do(req *http.Request, v interface{}) error {
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,99 @@
+package wechat
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 微信消息加解密使用 32 字节的块大小做 PKCS#7 填充
+const paddingBlockSize = 32
+
+var errInvalidMessage = errors.New("wechat: invalid encrypted message")
+
+// signature 计算签名：参数按字典序排序后拼接，再计算 SHA1
+func signature(parts ...string) string {
+	sorted := append([]string(nil), parts...)
+	sort.Strings(sorted)
+	h := sha1.Sum([]byte(strings.Join(sorted, "")))
+	return hex.EncodeToString(h[:])
+}
+
+// msgCipher 安全模式下的消息加解密。
+// 明文格式为 16 字节随机数 + 4 字节消息长度 (网络字节序) + 消息 + AppID，使用 AES-256-CBC 加密，IV 为密钥的前 16 字节
+type msgCipher struct {
+	key   []byte
+	appId string
+}
+
+func newMsgCipher(encodingAESKey, appId string) (*msgCipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("wechat: invalid EncodingAESKey")
+	}
+	return &msgCipher{key: key, appId: appId}, nil
+}
+
+func (c *msgCipher) encrypt(msg []byte) (string, error) {
+	var buf bytes.Buffer
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	buf.Write(random)
+	binary.Write(&buf, binary.BigEndian, uint32(len(msg)))
+	buf.Write(msg)
+	buf.WriteString(c.appId)
+
+	n := paddingBlockSize - buf.Len()%paddingBlockSize
+	buf.Write(bytes.Repeat([]byte{byte(n)}, n))
+
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return "", err
+	}
+	plain := buf.Bytes()
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, c.key[:aes.BlockSize]).CryptBlocks(encrypted, plain)
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func (c *msgCipher) decrypt(s string) ([]byte, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return nil, errInvalidMessage
+	}
+	block, err := aes.NewCipher(c.key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, c.key[:aes.BlockSize]).CryptBlocks(plain, encrypted)
+
+	n := int(plain[len(plain)-1])
+	if n < 1 || n > paddingBlockSize || n > len(plain) {
+		return nil, errInvalidMessage
+	}
+	plain = plain[:len(plain)-n]
+	if len(plain) < 20 {
+		return nil, errInvalidMessage
+	}
+	l := int(binary.BigEndian.Uint32(plain[16:20]))
+	if l > len(plain)-20 {
+		return nil, errInvalidMessage
+	}
+	msg, appId := plain[20:20+l], string(plain[20+l:])
+	if c.appId != "" && appId != c.appId {
+		return nil, fmt.Errorf("wechat: unexpected appid %s", appId)
+	}
+	return msg, nil
+}
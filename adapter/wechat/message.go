@@ -0,0 +1,68 @@
+package wechat
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// 消息类型
+const (
+	msgTypeText  = "text"
+	msgTypeVoice = "voice"
+	msgTypeEvent = "event"
+
+	eventSubscribe   = "subscribe"
+	eventUnsubscribe = "unsubscribe"
+)
+
+// message 微信推送的消息和事件
+type message struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   string   `xml:"ToUserName"`
+	FromUserName string   `xml:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime"`
+	MsgType      string   `xml:"MsgType"`
+	MsgId        int64    `xml:"MsgId"`
+	// 文本消息内容
+	Content string `xml:"Content"`
+	// 语音消息。开启语音识别后 Recognition 为识别结果
+	MediaId     string `xml:"MediaId"`
+	Format      string `xml:"Format"`
+	Recognition string `xml:"Recognition"`
+	// 事件类型及参数
+	Event    string `xml:"Event"`
+	EventKey string `xml:"EventKey"`
+	// 安全模式下的密文
+	Encrypt string `xml:"Encrypt"`
+}
+
+// key 消息的去重标识。普通消息使用 MsgId，事件使用 FromUserName + CreateTime
+func (m *message) key() string {
+	if m.MsgId != 0 {
+		return strconv.FormatInt(m.MsgId, 10)
+	}
+	return m.FromUserName + ":" + strconv.FormatInt(m.CreateTime, 10)
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// textReply 被动回复的文本消息
+type textReply struct {
+	XMLName      xml.Name `xml:"xml"`
+	ToUserName   cdata    `xml:"ToUserName"`
+	FromUserName cdata    `xml:"FromUserName"`
+	CreateTime   int64    `xml:"CreateTime"`
+	MsgType      cdata    `xml:"MsgType"`
+	Content      cdata    `xml:"Content"`
+}
+
+// encryptedReply 安全模式下的被动回复
+type encryptedReply struct {
+	XMLName      xml.Name `xml:"xml"`
+	Encrypt      cdata    `xml:"Encrypt"`
+	MsgSignature cdata    `xml:"MsgSignature"`
+	TimeStamp    string   `xml:"TimeStamp"`
+	Nonce        cdata    `xml:"Nonce"`
+}
@@ -0,0 +1,377 @@
+// Package wechat 将微信公众号接入 xgpt3：处理服务器配置中的消息推送 (签名校验、安全模式加解密、文本和语音消息、关注事件)，
+// 以粉丝的 OpenID 作为用户Id 发起对话。5 秒内没有生成回复时改用客服消息接口异步回复，超长的回复会拆成多条消息发送
+package wechat
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/adapter"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	name                   = "wechat"
+	defaultChannel         = name
+	defaultModel           = openai.GPT3Dot5Turbo
+	defaultReplyTimeout    = 4500 * time.Millisecond
+	defaultGenerateTimeout = 2 * time.Minute
+	defaultMaxMessageBytes = 2048
+	defaultErrorReply      = "服务繁忙，请稍后再试"
+	defaultUnsupported     = "暂不支持该类型的消息"
+	defaultMaxSkew         = 5 * time.Minute
+	maxBodyBytes           = 1 << 20
+)
+
+// Client 发起对话和关闭会话，*xgpt3.Client 满足该接口
+type Client interface {
+	CreateChatCompletionWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (openai.ChatCompletionResponse, error)
+	CloseConversationWithChannel(ctx context.Context, userId, channel string) error
+}
+
+// Config 公众号配置
+type Config struct {
+	// 服务器配置中的 Token
+	Token string
+	// 消息加解密密钥，为空时使用明文模式
+	EncodingAESKey string
+	// 公众号 AppID
+	AppID string
+	// 公众号 AppSecret，用于调用客服消息接口。为空时无法异步回复，超时和超长的回复会被丢弃
+	AppSecret string
+	// 消息渠道，默认为 wechat
+	Channel string
+	// 对话使用的模型
+	Model string
+	// 关注公众号时的欢迎语，为空时不回复
+	Welcome string
+	// 生成回复失败时的提示
+	ErrorReply string
+	// 不支持的消息类型 (图片、未开启语音识别的语音等) 的提示
+	Unsupported string
+}
+
+// Handler 处理公众号的消息推送
+type Handler struct {
+	client          Client
+	cfg             Config
+	cipher          *msgCipher
+	api             *apiClient
+	logger          zerolog.Logger
+	replyTimeout    time.Duration
+	generateTimeout time.Duration
+	maxMessageBytes int
+	maxSkew         time.Duration
+
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// ErrTokenRequired 没有配置 Token 时任何人都可以伪造签名
+var ErrTokenRequired = errors.New("wechat: Token is required")
+
+func New(client Client, cfg Config) (*Handler, error) {
+	if cfg.Token == "" {
+		return nil, ErrTokenRequired
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = defaultChannel
+	}
+	if cfg.Model == "" {
+		cfg.Model = defaultModel
+	}
+	if cfg.ErrorReply == "" {
+		cfg.ErrorReply = defaultErrorReply
+	}
+	if cfg.Unsupported == "" {
+		cfg.Unsupported = defaultUnsupported
+	}
+	h := &Handler{
+		client:          client,
+		cfg:             cfg,
+		logger:          log.Logger,
+		replyTimeout:    defaultReplyTimeout,
+		generateTimeout: defaultGenerateTimeout,
+		maxMessageBytes: defaultMaxMessageBytes,
+		maxSkew:         defaultMaxSkew,
+		pending:         make(map[string]bool),
+	}
+	if cfg.EncodingAESKey != "" {
+		c, err := newMsgCipher(cfg.EncodingAESKey, cfg.AppID)
+		if err != nil {
+			return nil, err
+		}
+		h.cipher = c
+	}
+	if cfg.AppID != "" && cfg.AppSecret != "" {
+		h.api = &apiClient{baseURL: defaultAPIBaseURL, appId: cfg.AppID, secret: cfg.AppSecret, httpClient: http.DefaultClient}
+	}
+	return h, nil
+}
+
+func (h *Handler) WithLogger(l zerolog.Logger) *Handler {
+	h.logger = l
+	return h
+}
+
+// WithReplyTimeout 设置被动回复的等待时间，超时后改用客服消息接口回复。微信要求 5 秒内响应
+func (h *Handler) WithReplyTimeout(d time.Duration) *Handler {
+	h.replyTimeout = d
+	return h
+}
+
+// WithGenerateTimeout 设置生成回复的最长时间
+func (h *Handler) WithGenerateTimeout(d time.Duration) *Handler {
+	h.generateTimeout = d
+	return h
+}
+
+// WithMaxMessageBytes 设置单条消息的最大字节数，超过时拆成多条发送
+func (h *Handler) WithMaxMessageBytes(n int) *Handler {
+	h.maxMessageBytes = n
+	return h
+}
+
+// WithMaxSkew 设置请求时间戳允许的最大偏差，用于防止重放。为 0 时不校验，便于回放录制的推送
+func (h *Handler) WithMaxSkew(d time.Duration) *Handler {
+	h.maxSkew = d
+	return h
+}
+
+// WithAPI 设置微信接口地址和 HTTP client，用于代理或测试
+func (h *Handler) WithAPI(baseURL string, client *http.Client) *Handler {
+	if h.api != nil {
+		h.api.baseURL = strings.TrimRight(baseURL, "/")
+		h.api.httpClient = client
+	}
+	return h
+}
+
+// Name 平台名称，Handler 满足 adapter.Adapter
+func (h *Handler) Name() string {
+	return name
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if !h.verify(q, q.Get("signature")) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		// 服务器地址验证
+		io.WriteString(w, q.Get("echostr"))
+	case http.MethodPost:
+		h.handlePush(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handlePush(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+	var msg message
+	if err := xml.Unmarshal(body, &msg); err != nil {
+		http.Error(w, "invalid message", http.StatusBadRequest)
+		return
+	}
+
+	encrypted := q.Get("encrypt_type") == "aes"
+	if encrypted {
+		if h.cipher == nil {
+			http.Error(w, "EncodingAESKey is not configured", http.StatusBadRequest)
+			return
+		}
+		if !h.verify(q, q.Get("msg_signature"), msg.Encrypt) {
+			http.Error(w, "invalid signature", http.StatusForbidden)
+			return
+		}
+		plain, err := h.cipher.decrypt(msg.Encrypt)
+		if err != nil {
+			http.Error(w, "decrypt message failed", http.StatusBadRequest)
+			return
+		}
+		msg = message{}
+		if err := xml.Unmarshal(plain, &msg); err != nil {
+			http.Error(w, "invalid message", http.StatusBadRequest)
+			return
+		}
+	}
+
+	content := h.handleMessage(r.Context(), &msg)
+	if content == "" {
+		// 不需要被动回复时返回 success，微信不会重试
+		io.WriteString(w, "success")
+		return
+	}
+	b, err := xml.Marshal(textReply{
+		ToUserName:   cdata{msg.FromUserName},
+		FromUserName: cdata{msg.ToUserName},
+		CreateTime:   time.Now().Unix(),
+		MsgType:      cdata{msgTypeText},
+		Content:      cdata{content},
+	})
+	if err == nil && encrypted {
+		b, err = h.encryptReply(b, q.Get("nonce"))
+	}
+	if err != nil {
+		h.logger.Warn().Msgf("Build wechat reply failed: %s", err)
+		io.WriteString(w, "success")
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write(b)
+}
+
+// verify 校验时间戳和签名，签名由 Token、timestamp、nonce 以及 extra 计算
+func (h *Handler) verify(q url.Values, sig string, extra ...string) bool {
+	timestamp := q.Get("timestamp")
+	if h.maxSkew > 0 {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false
+		}
+		if d := time.Since(time.Unix(ts, 0)); d > h.maxSkew || d < -h.maxSkew {
+			return false
+		}
+	}
+	expected := signature(append([]string{h.cfg.Token, timestamp, q.Get("nonce")}, extra...)...)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(sig)) == 1
+}
+
+func (h *Handler) encryptReply(b []byte, nonce string) ([]byte, error) {
+	encrypted, err := h.cipher.encrypt(b)
+	if err != nil {
+		return nil, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return xml.Marshal(encryptedReply{
+		Encrypt:      cdata{encrypted},
+		MsgSignature: cdata{signature(h.cfg.Token, timestamp, nonce, encrypted)},
+		TimeStamp:    timestamp,
+		Nonce:        cdata{nonce},
+	})
+}
+
+// handleMessage 处理消息并返回被动回复的内容，为空时不回复
+func (h *Handler) handleMessage(ctx context.Context, msg *message) string {
+	switch msg.MsgType {
+	case msgTypeText:
+		return h.chat(msg, msg.Content)
+	case msgTypeVoice:
+		if msg.Recognition == "" {
+			return h.cfg.Unsupported
+		}
+		return h.chat(msg, msg.Recognition)
+	case msgTypeEvent:
+		switch msg.Event {
+		case eventSubscribe:
+			return h.cfg.Welcome
+		case eventUnsubscribe:
+			if err := h.client.CloseConversationWithChannel(ctx, msg.FromUserName, h.cfg.Channel); err != nil {
+				h.logger.Warn().Msgf("Close wechat conversation of %s failed: %s", msg.FromUserName, err)
+			}
+		}
+		return ""
+	default:
+		return h.cfg.Unsupported
+	}
+}
+
+// chat 在后台生成回复。在等待时间内生成完成时被动回复第一段，否则通过客服消息接口发送
+func (h *Handler) chat(msg *message, content string) string {
+	key := msg.key()
+	// 微信重试的消息由首次请求负责回复
+	if !h.begin(key) {
+		return ""
+	}
+
+	done := make(chan []string, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), h.generateTimeout)
+		defer cancel()
+		done <- h.generate(xgpt3.WithIdempotencyKey(ctx, key), msg.FromUserName, content)
+	}()
+
+	timer := time.NewTimer(h.replyTimeout)
+	defer timer.Stop()
+	select {
+	case parts := <-done:
+		go h.send(key, msg.FromUserName, parts[1:])
+		return parts[0]
+	case <-timer.C:
+		h.logger.Debug().Msgf("Wechat reply to %s timed out, fallback to custom message", msg.FromUserName)
+		go func() { h.send(key, msg.FromUserName, <-done) }()
+		return ""
+	}
+}
+
+func (h *Handler) generate(ctx context.Context, user, content string) []string {
+	resp, err := h.client.CreateChatCompletionWithChannel(ctx, openai.ChatCompletionRequest{
+		Model:    h.cfg.Model,
+		User:     user,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: content}},
+	}, h.cfg.Channel)
+	reply := h.cfg.ErrorReply
+	if err != nil {
+		h.logger.Warn().Msgf("Wechat chat completion for %s failed: %s", user, err)
+	} else if len(resp.Choices) > 0 {
+		reply = resp.Choices[0].Message.Content
+	}
+	return adapter.SplitText(reply, h.maxMessageBytes)
+}
+
+// send 通过客服消息接口依次发送消息，发送完成后才接受同一消息的重试
+func (h *Handler) send(key, user string, parts []string) {
+	defer h.end(key)
+	if len(parts) == 0 {
+		return
+	}
+	if h.api == nil {
+		h.logger.Warn().Msgf("Wechat AppSecret is not configured, drop %d messages to %s", len(parts), user)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.generateTimeout)
+	defer cancel()
+	for _, p := range parts {
+		if err := h.api.sendText(ctx, user, p); err != nil {
+			h.logger.Warn().Msgf("Send wechat custom message to %s failed: %s", user, err)
+			return
+		}
+	}
+}
+
+func (h *Handler) begin(key string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.pending[key] {
+		return false
+	}
+	h.pending[key] = true
+	return true
+}
+
+func (h *Handler) end(key string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.pending, key)
+}
@@ -0,0 +1,181 @@
+package wechat
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	testToken  = "xgpt3-token"
+	testAESKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	testAppID  = "wx5823bf96d3bd56c7"
+	testOpenID = "oE3Kx5Q0dVZb1m2Nw-8rTf6hYp4A"
+)
+
+type fakeClient struct {
+	mu       sync.Mutex
+	requests []openai.ChatCompletionRequest
+	closed   []string
+}
+
+func (c *fakeClient) CreateChatCompletionWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (openai.ChatCompletionResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, request)
+	return openai.ChatCompletionResponse{Choices: []openai.ChatCompletionChoice{{
+		Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: "清水寺和金阁寺"},
+	}}}, nil
+}
+
+func (c *fakeClient) CloseConversationWithChannel(ctx context.Context, userId, channel string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = append(c.closed, userId+"@"+channel)
+	return nil
+}
+
+func fixture(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// signedURL 按微信的方式为请求签名
+func signedURL(timestamp time.Time, extra url.Values) string {
+	q := url.Values{}
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	q.Set("timestamp", ts)
+	q.Set("nonce", "1320562132")
+	q.Set("signature", signature(testToken, ts, "1320562132"))
+	for k, v := range extra {
+		q[k] = v
+	}
+	return "/wechat?" + q.Encode()
+}
+
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func TestNewRequiresToken(t *testing.T) {
+	if _, err := New(&fakeClient{}, Config{}); !errors.Is(err, ErrTokenRequired) {
+		t.Fatalf("New() error = %v, want ErrTokenRequired", err)
+	}
+}
+
+func TestVerifyURL(t *testing.T) {
+	h, err := New(&fakeClient{}, Config{Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	echo := url.Values{"echostr": {"5837397520767295"}}
+
+	w := serve(h, http.MethodGet, signedURL(time.Now(), echo), "")
+	if w.Code != http.StatusOK || w.Body.String() != "5837397520767295" {
+		t.Fatalf("verify url = %d %q", w.Code, w.Body.String())
+	}
+
+	forged := signedURL(time.Now(), url.Values{"echostr": {"1"}, "signature": {signature("guess", "0", "0")}})
+	if w := serve(h, http.MethodGet, forged, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("forged signature status = %d, want 403", w.Code)
+	}
+
+	// 签名正确但时间戳过期的请求可能是重放
+	stale := signedURL(time.Now().Add(-time.Hour), echo)
+	if w := serve(h, http.MethodGet, stale, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("stale timestamp status = %d, want 403", w.Code)
+	}
+	if w := serve(h.WithMaxSkew(0), http.MethodGet, stale, ""); w.Code != http.StatusOK {
+		t.Fatalf("stale timestamp without skew check status = %d, want 200", w.Code)
+	}
+}
+
+func TestTextMessage(t *testing.T) {
+	client := &fakeClient{}
+	h, err := New(client, Config{Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := serve(h, http.MethodPost, signedURL(time.Now(), nil), fixture(t, "text.xml"))
+	var reply textReply
+	if err := xml.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("parse reply %q failed: %v", w.Body.String(), err)
+	}
+	if reply.ToUserName.Value != testOpenID || reply.Content.Value != "清水寺和金阁寺" {
+		t.Fatalf("reply = %+v", reply)
+	}
+	if len(client.requests) != 1 || client.requests[0].User != testOpenID || client.requests[0].Messages[0].Content != "京都有哪些值得去的寺庙" {
+		t.Fatalf("requests = %+v", client.requests)
+	}
+}
+
+func TestEncryptedMessage(t *testing.T) {
+	client := &fakeClient{}
+	h, err := New(client, Config{Token: testToken, EncodingAESKey: testAESKey, AppID: testAppID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := h.cipher.encrypt([]byte(fixture(t, "text.xml")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := "<xml><ToUserName><![CDATA[gh_3f5e1a2b7c9d]]></ToUserName><Encrypt><![CDATA[" + encrypted + "]]></Encrypt></xml>"
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	q := url.Values{"encrypt_type": {"aes"}, "msg_signature": {signature(testToken, ts, "1320562132", encrypted)}}
+
+	// 消息签名错误
+	bad := url.Values{"encrypt_type": {"aes"}, "msg_signature": {signature(testToken, ts, "1320562132", "other")}}
+	if w := serve(h, http.MethodPost, signedURL(time.Now(), bad), body); w.Code != http.StatusForbidden {
+		t.Fatalf("invalid msg_signature status = %d, want 403", w.Code)
+	}
+
+	w := serve(h, http.MethodPost, signedURL(time.Now(), q), body)
+	var reply encryptedReply
+	if err := xml.Unmarshal(w.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("parse reply %q failed: %v", w.Body.String(), err)
+	}
+	if reply.MsgSignature.Value != signature(testToken, reply.TimeStamp, reply.Nonce.Value, reply.Encrypt.Value) {
+		t.Fatal("reply signature mismatch")
+	}
+	plain, err := h.cipher.decrypt(reply.Encrypt.Value)
+	if err != nil {
+		t.Fatalf("decrypt reply failed: %v", err)
+	}
+	if !strings.Contains(string(plain), "清水寺和金阁寺") {
+		t.Fatalf("decrypted reply = %s", plain)
+	}
+}
+
+func TestUnsubscribeClosesConversation(t *testing.T) {
+	client := &fakeClient{}
+	h, err := New(client, Config{Token: testToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := serve(h, http.MethodPost, signedURL(time.Now(), nil), fixture(t, "unsubscribe.xml"))
+	if body, _ := io.ReadAll(w.Body); string(body) != "success" {
+		t.Fatalf("response = %q, want success", body)
+	}
+	if len(client.closed) != 1 || client.closed[0] != testOpenID+"@wechat" {
+		t.Fatalf("closed = %v", client.closed)
+	}
+}
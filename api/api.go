@@ -0,0 +1,318 @@
+// Package api 提供会话管理的 HTTP 接口，包括会话列表、消息分页、关闭、重命名、删除和导出。
+//
+// 接口说明见 openapi.yaml。
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	// 导出时每次查询的消息数
+	exportBatch = 500
+)
+
+type Handler struct {
+	ch   conversation.Handler
+	auth Authenticator
+}
+
+// New 创建会话管理接口。ch 需要实现 conversation.SessionManager，否则管理类接口返回 501
+func New(ch conversation.Handler, auth Authenticator) *Handler {
+	return &Handler{ch: ch, auth: auth}
+}
+
+type sessionList struct {
+	Sessions   []*conversation.Session `json:"sessions"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+type messageList struct {
+	Messages   []*conversation.Message `json:"messages"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+type sessionExport struct {
+	Session  *conversation.Session   `json:"session"`
+	Messages []*conversation.Message `json:"messages"`
+}
+
+type renameRequest struct {
+	Title string `json:"title"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP 路由：
+//
+//	GET    /sessions
+//	GET    /sessions/{id}
+//	PATCH  /sessions/{id}
+//	DELETE /sessions/{id}
+//	POST   /sessions/{id}/close
+//	GET    /sessions/{id}/messages
+//	GET    /sessions/{id}/export
+//	GET    /openapi.yaml
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/openapi.yaml" {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(openAPI)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if parts[0] != "sessions" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	userId, err := h.auth(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	sm, ok := h.ch.(conversation.SessionManager)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, "conversation handler does not support session management")
+		return
+	}
+
+	if len(parts) == 1 {
+		route(w, r, http.MethodGet, func() { h.listSessions(w, r, sm, userId) })
+		return
+	}
+
+	sessionId, err := strconv.Atoi(parts[1])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			h.getSession(w, r, sm, userId, sessionId)
+		case http.MethodPatch:
+			h.renameSession(w, r, sm, userId, sessionId)
+		case http.MethodDelete:
+			h.deleteSession(w, r, sm, userId, sessionId)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	switch parts[2] {
+	case "close":
+		route(w, r, http.MethodPost, func() { h.closeSession(w, r, sm, userId, sessionId) })
+	case "messages":
+		route(w, r, http.MethodGet, func() { h.listMessages(w, r, sm, userId, sessionId) })
+	case "export":
+		route(w, r, http.MethodGet, func() { h.exportSession(w, r, sm, userId, sessionId) })
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func route(w http.ResponseWriter, r *http.Request, method string, handle func()) {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	handle()
+}
+
+func (h *Handler) listSessions(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string) {
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sessions, err := sm.ListSessions(r.Context(), userId, page)
+	if err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+
+	result := sessionList{Sessions: sessions}
+	if len(sessions) == page.Limit {
+		last := sessions[len(sessions)-1]
+		result.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) getSession(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string, sessionId int) {
+	session, err := sm.GetSession(r.Context(), userId, sessionId)
+	if err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+func (h *Handler) renameSession(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string, sessionId int) {
+	var req renameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+	if err := sm.RenameSession(r.Context(), userId, sessionId, req.Title); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	h.getSession(w, r, sm, userId, sessionId)
+}
+
+func (h *Handler) deleteSession(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string, sessionId int) {
+	if err := sm.DeleteSession(r.Context(), userId, sessionId); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) closeSession(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string, sessionId int) {
+	if err := sm.CloseSessionByID(r.Context(), userId, sessionId); err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	h.getSession(w, r, sm, userId, sessionId)
+}
+
+func (h *Handler) listMessages(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string, sessionId int) {
+	page, err := parsePage(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	session, err := sm.GetSession(r.Context(), userId, sessionId)
+	if err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	messages, err := sm.ListMessages(r.Context(), session, page)
+	if err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+
+	result := messageList{Messages: messages}
+	if len(messages) == page.Limit {
+		last := messages[len(messages)-1]
+		result.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// exportSession 导出会话的全部消息，format 支持 json (默认) 和 markdown
+func (h *Handler) exportSession(w http.ResponseWriter, r *http.Request, sm conversation.SessionManager, userId string, sessionId int) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported format: %s", format))
+		return
+	}
+
+	session, err := sm.GetSession(r.Context(), userId, sessionId)
+	if err != nil {
+		writeHandlerError(w, err)
+		return
+	}
+	messages := make([]*conversation.Message, 0)
+	page := conversation.Page{Limit: exportBatch}
+	for {
+		batch, err := sm.ListMessages(r.Context(), session, page)
+		if err != nil {
+			writeHandlerError(w, err)
+			return
+		}
+		messages = append(messages, batch...)
+		if len(batch) < page.Limit {
+			break
+		}
+		last := batch[len(batch)-1]
+		page.After = &conversation.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	filename := fmt.Sprintf("session-%d", session.ID)
+	if format == "markdown" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".md"))
+		writeMarkdown(w, session, messages)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+	writeJSON(w, http.StatusOK, sessionExport{Session: session, Messages: messages})
+}
+
+func writeMarkdown(w http.ResponseWriter, session *conversation.Session, messages []*conversation.Message) {
+	title := session.Title
+	if title == "" {
+		title = fmt.Sprintf("Session %d", session.ID)
+	}
+	fmt.Fprintf(w, "# %s\n\n", title)
+	for _, m := range messages {
+		role := "assistant"
+		if m.FromUserID == session.UserID {
+			role = "user"
+		}
+		fmt.Fprintf(w, "**%s** (%s)\n\n%s\n\n", role, m.CreatedAt.Format("2006-01-02 15:04:05"), m.Content)
+	}
+}
+
+func parsePage(r *http.Request) (conversation.Page, error) {
+	q := r.URL.Query()
+	page := conversation.Page{Limit: defaultLimit}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return page, fmt.Errorf("invalid limit: %s", v)
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+		page.Limit = limit
+	}
+	after, err := decodeCursor(q.Get("cursor"))
+	if err != nil {
+		return page, err
+	}
+	page.After = after
+	return page, nil
+}
+
+func writeHandlerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, conversation.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	log.Warn().Msgf("Conversation api failed: %s", err)
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warn().Msgf("Write response failed: %s", err)
+	}
+}
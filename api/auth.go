@@ -0,0 +1,83 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrUnauthorized 请求未通过认证
+	ErrUnauthorized = errors.New("api: unauthorized")
+	// ErrTokenExpired 用户令牌已过期
+	ErrTokenExpired = errors.New("api: token expired")
+)
+
+// Authenticator 从请求中识别用户，返回的 userId 与会话的 user_id 对应
+type Authenticator func(r *http.Request) (userId string, err error)
+
+// HeaderAuthenticator 从请求头中读取用户Id，只适用于前置网关已经完成认证、客户端无法直接设置该请求头的场景
+func HeaderAuthenticator(name string) Authenticator {
+	return func(r *http.Request) (string, error) {
+		userId := r.Header.Get(name)
+		if userId == "" {
+			return "", ErrUnauthorized
+		}
+		return userId, nil
+	}
+}
+
+// TokenAuthenticator 从请求头中读取 SignToken 签发的用户令牌，校验签名和有效期后返回其中的用户Id
+func TokenAuthenticator(name string, secret []byte) Authenticator {
+	return func(r *http.Request) (string, error) {
+		return VerifyToken(secret, r.Header.Get(name), time.Now())
+	}
+}
+
+// SignToken 为用户签发令牌，通常由业务后端在用户登录后签发给前端。令牌格式为 用户Id.过期时间.签名，各部分 base64url 编码
+func SignToken(secret []byte, userId string, ttl time.Duration) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(userId)) + "." + strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(tokenSignature(secret, payload))
+}
+
+// VerifyToken 校验用户令牌，返回其中的用户Id
+func VerifyToken(secret []byte, token string, now time.Time) (string, error) {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return "", ErrUnauthorized
+	}
+	payload, sig := token[:i], token[i+1:]
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, tokenSignature(secret, payload)) {
+		return "", ErrUnauthorized
+	}
+
+	encodedUser, expiry, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", ErrUnauthorized
+	}
+	expiredAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return "", ErrUnauthorized
+	}
+	if now.Unix() > expiredAt {
+		return "", ErrTokenExpired
+	}
+	userId, err := base64.RawURLEncoding.DecodeString(encodedUser)
+	if err != nil || len(userId) == 0 {
+		return "", fmt.Errorf("%w: malformed user", ErrUnauthorized)
+	}
+	return string(userId), nil
+}
+
+func tokenSignature(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
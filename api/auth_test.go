@@ -0,0 +1,46 @@
+package api
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenAuthenticator(t *testing.T) {
+	secret := []byte("secret")
+	auth := TokenAuthenticator("X-Xgpt3-User-Token", secret)
+
+	r := httptest.NewRequest("GET", "/sessions", nil)
+	r.Header.Set("X-Xgpt3-User-Token", SignToken(secret, "u1", time.Hour))
+	if userId, err := auth(r); err != nil || userId != "u1" {
+		t.Fatalf("auth = %q, %v", userId, err)
+	}
+
+	tests := map[string]string{
+		"empty":        "",
+		"wrong secret": SignToken([]byte("other"), "u1", time.Hour),
+		"plain user":   "u1",
+	}
+	for name, token := range tests {
+		r.Header.Set("X-Xgpt3-User-Token", token)
+		if _, err := auth(r); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	// 修改令牌中的用户后签名不再匹配
+	token := SignToken(secret, "u1", time.Hour)
+	other := SignToken(secret, "u2", time.Hour)
+	forged := other[:strings.LastIndexByte(other, '.')] + token[strings.LastIndexByte(token, '.'):]
+	r.Header.Set("X-Xgpt3-User-Token", forged)
+	if _, err := auth(r); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("forged: err = %v", err)
+	}
+
+	r.Header.Set("X-Xgpt3-User-Token", SignToken(secret, "u1", -time.Minute))
+	if _, err := auth(r); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("expired: err = %v", err)
+	}
+}
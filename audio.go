@@ -0,0 +1,174 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	defaultTranscriptionModel = openai.Whisper1
+	defaultSpeechVoice        = openai.VoiceAlloy
+	defaultSpeechFormat       = openai.SpeechResponseFormatMp3
+	// 语音合成接口单次输入的最大字符数
+	maxSpeechInput = 4096
+)
+
+var (
+	// ErrAudioUnsupported 模型服务没有实现 provider.AudioProvider
+	ErrAudioUnsupported = errors.New("xgpt3: provider does not support audio")
+	// ErrEmptyTranscript 语音转写结果为空
+	ErrEmptyTranscript = errors.New("xgpt3: empty transcript")
+)
+
+// AudioConfig 语音消息配置
+type AudioConfig struct {
+	// 转写模型，默认为 whisper-1
+	TranscriptionModel string
+	// 音频的语言，ISO-639-1 代码，例如 zh。为空时自动识别
+	Language string
+	// 转写的提示词，可以提供专有名词提高准确率
+	Prompt string
+	// 语音合成模型，例如 tts-1。为空时不合成回复
+	SpeechModel openai.SpeechModel
+	// 语音合成的声音，默认为 alloy
+	Voice openai.SpeechVoice
+	// 合成音频的格式，默认为 mp3
+	SpeechFormat openai.SpeechResponseFormat
+}
+
+// Audio 用户发送的语音
+type Audio struct {
+	// 音频内容
+	Reader io.Reader
+	// 文件名，转写接口根据扩展名识别音频格式，例如 voice.ogg
+	FileName string
+	// 音频的引用，例如对象存储地址或聊天平台的媒体Id，与转写文本一起保存在消息中。为空时不保存
+	Ref string
+}
+
+// AudioChatResponse 语音对话的结果
+type AudioChatResponse struct {
+	// 语音转写的文本
+	Transcript string
+	// 对话结果
+	Response openai.ChatCompletionResponse
+	// 合成的回复音频，没有开启语音合成时为空
+	Speech []byte
+	// 回复音频的格式
+	SpeechFormat openai.SpeechResponseFormat
+}
+
+type audioContextKey struct{}
+
+// WithAudio 设置语音消息的转写和合成，不设置时使用 whisper-1 转写、不合成回复。
+// 模型服务需要实现 provider.AudioProvider
+func (c *Client) WithAudio(cfg AudioConfig) *Client {
+	c.audio = cfg
+	return c
+}
+
+// CreateChatCompletionFromAudio 转写用户的语音，将转写文本作为最后一条用户消息进行对话。
+// 开启语音合成时同时返回回复的音频；合成失败时返回对话结果和错误
+func (c *Client) CreateChatCompletionFromAudio(ctx context.Context, request openai.ChatCompletionRequest, audio Audio, channel string) (AudioChatResponse, error) {
+	ap, ok := c.provider.(provider.AudioProvider)
+	if !ok {
+		return AudioChatResponse{}, ErrAudioUnsupported
+	}
+	cfg := c.audioConfig()
+
+	transcript, err := c.transcribe(ctx, ap, cfg, audio)
+	if err != nil {
+		return AudioChatResponse{}, c.failed(ctx, requestKindChat, StagePreprocess, request.User, channel, fmt.Errorf("transcribe audio failed: %w", err))
+	}
+	result := AudioChatResponse{Transcript: transcript}
+
+	if audio.Ref != "" {
+		ctx = context.WithValue(ctx, audioContextKey{}, conversation.ContentPart{Type: conversation.ContentPartAudio, AudioURL: audio.Ref})
+	}
+	request.Messages = append(append([]openai.ChatCompletionMessage{}, request.Messages...), openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: transcript,
+	})
+	result.Response, err = c.CreateChatCompletionWithChannel(ctx, request, channel)
+	if err != nil || cfg.SpeechModel == "" || len(result.Response.Choices) == 0 {
+		return result, err
+	}
+
+	result.Speech, err = c.synthesize(ctx, ap, cfg, result.Response.Choices[0].Message.Content)
+	if err != nil {
+		return result, fmt.Errorf("synthesize speech failed: %w", err)
+	}
+	result.SpeechFormat = cfg.SpeechFormat
+	return result, nil
+}
+
+// audioConfig 返回填充了默认值的语音配置
+func (c *Client) audioConfig() AudioConfig {
+	cfg := c.audio
+	if cfg.TranscriptionModel == "" {
+		cfg.TranscriptionModel = defaultTranscriptionModel
+	}
+	if cfg.Voice == "" {
+		cfg.Voice = defaultSpeechVoice
+	}
+	if cfg.SpeechFormat == "" {
+		cfg.SpeechFormat = defaultSpeechFormat
+	}
+	return cfg
+}
+
+func (c *Client) transcribe(ctx context.Context, ap provider.AudioProvider, cfg AudioConfig, audio Audio) (text string, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.transcribe", attribute.String("xgpt3.model", cfg.TranscriptionModel))
+	defer func() { endSpan(span, err) }()
+
+	resp, err := ap.CreateTranscription(ctx, openai.AudioRequest{
+		Model:    cfg.TranscriptionModel,
+		FilePath: audio.FileName,
+		Reader:   audio.Reader,
+		Prompt:   cfg.Prompt,
+		Language: cfg.Language,
+		Format:   openai.AudioResponseFormatJSON,
+	})
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(resp.Text)
+	if text == "" {
+		return "", ErrEmptyTranscript
+	}
+	return text, nil
+}
+
+func (c *Client) synthesize(ctx context.Context, ap provider.AudioProvider, cfg AudioConfig, text string) (audio []byte, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.speech", attribute.String("xgpt3.model", string(cfg.SpeechModel)))
+	defer func() { endSpan(span, err) }()
+
+	if r := []rune(text); len(r) > maxSpeechInput {
+		text = string(r[:maxSpeechInput])
+	}
+	rc, err := ap.CreateSpeech(ctx, openai.CreateSpeechRequest{
+		Model:          cfg.SpeechModel,
+		Input:          text,
+		Voice:          cfg.Voice,
+		ResponseFormat: cfg.SpeechFormat,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// audioPart 返回请求中语音消息的音频片段
+func audioPart(ctx context.Context) (conversation.ContentPart, bool) {
+	part, ok := ctx.Value(audioContextKey{}).(conversation.ContentPart)
+	return part, ok
+}
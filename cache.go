@@ -0,0 +1,119 @@
+package xgpt3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/cache"
+	"github.com/sashabaranov/go-openai"
+)
+
+// WithResponseCache 开启完全匹配的回复缓存。缓存键由缓存所属的用户、拼接好的消息、模型和采样参数生成，
+// 不同用户之间不共享缓存。默认只缓存 temperature 为 0 的请求
+func (c *Client) WithResponseCache(store cache.Store) *Client {
+	c.cache = store
+	return c
+}
+
+// WithCacheNonDeterministic 允许缓存 temperature 不为 0 的请求
+func (c *Client) WithCacheNonDeterministic() *Client {
+	c.cacheNonDeterministic = true
+	return c
+}
+
+type cacheKeyMessage struct {
+	Role    string `json:"role"`
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+type cacheKeyPayload struct {
+	Owner            string            `json:"owner"`
+	Model            string            `json:"model"`
+	Messages         []cacheKeyMessage `json:"messages"`
+	MaxTokens        int               `json:"max_tokens"`
+	Temperature      float32           `json:"temperature"`
+	TopP             float32           `json:"top_p"`
+	N                int               `json:"n"`
+	Stop             []string          `json:"stop"`
+	PresencePenalty  float32           `json:"presence_penalty"`
+	FrequencyPenalty float32           `json:"frequency_penalty"`
+}
+
+// responseCacheKey 返回 owner 的请求的缓存键，请求不允许缓存时返回 false
+func (c *Client) responseCacheKey(owner string, request openai.ChatCompletionRequest) (string, bool) {
+	if c.cache == nil || request.Stream {
+		return "", false
+	}
+	if request.Temperature != 0 && !c.cacheNonDeterministic {
+		return "", false
+	}
+
+	payload := cacheKeyPayload{
+		Owner:            owner,
+		Model:            request.Model,
+		Messages:         make([]cacheKeyMessage, 0, len(request.Messages)),
+		MaxTokens:        request.MaxTokens,
+		Temperature:      request.Temperature,
+		TopP:             request.TopP,
+		N:                request.N,
+		Stop:             request.Stop,
+		PresencePenalty:  request.PresencePenalty,
+		FrequencyPenalty: request.FrequencyPenalty,
+	}
+	for _, m := range request.Messages {
+		// 多模态消息不缓存
+		if len(m.MultiContent) > 0 {
+			return "", false
+		}
+		payload.Messages = append(payload.Messages, cacheKeyMessage{
+			Role:    m.Role,
+			Name:    m.Name,
+			Content: normalizeContent(m.Content),
+		})
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), true
+}
+
+func normalizeContent(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func (c *Client) getCachedResponse(ctx context.Context, key string) (openai.ChatCompletionResponse, bool) {
+	var resp openai.ChatCompletionResponse
+	b, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		c.logger.Warn().Msgf("Get response cache failed: %s", err)
+		return resp, false
+	}
+	if !ok {
+		return resp, false
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		c.logger.Warn().Msgf("Unmarshal response cache failed: %s", err)
+		return resp, false
+	}
+	// 命中缓存时没有消耗 token
+	resp.Usage = openai.Usage{}
+	return resp, true
+}
+
+func (c *Client) setCachedResponse(ctx context.Context, key string, resp openai.ChatCompletionResponse) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		c.logger.Warn().Msgf("Marshal response cache failed: %s", err)
+		return
+	}
+	if err := c.cache.Set(ctx, key, b); err != nil {
+		c.logger.Warn().Msgf("Set response cache failed: %s", err)
+	}
+}
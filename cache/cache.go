@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store 缓存存储
+type Store interface {
+	// 获取缓存。缓存不存在或已过期时返回 false
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// 写入缓存。持久化的存储可以通过 Owner(ctx) 记录缓存所属的用户，删除用户数据时一并删除
+	Set(ctx context.Context, key string, value []byte) error
+}
+
+type ownerContextKey struct{}
+
+// WithOwner 设置写入的缓存所属的用户
+func WithOwner(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, ownerContextKey{}, userId)
+}
+
+// Owner 返回写入的缓存所属的用户，没有设置时为空
+func Owner(ctx context.Context) string {
+	userId, _ := ctx.Value(ownerContextKey{}).(string)
+	return userId
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiredAt time.Time
+}
+
+// LRU 进程内的 LRU 缓存
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// NewLRU 创建最多保存 size 条记录的 LRU 缓存。ttl 为 0 时缓存不过期
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{size: size, ttl: ttl, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *LRU) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := e.Value.(*lruEntry)
+	if !entry.expiredAt.IsZero() && time.Now().After(entry.expiredAt) {
+		c.ll.Remove(e)
+		delete(c.items, key)
+		return nil, false, nil
+	}
+	c.ll.MoveToFront(e)
+	return entry.value, true, nil
+}
+
+func (c *LRU) Set(ctx context.Context, key string, value []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiredAt time.Time
+	if c.ttl > 0 {
+		expiredAt = time.Now().Add(c.ttl)
+	}
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		entry := e.Value.(*lruEntry)
+		entry.value, entry.expiredAt = value, expiredAt
+		return nil
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expiredAt: expiredAt})
+	for c.size > 0 && c.ll.Len() > c.size {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.items, e.Value.(*lruEntry).key)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package xgpt3_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fanchunke/xgpt3/cache"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+)
+
+func TestResponseCacheScopedByUser(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+	c.WithResponseCache(cache.NewLRU(100, time.Hour))
+	srv.Respond(func(r xgpt3test.Request) xgpt3test.Response {
+		return xgpt3test.Response{Content: "你好"}
+	})
+
+	for _, user := range []string{"alice", "bob"} {
+		if _, err := c.CreateChatCompletion(ctx, chatRequest(user, "你好")); err != nil {
+			t.Fatalf("CreateChatCompletion() error = %v", err)
+		}
+		if err := c.CloseConversation(ctx, user); err != nil {
+			t.Fatalf("CloseConversation() error = %v", err)
+		}
+	}
+	if n := chatRequests(srv); n != 2 {
+		t.Fatalf("chat requests = %d, want 2", n)
+	}
+
+	// 同一用户的相同请求命中缓存
+	resp, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if resp.Choices[0].Message.Content != "你好" || chatRequests(srv) != 2 {
+		t.Fatalf("reply = %q, chat requests = %d", resp.Choices[0].Message.Content, chatRequests(srv))
+	}
+}
@@ -0,0 +1,280 @@
+// Package cassette 录制并回放与模型服务的 HTTP 交互，集成测试可以在没有网络的环境中运行。
+//
+// 录制时请求头和查询参数中的 API Key 等认证信息会被替换，请求按 method、路径、查询参数和规范化后的请求体匹配。
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode 工作模式
+type Mode int
+
+const (
+	// 回放已录制的交互，没有匹配的交互时返回错误
+	ModeReplay Mode = iota
+	// 请求真实服务并录制交互
+	ModeRecord
+	// 文件存在时回放，否则录制
+	ModeAuto
+)
+
+const redacted = "[REDACTED]"
+
+// 录制时替换的请求头和响应头
+var sensitiveHeaders = []string{"Authorization", "Api-Key", "Openai-Organization", "Cookie", "Set-Cookie"}
+
+// 录制时替换的查询参数，不区分大小写
+var sensitiveParams = []string{"api-key", "api_key", "apikey", "key", "access_token", "token", "sig", "signature"}
+
+// ErrNoInteraction 回放时没有匹配的交互
+var ErrNoInteraction = errors.New("cassette: no matching interaction")
+
+// Interaction 一次请求和响应
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder 实现 http.RoundTripper，按模式录制或回放交互
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         map[int]bool
+}
+
+// New 创建 Recorder。回放模式下从 path 读取录制的交互，录制模式下每次交互后写入 path
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		used:      make(map[int]bool),
+	}
+	if mode == ModeAuto {
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	}
+	if r.mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read cassette failed: %w", err)
+		}
+		if err := json.Unmarshal(b, &r.interactions); err != nil {
+			return nil, fmt.Errorf("parse cassette failed: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// WithTransport 设置录制时使用的 http.RoundTripper，默认为 http.DefaultTransport
+func (r *Recorder) WithTransport(t http.RoundTripper) *Recorder {
+	r.transport = t
+	return r
+}
+
+// Mode 实际使用的模式
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// HTTPClient 使用 Recorder 的 http.Client，可以设置到 openai.ClientConfig.HTTPClient
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	// 录制的地址中认证参数已被替换，匹配前同样替换
+	key := Request{Method: req.Method, URL: scrubURL(req.URL), Body: normalize(body)}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 优先使用未回放过的交互，全部回放过后重复使用最后一个匹配的交互
+	found := -1
+	for i, it := range r.interactions {
+		if !match(it.Request, key) {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.Path)
+	}
+	r.used[found] = true
+	return toHTTPResponse(r.interactions[found].Response, req), nil
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	it := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    scrubURL(req.URL),
+			Header: scrub(req.Header),
+			Body:   normalize(body),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     scrub(resp.Header),
+			Body:       string(respBody),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, it)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (r *Recorder) save() error {
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("create cassette dir failed: %w", err)
+	}
+	if err := os.WriteFile(r.path, b, 0o644); err != nil {
+		return fmt.Errorf("write cassette failed: %w", err)
+	}
+	return nil
+}
+
+// match 只比较路径和查询参数，回放时服务地址可以与录制时不同
+func match(recorded, req Request) bool {
+	return recorded.Method == req.Method && requestURI(recorded.URL) == requestURI(req.URL) && recorded.Body == req.Body
+}
+
+func requestURI(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	return u.RequestURI()
+}
+
+// normalize 规范化 JSON 请求体：去掉空白并按字段名排序，非 JSON 内容保持原样
+func normalize(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(b)
+}
+
+func scrub(h http.Header) http.Header {
+	result := h.Clone()
+	for _, k := range sensitiveHeaders {
+		if result.Get(k) != "" {
+			result.Set(k, redacted)
+		}
+	}
+	return result
+}
+
+// scrubURL 替换地址中的认证参数。有参数被替换时其余参数按名称排序
+func scrubURL(u *url.URL) string {
+	q := u.Query()
+	scrubbed := false
+	for k := range q {
+		for _, p := range sensitiveParams {
+			if strings.EqualFold(k, p) {
+				q[k] = []string{redacted}
+				scrubbed = true
+			}
+		}
+	}
+	if !scrubbed {
+		return u.String()
+	}
+	result := *u
+	result.RawQuery = q.Encode()
+	return result.String()
+}
+
+func toHTTPResponse(resp Response, req *http.Request) *http.Response {
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode)),
+		StatusCode:    resp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(resp.Body))),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}
+}
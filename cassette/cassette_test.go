@@ -0,0 +1,95 @@
+package cassette
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newUpstream(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, c *http.Client, url, body string) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	req.Header.Set("Api-Key", "azure-secret")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return string(b)
+}
+
+func TestRecordScrubsCredentials(t *testing.T) {
+	upstream := newUpstream(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	do(t, rec.HTTPClient(), upstream.URL+"/openai/deployments/chat/chat/completions?api-version=2023-05-15&api-key=query-secret", `{"model":"gpt"}`)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cassette failed: %v", err)
+	}
+	for _, secret := range []string{"sk-secret", "azure-secret", "query-secret", "secret-cookie"} {
+		if strings.Contains(string(b), secret) {
+			t.Fatalf("cassette contains %q:\n%s", secret, b)
+		}
+	}
+	if !strings.Contains(string(b), "api-version=2023-05-15") {
+		t.Fatalf("cassette lost non-sensitive query params:\n%s", b)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	upstream := newUpstream(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	do(t, rec.HTTPClient(), upstream.URL+"/v1/chat/completions?api-key=query-secret", `{"model":"gpt","messages":[{"role":"user","content":"hi"}]}`)
+	upstream.Close()
+
+	rec, err = New(path, ModeAuto)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if rec.Mode() != ModeReplay {
+		t.Fatalf("Mode() = %v, want ModeReplay", rec.Mode())
+	}
+
+	// 回放时服务地址和认证参数可以不同，请求体按规范化后的 JSON 匹配
+	got := do(t, rec.HTTPClient(), "http://replay.invalid/v1/chat/completions?api-key=other-key", `{
+		"messages": [{"content": "hi", "role": "user"}],
+		"model": "gpt"
+	}`)
+	if got != `{"ok":true}` {
+		t.Fatalf("replayed body = %q", got)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://replay.invalid/v1/chat/completions", strings.NewReader(`{"model":"other"}`))
+	if _, err := rec.HTTPClient().Do(req); !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("unmatched request error = %v, want ErrNoInteraction", err)
+	}
+}
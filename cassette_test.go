@@ -0,0 +1,37 @@
+package xgpt3_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/cassette"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/sashabaranov/go-openai"
+)
+
+// 回放 testdata 中录制的两轮对话，不需要网络。第二轮的请求体包含第一轮的历史消息，历史拼接变化时匹配失败。
+// 录制的交互来自 xgpt3test，需要对真实服务录制时删除 testdata/cassettes/chat.json 并设置 OPENAI_API_KEY 后运行
+func TestChatCompletionWithChannelReplay(t *testing.T) {
+	rec, err := cassette.New("testdata/cassettes/chat.json", cassette.ModeAuto)
+	if err != nil {
+		t.Fatalf("cassette.New() error = %v", err)
+	}
+	config := openai.DefaultConfig(os.Getenv("OPENAI_API_KEY"))
+	config.HTTPClient = rec.HTTPClient()
+	c := xgpt3.NewClient(openai.NewClientWithConfig(config), memory.New())
+	ctx := context.Background()
+
+	questions := []string{"用一句话介绍京都", "它和大阪相距多远"}
+	for _, q := range questions {
+		resp, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("alice", q), "wechat")
+		if err != nil {
+			t.Fatalf("CreateChatCompletionWithChannel(%q) error = %v", q, err)
+		}
+		if len(resp.Choices) == 0 || strings.TrimSpace(resp.Choices[0].Message.Content) == "" {
+			t.Fatalf("CreateChatCompletionWithChannel(%q) empty reply", q)
+		}
+	}
+}
@@ -7,31 +7,80 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/fanchunke/xgpt3/cache"
 	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/knowledge"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/fanchunke/xgpt3/redact"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	defaultMaxCtxLength = 4097
 	defaultMaxTurn      = 10
-	defaultChannel      = "default"
+	defaultChannel      = conversation.DefaultChannel
 	questionPrefix      = "Q"
 	answerPrefix        = "A"
 )
 
 type Client struct {
-	*openai.Client
 	ch           conversation.Handler
 	maxCtxLength int
 	maxTurn      int
 	logger       zerolog.Logger
+	redactor     *redact.Redactor
+	middlewares  []ChatMiddleware
+	events       eventBus
+	tracer       trace.Tracer
+	metrics      *metrics
+
+	cache                 cache.Store
+	cacheNonDeterministic bool
+	semanticCache         *SemanticCache
+	memory                *LongTermMemory
+	knowledge             *knowledge.KnowledgeBase
+	titleModel            string
+	provider              provider.Provider
+	inflight              *InflightRequests
+	audio                 AudioConfig
 }
 
 func NewClient(client *openai.Client, ch conversation.Handler) *Client {
-	return &Client{Client: client, ch: ch, maxCtxLength: defaultMaxCtxLength, maxTurn: defaultMaxTurn, logger: log.Logger}
+	return NewClientWithProvider(provider.NewOpenAI(client), ch)
+}
+
+// NewClientWithProvider 使用指定的模型服务，例如 Azure OpenAI 或兼容 OpenAI 接口的自部署服务
+func NewClientWithProvider(p provider.Provider, ch conversation.Handler) *Client {
+	return &Client{
+		provider:     p,
+		ch:           ch,
+		maxCtxLength: defaultMaxCtxLength,
+		maxTurn:      defaultMaxTurn,
+		logger:       log.Logger,
+		tracer:       trace.NewNoopTracerProvider().Tracer(instrumentationName),
+		inflight:     NewInflightRequests(),
+	}
+}
+
+// Provider 返回模型服务，可以用于不需要会话记录的请求
+func (c *Client) Provider() provider.Provider {
+	return c.provider
+}
+
+// OpenAI 返回底层的 go-openai client，用于调用 Provider 之外的接口，例如图片和文件。
+// 模型服务不是基于 go-openai 的实现时返回 nil
+func (c *Client) OpenAI() *openai.Client {
+	if p, ok := c.provider.(interface{ Client() *openai.Client }); ok {
+		return p.Client()
+	}
+	return nil
 }
 
 func (c *Client) WithMaxTurn(n int) *Client {
@@ -44,6 +93,12 @@ func (c *Client) WithLogger(l zerolog.Logger) *Client {
 	return c
 }
 
+// WithRedactor 在保存消息和请求 OpenAI 之前对用户输入脱敏
+func (c *Client) WithRedactor(r *redact.Redactor) *Client {
+	c.redactor = r
+	return c
+}
+
 func (c *Client) CreateConversationCompletion(
 	ctx context.Context,
 	request openai.CompletionRequest,
@@ -56,24 +111,40 @@ func (c *Client) CreateConversationCompletionWithChannel(
 	request openai.CompletionRequest,
 	channel string,
 ) (openai.CompletionResponse, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "xgpt3.CreateConversationCompletion", attribute.String("xgpt3.model", request.Model), attribute.String("xgpt3.channel", channel))
+	defer span.End()
+
+	// 脱敏
+	vault := c.redactCompletionRequest(ctx, &request)
 	c.logger.Debug().Msgf("User: %s, Origin Prompt: %s", request.User, request.Prompt)
 	// 预处理
 	session, msg, err := c.preCompletion(ctx, &request, channel)
 	if err != nil {
-		return openai.CompletionResponse{}, fmt.Errorf("preprocess failed: %w", err)
+		return openai.CompletionResponse{}, c.failed(ctx, requestKindCompletion, StagePreprocess, request.User, channel, fmt.Errorf("preprocess failed: %w", err))
 	}
 	c.logger.Debug().Msgf("User: %s, Prompt with conversation: %s", request.User, request.Prompt)
 
 	// 请求
-	resp, err := c.Client.CreateCompletion(ctx, request)
+	upstreamCtx, upstreamSpan := c.startSpan(ctx, "xgpt3.upstream", attribute.String("xgpt3.model", request.Model), attribute.Int("xgpt3.max_tokens", request.MaxTokens))
+	resp, err := c.provider.CreateCompletion(upstreamCtx, request)
+	upstreamSpan.SetAttributes(usageAttributes(resp.Usage)...)
+	endSpan(upstreamSpan, err)
 	if err != nil {
-		return resp, err
+		return resp, c.failed(ctx, requestKindCompletion, StageUpstream, request.User, channel, err)
 	}
 
 	// 后处理
 	_, err = c.postCompletion(ctx, request, resp, session, msg, channel)
 	if err != nil {
-		return openai.CompletionResponse{}, fmt.Errorf("postprocess failed: %w", err)
+		return openai.CompletionResponse{}, c.failed(ctx, requestKindCompletion, StagePostprocess, request.User, channel, fmt.Errorf("postprocess failed: %w", err))
+	}
+	span.SetAttributes(usageAttributes(resp.Usage)...)
+	c.metrics.observe(requestKindCompletion, request.Model, start, resp.Usage)
+
+	// 还原回复中的占位符
+	for i := range resp.Choices {
+		resp.Choices[i].Text = vault.Restore(resp.Choices[i].Text)
 	}
 	return resp, nil
 }
@@ -84,21 +155,21 @@ func (c *Client) preCompletion(ctx context.Context, request *openai.CompletionRe
 	}
 
 	// 获取最近的 session。如果没有 session，创建一个 session。
-	session, err := c.ch.GetLatestActiveSession(ctx, request.User)
+	session, err := c.getOrCreateSession(ctx, request.User, channel)
 	if err != nil {
-		session, err = c.ch.CreateSession(ctx, request.User)
-		if err != nil {
-			return nil, nil, fmt.Errorf("create session failed: %w", err)
-		}
+		return nil, nil, err
 	}
 
-	newPrompt := c.buildSessionQuery(ctx, session, request)
+	assemblyCtx, assemblySpan := c.startSpan(ctx, "xgpt3.prompt_assembly")
+	newPrompt := c.buildSessionQuery(assemblyCtx, session, request)
+	assemblySpan.End()
 
 	// 保存用户消息
-	msg, err := c.ch.CreateMessage(ctx, session, request.User, channel, convertCompletionPrompt(request.Prompt))
+	msg, err := c.createMessage(ctx, session, request.User, channel, convertCompletionPrompt(request.Prompt))
 	if err != nil {
 		return session, nil, fmt.Errorf("create message failed: %w", err)
 	}
+	c.emit(ctx, Event{Type: EventMessageStored, UserID: request.User, Channel: channel, Session: session, Message: msg})
 
 	request.Prompt = newPrompt
 	prompt := convertCompletionPrompt(request.Prompt)
@@ -118,10 +189,11 @@ func (c *Client) buildSessionQuery(ctx context.Context, session *conversation.Se
 	prompt := convertCompletionPrompt(request.Prompt)
 	if len(prompt)+request.MaxTokens > c.maxCtxLength {
 		c.logger.Debug().Msgf("Requested %d tokens (%d in your prompt; %d for the completion), reduce prompt", len(prompt)+request.MaxTokens, len(prompt), request.MaxTokens)
+		c.metrics.truncated(requestKindCompletion)
 		return string([]rune(prompt)[:c.maxCtxLength-request.MaxTokens])
 	}
 
-	msgs, err := c.ch.ListLatestMessagesWithSpouse(ctx, session, request.User, c.maxTurn)
+	msgs, err := c.listHistory(ctx, session, request.User)
 	if err != nil {
 		c.logger.Warn().Msgf("ListLatestMessagesWithSpouse failed: %s", err)
 		return request.Prompt
@@ -148,6 +220,7 @@ func (c *Client) buildSessionQuery(ctx context.Context, session *conversation.Se
 			selectedMsgs = append([]string{prompt}, selectedMsgs...)
 			pl += len(prompt)
 		} else {
+			c.metrics.truncated(requestKindCompletion)
 			break
 		}
 	}
@@ -169,21 +242,140 @@ func (c *Client) postCompletion(ctx context.Context, request openai.CompletionRe
 	}
 
 	reply := response.Choices[0].Text
-	m, err := c.ch.CreateSpouseMessage(ctx, session, channel, request.User, reply, msg)
+	m, err := c.createSpouseMessage(ctx, session, channel, request.User, reply, msg)
 	if err != nil {
 		return nil, fmt.Errorf("create spouse message failed: %w", err)
 	}
+	c.emit(ctx, Event{Type: EventReplyStored, UserID: request.User, Channel: channel, Session: session, Message: m, Usage: response.Usage})
 	return m, nil
 }
 
 func (c *Client) CloseConversation(ctx context.Context, userId string) error {
+	return c.CloseConversationWithChannel(ctx, userId, defaultChannel)
+}
+
+// CloseConversationWithChannel 关闭用户在指定渠道中的会话，不影响其他渠道。
+// 用户没有开启的会话时不做处理，也不会触发 EventSessionClosed 事件
+func (c *Client) CloseConversationWithChannel(ctx context.Context, userId, channel string) error {
+	session, err := c.latestSession(ctx, userId, channel)
+	if errors.Is(err, conversation.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get latest session failed: %w", err)
+	}
+	if err := c.closeSession(ctx, userId, channel); err != nil {
+		return err
+	}
+	session.Status = false
+	c.emit(ctx, Event{Type: EventSessionClosed, UserID: userId, Channel: channel, Session: session})
+	return nil
+}
+
+// ErrShredUnsupported 会话后端没有实现 conversation.UserShredder
+var ErrShredUnsupported = errors.New("xgpt3: conversation handler does not support user shredding")
+
+// ShredUser 删除用户的数据密钥和由用户消息生成的数据，并从进程内的长期记忆、语义缓存索引和脱敏占位符映射中移除该用户。
+// 会话后端需要实现 conversation.UserShredder
+func (c *Client) ShredUser(ctx context.Context, userId string) error {
+	s, ok := c.ch.(conversation.UserShredder)
+	if !ok {
+		return ErrShredUnsupported
+	}
+	if err := s.ShredUser(ctx, userId); err != nil {
+		return err
+	}
+	if c.memory != nil {
+		c.memory.forget(userId)
+	}
+	if c.semanticCache != nil {
+		c.semanticCache.forget(userId)
+	}
+	if c.redactor != nil {
+		c.redactor.Forget(userId)
+	}
+	return nil
+}
+
+// latestSession、createSession、closeSession 在会话后端实现 conversation.ChannelHandler 时按渠道区分会话，
+// 否则所有渠道共用一个会话
+func (c *Client) latestSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
+	if ch, ok := c.ch.(conversation.ChannelHandler); ok {
+		return ch.GetLatestActiveChannelSession(ctx, userId, channel)
+	}
+	return c.ch.GetLatestActiveSession(ctx, userId)
+}
+
+func (c *Client) createSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
+	if ch, ok := c.ch.(conversation.ChannelHandler); ok {
+		return ch.CreateChannelSession(ctx, userId, channel)
+	}
+	return c.ch.CreateSession(ctx, userId)
+}
+
+func (c *Client) closeSession(ctx context.Context, userId, channel string) error {
+	if ch, ok := c.ch.(conversation.ChannelHandler); ok {
+		return ch.CloseChannelSession(ctx, userId, channel)
+	}
 	return c.ch.CloseSession(ctx, userId)
 }
 
+func (c *Client) failed(ctx context.Context, kind string, stage Stage, userId, channel string, err error) error {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	c.metrics.failed(kind, stage, err)
+	c.emit(ctx, Event{Type: EventRequestFailed, UserID: userId, Channel: channel, Err: err})
+	return err
+}
+
+func (c *Client) getOrCreateSession(ctx context.Context, userId, channel string) (session *conversation.Session, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.session_lookup")
+	defer func() { endSpan(span, err) }()
+
+	session, err = c.latestSession(ctx, userId, channel)
+	if err == nil {
+		return session, nil
+	}
+	session, err = c.createSession(ctx, userId, channel)
+	if err != nil {
+		return nil, fmt.Errorf("create session failed: %w", err)
+	}
+	span.SetAttributes(attribute.Bool("xgpt3.session.created", true))
+	c.emit(ctx, Event{Type: EventSessionCreated, UserID: userId, Channel: channel, Session: session})
+	return session, nil
+}
+
+func (c *Client) listHistory(ctx context.Context, session *conversation.Session, userId string) (msgs []*conversation.Message, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.history_load", attribute.Int("xgpt3.history.max_turns", c.maxTurn))
+	defer func() { endSpan(span, err) }()
+
+	msgs, err = c.ch.ListLatestMessagesWithSpouse(ctx, session, userId, c.maxTurn)
+	span.SetAttributes(attribute.Int("xgpt3.history.messages", len(msgs)))
+	return msgs, err
+}
+
+func (c *Client) createMessage(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content string) (m *conversation.Message, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.persist", attribute.String("xgpt3.message.role", openai.ChatMessageRoleUser))
+	defer func() { endSpan(span, err) }()
+	if key := IdempotencyKey(ctx); key != "" {
+		if s, ok := c.ch.(conversation.IdempotencyStore); ok {
+			return c.createKeyedMessage(ctx, s, session, fromUserId, toUserId, content, key)
+		}
+	}
+	return c.ch.CreateMessage(ctx, session, fromUserId, toUserId, content)
+}
+
+func (c *Client) createSpouseMessage(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content string, spouse *conversation.Message) (m *conversation.Message, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.persist", attribute.String("xgpt3.message.role", openai.ChatMessageRoleAssistant))
+	defer func() { endSpan(span, err) }()
+	return c.ch.CreateSpouseMessage(ctx, session, fromUserId, toUserId, content, spouse)
+}
+
 func getRequestTokens(request openai.ChatCompletionRequest) int {
 	l := 0
 	for _, m := range request.Messages {
-		l += len(m.Content)
+		l += messageTokens(m)
 	}
 	return l
 }
@@ -197,9 +389,9 @@ func (c *Client) reduceRequestMessages(request openai.ChatCompletionRequest) []o
 	msgs := make([]openai.ChatCompletionMessage, 0)
 	l := 0
 	for _, m := range request.Messages {
-		if l+len(m.Content)+request.MaxTokens <= c.maxCtxLength {
+		if l+messageTokens(m)+request.MaxTokens <= c.maxCtxLength {
 			msgs = append(msgs, m)
-			l = len(m.Content)
+			l = messageTokens(m)
 		} else {
 			break
 		}
@@ -207,9 +399,13 @@ func (c *Client) reduceRequestMessages(request openai.ChatCompletionRequest) []o
 
 	if len(msgs) == 0 && len(request.Messages) > 0 {
 		m := request.Messages[0]
+		content := []rune(messageText(m))
+		if n := c.maxCtxLength - request.MaxTokens; len(content) > n {
+			content = content[:n]
+		}
 		msgs = append(msgs, openai.ChatCompletionMessage{
 			Role:    m.Role,
-			Content: string([]rune(m.Content)[:c.maxCtxLength-request.MaxTokens]),
+			Content: string(content),
 		})
 	}
 	return msgs
@@ -220,107 +416,159 @@ func (c *Client) CreateChatCompletion(ctx context.Context, request openai.ChatCo
 }
 
 func (c *Client) CreateChatCompletionWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (openai.ChatCompletionResponse, error) {
+	if key := IdempotencyKey(ctx); key != "" {
+		return c.idempotentChatCompletion(ctx, request, channel, key)
+	}
+	return c.createChatCompletion(ctx, request, channel)
+}
+
+func (c *Client) createChatCompletion(ctx context.Context, request openai.ChatCompletionRequest, channel string) (openai.ChatCompletionResponse, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "xgpt3.CreateChatCompletion", attribute.String("xgpt3.model", request.Model), attribute.String("xgpt3.channel", channel))
+	defer span.End()
+
+	// 脱敏
+	vault := c.redactChatRequest(ctx, &request)
 	c.logger.Debug().Msgf("User: %s, Origin Messages: %s", request.User, marshalMessages(request.Messages))
+	cc := &ChatContext{Channel: channel, Request: request}
+
 	// 预处理
-	session, msg, err := c.preChatCompletion(ctx, &request, channel)
-	if err != nil {
-		return openai.ChatCompletionResponse{}, fmt.Errorf("chat completion preprocess failed: %w", err)
+	if err := c.runStage(ctx, cc, StagePreprocess, c.preprocessStage); err != nil {
+		return openai.ChatCompletionResponse{}, c.failed(ctx, requestKindChat, StagePreprocess, request.User, channel, fmt.Errorf("chat completion preprocess failed: %w", err))
 	}
-	c.logger.Debug().Msgf("User: %s, Messages with conversation: %s", request.User, marshalMessages(request.Messages))
+	c.logger.Debug().Msgf("User: %s, Messages with conversation: %s", cc.Request.User, marshalMessages(cc.Request.Messages))
 
 	// 请求
-	resp, err := c.Client.CreateChatCompletion(ctx, request)
-	if err != nil {
-		return resp, err
+	if err := c.runStage(ctx, cc, StageUpstream, c.upstreamStage); err != nil {
+		return cc.Response, c.failed(ctx, requestKindChat, StageUpstream, request.User, channel, err)
 	}
 
 	// 后处理
-	_, err = c.postChatCompletion(ctx, request, resp, session, msg, channel)
-	if err != nil {
-		return openai.ChatCompletionResponse{}, fmt.Errorf("chat completion postprocess failed: %w", err)
+	if err := c.runStage(ctx, cc, StagePostprocess, c.postprocessStage); err != nil {
+		return openai.ChatCompletionResponse{}, c.failed(ctx, requestKindChat, StagePostprocess, request.User, channel, fmt.Errorf("chat completion postprocess failed: %w", err))
+	}
+
+	span.SetAttributes(usageAttributes(cc.Response.Usage)...)
+	c.metrics.observe(requestKindChat, request.Model, start, cc.Response.Usage)
+
+	// 还原回复中的占位符
+	resp := cc.Response
+	for i := range resp.Choices {
+		resp.Choices[i].Message.Content = vault.Restore(resp.Choices[i].Message.Content)
 	}
 	return resp, nil
 }
 
-func (c *Client) preChatCompletion(ctx context.Context, request *openai.ChatCompletionRequest, channel string) (*conversation.Session, *conversation.Message, error) {
+func (c *Client) preChatCompletion(ctx context.Context, cc *ChatContext) error {
+	request, channel := &cc.Request, cc.Channel
 	if request.MaxTokens >= c.maxCtxLength {
-		return nil, nil, fmt.Errorf("request.MaxTokens exceeded maximum context length")
+		return fmt.Errorf("request.MaxTokens exceeded maximum context length")
 	}
 
-	// 获取最近的 session。如果没有 session，创建一个 session。
-	session, err := c.ch.GetLatestActiveSession(ctx, request.User)
-	if err != nil {
-		session, err = c.ch.CreateSession(ctx, request.User)
-		if err != nil {
-			return nil, nil, fmt.Errorf("create session failed: %w", err)
+	// 获取最近的 session。如果没有 session，创建一个 session。群聊中使用群的会话
+	var session *conversation.Session
+	var err error
+	if groupId := GroupID(ctx); groupId != "" {
+		gh, ok := c.ch.(conversation.GroupHandler)
+		if !ok {
+			return ErrGroupUnsupported
 		}
+		session, err = c.getOrCreateGroupSession(ctx, gh, groupId, request.User, channel)
+		request.Messages = nameLastUserMessage(request.Messages, request.User)
+	} else {
+		session, err = c.getOrCreateSession(ctx, request.User, channel)
 	}
+	if err != nil {
+		return err
+	}
+	cc.Session = session
 
 	// 保存用户消息。只保存请求中最后一次的用户信息
 	var msg *conversation.Message
 	for i := len(request.Messages) - 1; i >= 0; i-- {
 		m := request.Messages[i]
 		if m.Role == openai.ChatMessageRoleUser {
-			msg, err = c.ch.CreateMessage(ctx, session, request.User, channel, m.Content)
+			msg, err = c.createMessage(ctx, session, request.User, channel, messageText(m))
 			if err != nil {
-				return session, nil, fmt.Errorf("create message failed: %w", err)
+				return fmt.Errorf("create message failed: %w", err)
 			}
+			// 多模态消息和语音消息另外保存内容片段，之后的对话中还原图片
+			if parts := messageParts(ctx, m); len(parts) > 0 {
+				if err := c.saveContentParts(ctx, session, msg, parts); err != nil {
+					return fmt.Errorf("save content parts failed: %w", err)
+				}
+			}
+			c.emit(ctx, Event{Type: EventMessageStored, UserID: request.User, Channel: channel, Session: session, Message: msg})
 			break
 		}
 	}
 	if msg == nil {
-		return session, nil, errors.New("request has no user message")
+		return errors.New("request has no user message")
 	}
+	cc.Message = msg
 
 	// 构造会话历史消息
-	request.Messages = c.buildChatSessionQuery(ctx, session, request)
+	assemblyCtx, assemblySpan := c.startSpan(ctx, "xgpt3.prompt_assembly")
+	originLen := len(request.Messages)
+	memory := c.recall(assemblyCtx, session, request)
+	reference, citations := c.retrieveKnowledge(assemblyCtx, request)
+	request.Messages = c.buildChatSessionQuery(assemblyCtx, session, msg, request, len(memory)+len(reference))
+	assemblySpan.SetAttributes(attribute.Int("xgpt3.history.messages", len(request.Messages)-originLen))
+	if memory != "" && getRequestTokens(*request)+len(memory)+request.MaxTokens <= c.maxCtxLength {
+		request.Messages = injectSystemMessage(request.Messages, memory)
+	}
+	if reference != "" && getRequestTokens(*request)+len(reference)+request.MaxTokens <= c.maxCtxLength {
+		request.Messages = injectSystemMessage(request.Messages, reference)
+		cc.Citations = citations
+	}
+	assemblySpan.End()
 	msgLen := getRequestTokens(*request)
 	c.logger.Debug().Msgf("Requested %d tokens (%d in your messages; %d for the chat completion)", msgLen+request.MaxTokens, msgLen, request.MaxTokens)
-	return session, msg, nil
+	return nil
 }
 
-func (c *Client) buildChatSessionQuery(ctx context.Context, session *conversation.Session, request *openai.ChatCompletionRequest) []openai.ChatCompletionMessage {
+// buildChatSessionQuery 拼接会话历史消息。current 为本次保存的用户消息，reserved 为需要为其他内容预留的长度
+func (c *Client) buildChatSessionQuery(ctx context.Context, session *conversation.Session, current *conversation.Message, request *openai.ChatCompletionRequest, reserved int) []openai.ChatCompletionMessage {
 	msgLen := getRequestTokens(*request)
 	if msgLen+request.MaxTokens > c.maxCtxLength {
 		c.logger.Debug().Msgf("Requested %d tokens (%d in your messages; %d for the chat completion), reduce messages", msgLen+request.MaxTokens, msgLen, request.MaxTokens)
+		c.metrics.truncated(requestKindChat)
 		return c.reduceRequestMessages(*request)
 	}
 
-	msgs, err := c.ch.ListLatestMessagesWithSpouse(ctx, session, request.User, c.maxTurn)
+	history, err := c.chatHistory(ctx, session, current, request.User)
 	if err != nil {
-		c.logger.Warn().Msgf("ListLatestMessagesWithSpouse failed: %s", err)
+		c.logger.Warn().Msgf("List session %d history failed: %s", session.ID, err)
 		return request.Messages
 	}
 
-	// 按照消息创建时间倒序排序
-	sort.Slice(msgs, func(i, j int) bool {
-		return msgs[i].CreatedAt.Sub(msgs[j].CreatedAt) > 0
-	})
-
-	// 按照消息长度重排历史消息
+	// 按照文本长度从最近的消息开始选取历史消息
 	pl := msgLen
-	selectedMsgs := append([]openai.ChatCompletionMessage{}, request.Messages...)
-	for _, m := range msgs {
-		var ccm openai.ChatCompletionMessage
-		if m.FromUserID == request.User {
-			ccm = openai.ChatCompletionMessage{
-				Role:    openai.ChatMessageRoleUser,
-				Content: m.Content,
-			}
-		} else {
-			ccm = openai.ChatCompletionMessage{
-				Role:    openai.ChatMessageRoleAssistant,
-				Content: m.Content,
-			}
+	budget := c.maxCtxLength - request.MaxTokens - reserved
+	start := len(history)
+	for ; start > 0; start-- {
+		l := len(messageText(history[start-1]))
+		if pl+l > budget {
+			c.metrics.truncated(requestKindChat)
+			break
 		}
+		pl += l
+	}
+	history = append([]openai.ChatCompletionMessage{}, history[start:]...)
 
-		if len(ccm.Content)+pl+request.MaxTokens <= c.maxCtxLength {
-			selectedMsgs = append([]openai.ChatCompletionMessage{ccm}, selectedMsgs...)
-			pl += len(ccm.Content)
-		} else {
-			break
+	// 剩余长度中从最近的消息开始保留图片，超出时先省略较早的图片
+	for i := len(history) - 1; i >= 0; i-- {
+		ccm := history[i]
+		if !hasImage(ccm) {
+			continue
+		}
+		if l := messageTokens(ccm) - len(messageText(ccm)); pl+l <= budget {
+			pl += l
+			continue
 		}
+		history[i] = withoutImages(ccm)
 	}
+	selectedMsgs := append(history, request.Messages...)
 
 	// 如果首个消息不是用户发出的，则忽略
 	if len(selectedMsgs) > 0 && selectedMsgs[0].Role != openai.ChatMessageRoleUser {
@@ -329,15 +577,99 @@ func (c *Client) buildChatSessionQuery(ctx context.Context, session *conversatio
 	return selectedMsgs
 }
 
-func (c *Client) postChatCompletion(ctx context.Context, request openai.ChatCompletionRequest, response openai.ChatCompletionResponse, session *conversation.Session, msg *conversation.Message, channel string) (*conversation.Message, error) {
+// chatHistory 返回按时间正序排列的会话历史消息。群聊会话包括其他成员的发言，
+// 否则只包括用户已经得到回复的提问和回复
+func (c *Client) chatHistory(ctx context.Context, session *conversation.Session, current *conversation.Message, userId string) ([]openai.ChatCompletionMessage, error) {
+	if session.GroupChat {
+		return c.listGroupHistory(ctx, session, current)
+	}
+	msgs, err := c.listHistory(ctx, session, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按照消息创建时间正序排序
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].CreatedAt.Before(msgs[j].CreatedAt)
+	})
+	history := make([]openai.ChatCompletionMessage, 0, len(msgs))
+	for _, m := range msgs {
+		role := openai.ChatMessageRoleAssistant
+		if m.FromUserID == userId {
+			role = openai.ChatMessageRoleUser
+		}
+		history = append(history, historyMessage(role, m))
+	}
+	return history, nil
+}
+
+func (c *Client) postChatCompletion(ctx context.Context, cc *ChatContext) error {
+	request, response := cc.Request, cc.Response
 	if len(response.Choices) == 0 {
-		return nil, fmt.Errorf("Empty GPT Choices")
+		return fmt.Errorf("Empty GPT Choices")
 	}
 
 	reply := response.Choices[0].Message.Content
-	m, err := c.ch.CreateSpouseMessage(ctx, session, channel, request.User, reply, msg)
+	m, err := c.createSpouseMessage(ctx, cc.Session, cc.Channel, request.User, reply, cc.Message)
 	if err != nil {
-		return nil, fmt.Errorf("create spouse message failed: %w", err)
+		return fmt.Errorf("create spouse message failed: %w", err)
 	}
-	return m, nil
+	cc.Reply = m
+
+	// 记录回复引用的资料
+	if len(cc.Citations) > 0 {
+		if err := c.saveCitations(ctx, m, cc.Citations); err != nil {
+			c.logger.Warn().Msgf("Save citations failed: %s", err)
+		}
+	}
+	c.emit(ctx, Event{Type: EventReplyStored, UserID: request.User, Channel: cc.Channel, Session: cc.Session, Message: m, Usage: response.Usage})
+	return nil
+}
+
+// newRedactVault 返回会话所有者的 vault。群聊中占位符属于群，群内的回复都能还原
+func (c *Client) newRedactVault(ctx context.Context, userId string) *redact.Vault {
+	if !c.redactor.Tokenize() {
+		return nil
+	}
+	if groupId := GroupID(ctx); groupId != "" {
+		return c.redactor.NewVault(groupId)
+	}
+	return c.redactor.NewVault(userId)
+}
+
+func (c *Client) redactCompletionRequest(ctx context.Context, request *openai.CompletionRequest) *redact.Vault {
+	if c.redactor == nil {
+		return nil
+	}
+	prompt, ok := request.Prompt.(string)
+	if !ok {
+		return nil
+	}
+	vault := c.newRedactVault(ctx, request.User)
+	request.Prompt = c.redactor.Redact(prompt, vault)
+	return vault
+}
+
+func (c *Client) redactChatRequest(ctx context.Context, request *openai.ChatCompletionRequest) *redact.Vault {
+	if c.redactor == nil {
+		return nil
+	}
+	vault := c.newRedactVault(ctx, request.User)
+	msgs := make([]openai.ChatCompletionMessage, len(request.Messages))
+	for i, m := range request.Messages {
+		m.Content = c.redactor.Redact(m.Content, vault)
+		if len(m.MultiContent) > 0 {
+			parts := make([]openai.ChatMessagePart, len(m.MultiContent))
+			for j, p := range m.MultiContent {
+				if p.Type == openai.ChatMessagePartTypeText {
+					p.Text = c.redactor.Redact(p.Text, vault)
+				}
+				parts[j] = p
+			}
+			m.MultiContent = parts
+		}
+		msgs[i] = m
+	}
+	request.Messages = msgs
+	return vault
 }
@@ -0,0 +1,154 @@
+package xgpt3_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+	"github.com/sashabaranov/go-openai"
+)
+
+func newTestClient(t *testing.T) (*xgpt3.Client, *xgpt3test.Server) {
+	t.Helper()
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	return xgpt3.NewClient(srv.Client(), memory.New()), srv
+}
+
+func chatRequest(user, content string) openai.ChatCompletionRequest {
+	return openai.ChatCompletionRequest{
+		Model:    openai.GPT3Dot5Turbo,
+		User:     user,
+		Messages: []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: content}},
+	}
+}
+
+func contents(msgs []openai.ChatCompletionMessage) []string {
+	result := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		result = append(result, m.Role+": "+m.Content)
+	}
+	return result
+}
+
+func TestChatCompletionHistory(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+	srv.Enqueue(xgpt3test.Response{Content: "你好，有什么可以帮你？"}, xgpt3test.Response{Content: "可以在设置页面重置"})
+
+	resp, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "你好，有什么可以帮你？" {
+		t.Fatalf("reply = %q", got)
+	}
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "怎么重置密码")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	got := strings.Join(contents(srv.LastRequest().Messages()), "\n")
+	want := "user: 你好\nassistant: 你好，有什么可以帮你？\nuser: 怎么重置密码"
+	if got != want {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestChatCompletionHistoryIsolatedByUser(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "alice 的问题")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("bob", "bob 的问题")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := contents(srv.LastRequest().Messages()); len(got) != 1 || got[0] != "user: bob 的问题" {
+		t.Fatalf("messages = %q", got)
+	}
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+	srv.Enqueue(xgpt3test.Response{Chunks: []string{"流式", "输出", "的回复"}})
+
+	req := chatRequest("alice", "你好")
+	req.Stream = true
+	stream, err := c.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream() error = %v", err)
+	}
+	var b strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		if len(chunk.Choices) > 0 {
+			b.WriteString(chunk.Choices[0].Delta.Content)
+		}
+	}
+	stream.Close()
+	if got := b.String(); got != "流式输出的回复" {
+		t.Fatalf("stream content = %q", got)
+	}
+
+	// 流式输出结束后保存完整的回复
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "继续")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	got := strings.Join(contents(srv.LastRequest().Messages()), "\n")
+	want := "user: 你好\nassistant: 流式输出的回复\nuser: 继续"
+	if got != want {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+}
+
+func TestChatCompletionError(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+	srv.Enqueue(xgpt3test.Response{StatusCode: http.StatusInternalServerError, ErrorMessage: "server error", ErrorType: "server_error"})
+
+	_, err := c.CreateChatCompletion(ctx, chatRequest("alice", "失败的问题"))
+	var apiErr *openai.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusInternalServerError {
+		t.Fatalf("CreateChatCompletion() error = %v, want 500 APIError", err)
+	}
+
+	// 没有回复的问题不会出现在之后的历史消息中
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "新的问题")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := contents(srv.LastRequest().Messages()); len(got) != 1 || got[0] != "user: 新的问题" {
+		t.Fatalf("messages = %q", got)
+	}
+}
+
+func TestCloseConversation(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "第一个会话")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if err := c.CloseConversation(ctx, "alice"); err != nil {
+		t.Fatalf("CloseConversation() error = %v", err)
+	}
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "第二个会话")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := contents(srv.LastRequest().Messages()); len(got) != 1 || got[0] != "user: 第二个会话" {
+		t.Fatalf("messages = %q", got)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// 支持的数据库驱动，需要在 main.go 中导入
+var supportedDrivers = []string{"mysql", "sqlite3"}
+
+type config struct {
+	// 监听地址
+	Addr string
+	// 模型服务类型：openai、azure、compatible
+	Provider string
+	// 模型服务地址，Azure 为资源地址
+	UpstreamBaseURL string
+	// Azure 接口版本
+	AzureAPIVersion string
+	// Azure 模型名称到部署名称的映射
+	AzureDeployments map[string]string
+	// OpenAI API Key，多个 Key 轮流使用
+	APIKeys []string
+	// 访问代理服务需要的 Key
+	AccessKeys []string
+	// 没有配置 AccessKeys 时仍然启动，任何人都可以使用上游 API Key
+	Insecure bool
+	// 数据库驱动：mysql、sqlite3
+	Driver string
+	// 数据库连接
+	DSN string
+	// 是否自动生成数据库表
+	Migrate bool
+	// 指定消息渠道的请求头
+	ChannelHeader string
+	// 默认消息渠道
+	DefaultChannel string
+	// 会话管理接口中携带用户令牌的请求头
+	UserTokenHeader string
+	// 签发用户令牌的密钥，为空时不开启会话管理接口
+	UserTokenSecret string
+	// 最多携带的历史对话轮数
+	MaxTurn int
+	// 生成会话标题使用的模型，为空时不生成
+	TitleModel string
+	// 微信公众号服务器配置中的 Token，为空时不开启公众号接入
+	WechatToken string
+	// 微信公众号消息加解密密钥
+	WechatAESKey string
+	// 微信公众号 AppID
+	WechatAppID string
+	// 微信公众号 AppSecret
+	WechatAppSecret string
+	// 微信公众号对话使用的模型
+	WechatModel string
+	// 聊天平台机器人对话使用的模型
+	BotModel string
+	// 机器人是否开启群聊模式，群内成员共用会话
+	BotGroupChat bool
+	// Slack Bot Token，为空时不开启 Slack 接入
+	SlackBotToken string
+	// Slack Signing Secret
+	SlackSigningSecret string
+	// Slack 机器人的用户Id，群聊模式下用于识别 @机器人 的消息
+	SlackBotUserID string
+	// Telegram Bot Token，为空时不开启 Telegram 接入
+	TelegramToken string
+	// Telegram webhook 的 secret_token
+	TelegramSecretToken string
+	// Telegram 机器人用户名
+	TelegramUsername string
+	// 飞书应用 AppID，为空时不开启飞书接入
+	FeishuAppID string
+	// 飞书应用 AppSecret
+	FeishuAppSecret string
+	// 飞书事件订阅的 Verification Token
+	FeishuVerificationToken string
+	// 飞书事件订阅的 Encrypt Key
+	FeishuEncryptKey string
+	// 飞书机器人的 open_id，用于识别 @机器人 的消息
+	FeishuBotOpenID string
+	// 飞书开放平台地址，Lark 国际版为 https://open.larksuite.com
+	FeishuBaseURL string
+}
+
+func loadConfig() config {
+	var c config
+	var apiKeys, accessKeys, deployments string
+	flag.StringVar(&c.Provider, "provider", env("XGPT3_PROVIDER", "openai"), "upstream provider: openai, azure or compatible")
+	flag.StringVar(&c.Addr, "addr", env("XGPT3_ADDR", ":8080"), "listen address")
+	flag.StringVar(&c.UpstreamBaseURL, "upstream", env("OPENAI_BASE_URL", "https://api.openai.com/v1"), "upstream base url")
+	flag.StringVar(&c.AzureAPIVersion, "azure-api-version", env("AZURE_OPENAI_API_VERSION", ""), "azure api version")
+	flag.StringVar(&deployments, "azure-deployments", env("AZURE_OPENAI_DEPLOYMENTS", ""), "comma separated model=deployment pairs")
+	flag.StringVar(&apiKeys, "api-keys", env("OPENAI_API_KEYS", os.Getenv("OPENAI_API_KEY")), "comma separated upstream api keys")
+	flag.StringVar(&accessKeys, "access-keys", env("XGPT3_ACCESS_KEYS", ""), "comma separated keys required to access the server")
+	flag.BoolVar(&c.Insecure, "insecure", envBool("XGPT3_INSECURE", false), "start without access keys, anyone who can reach the server spends the upstream api keys")
+	flag.StringVar(&c.Driver, "driver", env("XGPT3_DB_DRIVER", "mysql"), "database driver: "+strings.Join(supportedDrivers, ", "))
+	flag.StringVar(&c.DSN, "dsn", env("XGPT3_DB_DSN", ""), "database dsn")
+	flag.BoolVar(&c.Migrate, "migrate", envBool("XGPT3_DB_MIGRATE", false), "create database schema on startup")
+	flag.StringVar(&c.ChannelHeader, "channel-header", env("XGPT3_CHANNEL_HEADER", "X-Xgpt3-Channel"), "request header used to select the channel")
+	flag.StringVar(&c.DefaultChannel, "default-channel", env("XGPT3_DEFAULT_CHANNEL", "default"), "channel used when the header is absent")
+	flag.StringVar(&c.UserTokenHeader, "user-token-header", env("XGPT3_USER_TOKEN_HEADER", "X-Xgpt3-User-Token"), "request header carrying the signed user token in conversation api")
+	flag.StringVar(&c.UserTokenSecret, "user-token-secret", env("XGPT3_USER_TOKEN_SECRET", ""), "secret used to verify user tokens, conversation api is disabled when empty")
+	flag.IntVar(&c.MaxTurn, "max-turn", envInt("XGPT3_MAX_TURN", 10), "max history turns")
+	flag.StringVar(&c.TitleModel, "title-model", env("XGPT3_TITLE_MODEL", ""), "model used to generate session titles, disabled when empty")
+	flag.StringVar(&c.WechatToken, "wechat-token", env("WECHAT_TOKEN", ""), "wechat official account token, disabled when empty")
+	flag.StringVar(&c.WechatAESKey, "wechat-aes-key", env("WECHAT_ENCODING_AES_KEY", ""), "wechat official account EncodingAESKey")
+	flag.StringVar(&c.WechatAppID, "wechat-app-id", env("WECHAT_APP_ID", ""), "wechat official account appid")
+	flag.StringVar(&c.WechatAppSecret, "wechat-app-secret", env("WECHAT_APP_SECRET", ""), "wechat official account appsecret")
+	flag.StringVar(&c.WechatModel, "wechat-model", env("WECHAT_MODEL", "gpt-3.5-turbo"), "model used for wechat messages")
+	flag.StringVar(&c.BotModel, "bot-model", env("XGPT3_BOT_MODEL", "gpt-3.5-turbo"), "model used for slack, telegram and feishu bots")
+	flag.BoolVar(&c.BotGroupChat, "bot-group-chat", envBool("XGPT3_BOT_GROUP_CHAT", false), "share one conversation among group members and keep messages that do not mention the bot as context")
+	flag.StringVar(&c.SlackBotToken, "slack-bot-token", env("SLACK_BOT_TOKEN", ""), "slack bot token, disabled when empty")
+	flag.StringVar(&c.SlackSigningSecret, "slack-signing-secret", env("SLACK_SIGNING_SECRET", ""), "slack signing secret, required when slack is enabled")
+	flag.StringVar(&c.SlackBotUserID, "slack-bot-user-id", env("SLACK_BOT_USER_ID", ""), "slack bot user id, required to record channel messages in group chat mode")
+	flag.StringVar(&c.TelegramToken, "telegram-token", env("TELEGRAM_BOT_TOKEN", ""), "telegram bot token, disabled when empty")
+	flag.StringVar(&c.TelegramSecretToken, "telegram-secret-token", env("TELEGRAM_SECRET_TOKEN", ""), "telegram webhook secret token, required when telegram is enabled")
+	flag.StringVar(&c.TelegramUsername, "telegram-username", env("TELEGRAM_BOT_USERNAME", ""), "telegram bot username")
+	flag.StringVar(&c.FeishuAppID, "feishu-app-id", env("FEISHU_APP_ID", ""), "feishu app id, disabled when empty")
+	flag.StringVar(&c.FeishuAppSecret, "feishu-app-secret", env("FEISHU_APP_SECRET", ""), "feishu app secret")
+	flag.StringVar(&c.FeishuVerificationToken, "feishu-verification-token", env("FEISHU_VERIFICATION_TOKEN", ""), "feishu event verification token, required when feishu is enabled")
+	flag.StringVar(&c.FeishuEncryptKey, "feishu-encrypt-key", env("FEISHU_ENCRYPT_KEY", ""), "feishu event encrypt key")
+	flag.StringVar(&c.FeishuBotOpenID, "feishu-bot-open-id", env("FEISHU_BOT_OPEN_ID", ""), "feishu bot open_id, any mention triggers a reply when empty")
+	flag.StringVar(&c.FeishuBaseURL, "feishu-base-url", env("FEISHU_BASE_URL", "https://open.feishu.cn"), "feishu open platform base url")
+	flag.Parse()
+
+	c.APIKeys = splitList(apiKeys)
+	c.AccessKeys = splitList(accessKeys)
+	c.AzureDeployments = make(map[string]string)
+	for _, pair := range splitList(deployments) {
+		if model, deployment, ok := strings.Cut(pair, "="); ok {
+			c.AzureDeployments[strings.TrimSpace(model)] = strings.TrimSpace(deployment)
+		}
+	}
+	return c
+}
+
+// validate 检查启动必需的配置
+func (c config) validate() error {
+	if len(c.APIKeys) == 0 {
+		return errors.New("OpenAI api key is required")
+	}
+	if len(c.AccessKeys) == 0 && !c.Insecure {
+		return errors.New("access keys are required, use -insecure to start without them")
+	}
+	for _, d := range supportedDrivers {
+		if c.Driver == d {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported database driver %s, supported: %s", c.Driver, strings.Join(supportedDrivers, ", "))
+}
+
+func env(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+func envBool(key string, def bool) bool {
+	if v, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,82 @@
+// xgpt3-server 是兼容 OpenAI HTTP 接口的代理服务，根据请求中的 user 字段自动携带会话历史
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation/ent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/rs/zerolog/log"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func main() {
+	cfg := loadConfig()
+	if err := cfg.validate(); err != nil {
+		log.Fatal().Msgf("Invalid config: %s", err)
+	}
+	if len(cfg.AccessKeys) == 0 {
+		log.Warn().Msg("No access keys configured, the server is open to anyone")
+	}
+
+	// 连接数据库
+	entClient, err := chatent.Open(cfg.Driver, cfg.DSN)
+	if err != nil {
+		log.Fatal().Msgf("Open database failed: %s", err)
+	}
+	defer entClient.Close()
+
+	// 生成数据库表
+	if cfg.Migrate {
+		if err := entClient.Schema.Create(context.Background()); err != nil {
+			log.Fatal().Msgf("Create database schema failed: %s", err)
+		}
+	}
+
+	// 每个 API Key 对应一个 xgpt3 client，共用同一个 conversation handler 和处理中的请求
+	handler := ent.New(entClient)
+	inflight := xgpt3.NewInflightRequests()
+	clients := make([]*xgpt3.Client, 0, len(cfg.APIKeys))
+	for _, key := range cfg.APIKeys {
+		p, err := newProvider(cfg, key)
+		if err != nil {
+			log.Fatal().Msgf("Create provider failed: %s", err)
+		}
+		client := xgpt3.NewClientWithProvider(p, handler).WithMaxTurn(cfg.MaxTurn).WithInflightRequests(inflight)
+		if cfg.TitleModel != "" {
+			client.WithSessionTitles(cfg.TitleModel)
+		}
+		clients = append(clients, client)
+	}
+
+	s, err := newServer(cfg, clients, handler)
+	if err != nil {
+		log.Fatal().Msgf("Create server failed: %s", err)
+	}
+	log.Info().Msgf("xgpt3 server listening on %s", cfg.Addr)
+	if err := http.ListenAndServe(cfg.Addr, s.routes()); err != nil {
+		log.Fatal().Msgf("Server stopped: %s", err)
+	}
+}
+
+func newProvider(cfg config, apiKey string) (provider.Provider, error) {
+	switch cfg.Provider {
+	case "openai", "compatible":
+		return provider.NewCompatible(cfg.UpstreamBaseURL, apiKey), nil
+	case "azure":
+		return provider.NewAzure(provider.AzureConfig{
+			Endpoint:    cfg.UpstreamBaseURL,
+			APIKey:      apiKey,
+			APIVersion:  cfg.AzureAPIVersion,
+			Deployments: cfg.AzureDeployments,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %s", cfg.Provider)
+	}
+}
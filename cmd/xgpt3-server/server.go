@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/adapter"
+	"github.com/fanchunke/xgpt3/adapter/feishu"
+	"github.com/fanchunke/xgpt3/adapter/slack"
+	"github.com/fanchunke/xgpt3/adapter/telegram"
+	"github.com/fanchunke/xgpt3/adapter/wechat"
+	"github.com/fanchunke/xgpt3/api"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/rs/zerolog/log"
+	"github.com/sashabaranov/go-openai"
+)
+
+type server struct {
+	cfg      config
+	clients  []*xgpt3.Client
+	api      *api.Handler
+	adapters []adapter.Adapter
+	next     uint32
+}
+
+func newServer(cfg config, clients []*xgpt3.Client, ch conversation.Handler) (*server, error) {
+	s := &server{
+		cfg:     cfg,
+		clients: clients,
+	}
+	// 会话管理接口和机器人命令关闭、删除会话时通过 client 触发会话事件
+	if len(clients) > 0 {
+		ch = clients[0].SessionManager()
+	}
+	// 会话管理接口可以读取和删除任意用户的会话，需要 access key 和签名的用户令牌
+	if len(cfg.AccessKeys) > 0 && cfg.UserTokenSecret != "" {
+		s.api = api.New(ch, api.TokenAuthenticator(cfg.UserTokenHeader, []byte(cfg.UserTokenSecret)))
+	} else {
+		log.Info().Msg("Conversation api disabled, both access keys and user token secret are required")
+	}
+	if cfg.WechatToken != "" {
+		h, err := wechat.New(s, wechat.Config{
+			Token:          cfg.WechatToken,
+			EncodingAESKey: cfg.WechatAESKey,
+			AppID:          cfg.WechatAppID,
+			AppSecret:      cfg.WechatAppSecret,
+			Model:          cfg.WechatModel,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.adapters = append(s.adapters, h)
+	}
+
+	var platforms []adapter.Platform
+	if cfg.SlackBotToken != "" {
+		p, err := slack.New(slack.Config{
+			BotToken:      cfg.SlackBotToken,
+			SigningSecret: cfg.SlackSigningSecret,
+			BotUserID:     cfg.SlackBotUserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p)
+	}
+	if cfg.TelegramToken != "" {
+		p, err := telegram.New(telegram.Config{
+			Token:       cfg.TelegramToken,
+			SecretToken: cfg.TelegramSecretToken,
+			Username:    cfg.TelegramUsername,
+		})
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p)
+	}
+	if cfg.FeishuAppID != "" {
+		p, err := feishu.New(feishu.Config{
+			AppID:             cfg.FeishuAppID,
+			AppSecret:         cfg.FeishuAppSecret,
+			VerificationToken: cfg.FeishuVerificationToken,
+			EncryptKey:        cfg.FeishuEncryptKey,
+			BotOpenID:         cfg.FeishuBotOpenID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, p.WithAPI(cfg.FeishuBaseURL, http.DefaultClient))
+	}
+	sessions, _ := ch.(conversation.SessionManager)
+	for _, p := range platforms {
+		bot := adapter.New(s, p).WithModel(cfg.BotModel).WithSessions(sessions)
+		if cfg.BotGroupChat {
+			bot.WithGroupChat()
+		}
+		s.adapters = append(s.adapters, bot)
+	}
+	return s, nil
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", s.auth(s.handleChatCompletions))
+	mux.HandleFunc("/v1/completions", s.auth(s.handleCompletions))
+	if s.api != nil {
+		mux.Handle("/v1/conversations/", http.StripPrefix("/v1/conversations", s.auth(s.api.ServeHTTP)))
+	}
+	// 聊天平台的推送通过签名认证，不需要 access key
+	for _, a := range s.adapters {
+		mux.Handle("/"+a.Name(), a)
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// client 轮流使用不同 API Key 的客户端
+func (s *server) client() *xgpt3.Client {
+	n := atomic.AddUint32(&s.next, 1)
+	return s.clients[int(n-1)%len(s.clients)]
+}
+
+// 以下方法使 server 满足 adapter.Client，聊天平台的请求同样轮流使用不同的客户端
+func (s *server) CreateChatCompletionWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (openai.ChatCompletionResponse, error) {
+	return s.client().CreateChatCompletionWithChannel(ctx, request, channel)
+}
+
+func (s *server) CreateChatCompletionStreamWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (*xgpt3.ChatCompletionStream, error) {
+	return s.client().CreateChatCompletionStreamWithChannel(ctx, request, channel)
+}
+
+func (s *server) RecordGroupMessage(ctx context.Context, groupId, channel, userId, content string) error {
+	return s.client().RecordGroupMessage(ctx, groupId, channel, userId, content)
+}
+
+func (s *server) CloseConversationWithChannel(ctx context.Context, userId, channel string) error {
+	return s.client().CloseConversationWithChannel(ctx, userId, channel)
+}
+
+func (s *server) channel(r *http.Request) string {
+	if ch := r.Header.Get(s.cfg.ChannelHeader); ch != "" {
+		return ch
+	}
+	return s.cfg.DefaultChannel
+}
+
+// requestContext 携带 Idempotency-Key 请求头作为幂等键，重试的请求不会重复调用模型
+func requestContext(r *http.Request) context.Context {
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		return xgpt3.WithIdempotencyKey(r.Context(), key)
+	}
+	return r.Context()
+}
+
+func (s *server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.cfg.AccessKeys) == 0 {
+			next(w, r)
+			return
+		}
+		key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		for _, k := range s.cfg.AccessKeys {
+			if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+				next(w, r)
+				return
+			}
+		}
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "Incorrect API key provided")
+	}
+}
+
+func (s *server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+	var req openai.ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+
+	ctx, client, channel := requestContext(r), s.client(), s.channel(r)
+	// 没有 user 时无法关联会话，直接转发请求
+	if req.User == "" {
+		if req.Stream {
+			stream, err := client.Provider().CreateChatCompletionStream(ctx, req)
+			if err != nil {
+				writeUpstreamError(w, err)
+				return
+			}
+			defer stream.Close()
+			pipeStream(w, stream.Recv)
+			return
+		}
+		resp, err := client.Provider().CreateChatCompletion(ctx, req)
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	if req.Stream {
+		stream, err := client.CreateChatCompletionStreamWithChannel(ctx, req, channel)
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		defer stream.Close()
+		pipeStream(w, stream.Recv)
+		return
+	}
+	resp, err := client.CreateChatCompletionWithChannel(ctx, req, channel)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *server) handleCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+		return
+	}
+	var req openai.CompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("invalid request body: %s", err))
+		return
+	}
+
+	ctx, client, channel := requestContext(r), s.client(), s.channel(r)
+	// 没有 user 时无法关联会话，直接转发请求
+	if req.User == "" {
+		if req.Stream {
+			stream, err := client.Provider().CreateCompletionStream(ctx, req)
+			if err != nil {
+				writeUpstreamError(w, err)
+				return
+			}
+			defer stream.Close()
+			pipeStream(w, stream.Recv)
+			return
+		}
+		resp, err := client.Provider().CreateCompletion(ctx, req)
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	if req.Stream {
+		stream, err := client.CreateConversationCompletionStreamWithChannel(ctx, req, channel)
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		defer stream.Close()
+		pipeStream(w, stream.Recv)
+		return
+	}
+	resp, err := client.CreateConversationCompletionWithChannel(ctx, req, channel)
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pipeStream 以 SSE 格式输出流式结果
+func pipeStream[T any](w http.ResponseWriter, recv func() (T, error)) {
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		resp, err := recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Warn().Msgf("Stream failed: %s", err)
+			b, _ := json.Marshal(errorBody(err))
+			fmt.Fprintf(w, "data: %s\n\n", b)
+			break
+		}
+		b, err := json.Marshal(resp)
+		if err != nil {
+			log.Warn().Msgf("Marshal stream response failed: %s", err)
+			break
+		}
+		fmt.Fprintf(w, "data: %s\n\n", b)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+type errorResponse struct {
+	Error openai.APIError `json:"error"`
+}
+
+func errorBody(err error) errorResponse {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return errorResponse{Error: *apiErr}
+	}
+	return errorResponse{Error: openai.APIError{Type: "server_error", Message: err.Error()}}
+}
+
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.HTTPStatusCode > 0:
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr) && reqErr.HTTPStatusCode > 0:
+		status = reqErr.HTTPStatusCode
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, xgpt3.ErrDuplicateRequest):
+		status = http.StatusConflict
+	}
+	log.Warn().Msgf("Request failed: %s", err)
+	writeJSON(w, status, errorBody(err))
+}
+
+func writeError(w http.ResponseWriter, status int, typ, message string) {
+	writeJSON(w, status, errorResponse{Error: openai.APIError{Type: typ, Message: message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warn().Msgf("Write response failed: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+// xgpt3 是命令行对话客户端，会话历史默认保存在本地 SQLite 数据库中，方便调试 prompt
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/rs/zerolog"
+	"github.com/sashabaranov/go-openai"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// store 命令行需要的会话后端能力
+type store interface {
+	conversation.Handler
+	conversation.SessionManager
+}
+
+func main() {
+	home, _ := os.UserHomeDir()
+	var (
+		dbPath   = flag.String("db", filepath.Join(home, ".xgpt3", "history.db"), "sqlite database path")
+		inMemory = flag.Bool("memory", false, "keep history in memory only")
+		baseURL  = flag.String("base-url", os.Getenv("OPENAI_BASE_URL"), "OpenAI base url")
+		model    = flag.String("model", openai.GPT3Dot5Turbo, "chat model")
+		user     = flag.String("user", "cli", "user id used to store history")
+		system   = flag.String("system", "", "system prompt")
+		maxTurn  = flag.Int("max-turn", 10, "max history turns")
+	)
+	flag.Parse()
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		fmt.Fprintln(os.Stderr, "OPENAI_API_KEY is required")
+		os.Exit(1)
+	}
+
+	var s store
+	if *inMemory {
+		s = memory.New()
+	} else {
+		entClient, err := openSQLite(*dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Open database failed: %s\n", err)
+			os.Exit(1)
+		}
+		defer entClient.Close()
+		s = ent.New(entClient)
+	}
+
+	gptConfig := openai.DefaultConfig(apiKey)
+	if *baseURL != "" {
+		gptConfig.BaseURL = *baseURL
+	}
+	client := xgpt3.NewClient(openai.NewClientWithConfig(gptConfig), s).
+		WithMaxTurn(*maxTurn).
+		WithLogger(zerolog.Nop())
+
+	r := newREPL(client, s, *user, *model, os.Stdin, os.Stdout)
+	r.system = *system
+	client.Use(r.systemPrompt)
+	r.run(context.Background())
+}
+
+func openSQLite(path string) (*chatent.Client, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	client, err := chatent.Open("sqlite3", fmt.Sprintf("file:%s?cache=shared&_fk=1", path))
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Schema.Create(context.Background()); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
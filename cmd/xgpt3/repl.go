@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+)
+
+const help = `命令：
+  /new              开启新会话
+  /sessions         列出历史会话
+  /switch <id>      切换到指定会话
+  /regen            重新生成上一条回复
+  /system [prompt]  设置系统提示，不带参数时清除
+  /export [path]    导出当前会话为 Markdown，不指定路径时输出到终端
+  /help             显示帮助
+  /quit             退出`
+
+type repl struct {
+	client *xgpt3.Client
+	store  store
+	user   string
+	model  string
+	system string
+	in     *bufio.Reader
+	out    io.Writer
+}
+
+func newREPL(client *xgpt3.Client, s store, user, model string, in io.Reader, out io.Writer) *repl {
+	return &repl{
+		client: client,
+		store:  s,
+		user:   user,
+		model:  model,
+		in:     bufio.NewReader(in),
+		out:    out,
+	}
+}
+
+// systemPrompt 请求 OpenAI 前在拼接好的消息最前面加上系统提示，系统提示不会保存到会话历史
+func (r *repl) systemPrompt(next xgpt3.ChatHandler) xgpt3.ChatHandler {
+	return func(ctx context.Context, cc *xgpt3.ChatContext) error {
+		if cc.Stage == xgpt3.StageUpstream && r.system != "" {
+			system := openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: r.system}
+			cc.Request.Messages = append([]openai.ChatCompletionMessage{system}, cc.Request.Messages...)
+		}
+		return next(ctx, cc)
+	}
+}
+
+func (r *repl) run(ctx context.Context) {
+	fmt.Fprintln(r.out, "输入 /help 查看命令")
+	for {
+		fmt.Fprint(r.out, "> ")
+		line, err := r.in.ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(r.out)
+			return
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "/") {
+			r.chat(ctx, line)
+			continue
+		}
+
+		cmd, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+		switch cmd {
+		case "/new":
+			err = r.newSession(ctx)
+		case "/sessions":
+			err = r.listSessions(ctx)
+		case "/switch":
+			err = r.switchSession(ctx, arg)
+		case "/regen":
+			err = r.regenerate(ctx)
+		case "/system":
+			r.system = arg
+			fmt.Fprintln(r.out, "系统提示已更新")
+		case "/export":
+			err = r.export(ctx, arg)
+		case "/help":
+			fmt.Fprintln(r.out, help)
+		case "/quit", "/exit":
+			return
+		default:
+			err = fmt.Errorf("未知命令 %s，输入 /help 查看命令", cmd)
+		}
+		if err != nil {
+			fmt.Fprintf(r.out, "错误：%s\n", err)
+		}
+	}
+}
+
+func (r *repl) chat(ctx context.Context, content string) {
+	request := openai.ChatCompletionRequest{
+		Model: r.model,
+		User:  r.user,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: content},
+		},
+	}
+	stream, err := r.client.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		fmt.Fprintf(r.out, "错误：%s\n", err)
+		return
+	}
+	defer stream.Close()
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(r.out, "\n错误：%s\n", err)
+			return
+		}
+		if len(resp.Choices) > 0 {
+			fmt.Fprint(r.out, resp.Choices[0].Delta.Content)
+		}
+	}
+	fmt.Fprintln(r.out)
+}
+
+func (r *repl) currentSession(ctx context.Context) (*conversation.Session, error) {
+	session, err := r.store.GetLatestActiveSession(ctx, r.user)
+	if err != nil {
+		return nil, fmt.Errorf("当前没有会话")
+	}
+	return session, nil
+}
+
+func (r *repl) newSession(ctx context.Context) error {
+	if err := r.client.CloseConversation(ctx, r.user); err != nil {
+		return err
+	}
+	fmt.Fprintln(r.out, "已开启新会话")
+	return nil
+}
+
+func (r *repl) listSessions(ctx context.Context) error {
+	sessions, err := r.store.ListSessions(ctx, r.user, conversation.Page{Limit: 20})
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		mark := " "
+		if s.Status {
+			mark = "*"
+		}
+		title := s.Title
+		if title == "" {
+			title = r.preview(ctx, s)
+		}
+		fmt.Fprintf(r.out, "%s %4d  %s  %s\n", mark, s.ID, s.CreatedAt.Local().Format("2006-01-02 15:04"), title)
+	}
+	return nil
+}
+
+// preview 没有标题的会话用第一条消息作为预览
+func (r *repl) preview(ctx context.Context, s *conversation.Session) string {
+	msgs, err := r.store.ListMessages(ctx, s, conversation.Page{Limit: 1})
+	if err != nil || len(msgs) == 0 {
+		return ""
+	}
+	content := []rune(strings.ReplaceAll(msgs[0].Content, "\n", " "))
+	if len(content) > 40 {
+		return string(content[:40]) + "..."
+	}
+	return string(content)
+}
+
+func (r *repl) switchSession(ctx context.Context, arg string) error {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return fmt.Errorf("用法：/switch <id>")
+	}
+	if err := r.store.ReopenSession(ctx, r.user, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.out, "已切换到会话 %d\n", id)
+	return nil
+}
+
+// regenerate 删除上一轮对话，并用同样的问题重新请求
+func (r *repl) regenerate(ctx context.Context) error {
+	session, err := r.currentSession(ctx)
+	if err != nil {
+		return err
+	}
+	msgs, err := r.allMessages(ctx, session)
+	if err != nil {
+		return err
+	}
+	var last *conversation.Message
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].FromUserID == r.user {
+			last = msgs[i]
+			break
+		}
+	}
+	if last == nil {
+		return fmt.Errorf("当前会话没有可以重新生成的消息")
+	}
+	if err := r.store.DeleteMessage(ctx, session, last.ID); err != nil {
+		return err
+	}
+	r.chat(ctx, last.Content)
+	return nil
+}
+
+func (r *repl) export(ctx context.Context, path string) error {
+	session, err := r.currentSession(ctx)
+	if err != nil {
+		return err
+	}
+	msgs, err := r.allMessages(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	w := r.out
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	title := session.Title
+	if title == "" {
+		title = fmt.Sprintf("Session %d", session.ID)
+	}
+	fmt.Fprintf(w, "# %s\n\n", title)
+	if r.system != "" {
+		fmt.Fprintf(w, "**system**\n\n%s\n\n", r.system)
+	}
+	for _, m := range msgs {
+		role := "assistant"
+		if m.FromUserID == r.user {
+			role = "user"
+		}
+		fmt.Fprintf(w, "**%s**\n\n%s\n\n", role, m.Content)
+	}
+	if path != "" {
+		fmt.Fprintf(r.out, "已导出到 %s\n", path)
+	}
+	return nil
+}
+
+func (r *repl) allMessages(ctx context.Context, session *conversation.Session) ([]*conversation.Message, error) {
+	result := make([]*conversation.Message, 0)
+	page := conversation.Page{Limit: 500}
+	for {
+		msgs, err := r.store.ListMessages(ctx, session, page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, msgs...)
+		if len(msgs) < page.Limit {
+			return result, nil
+		}
+		last := msgs[len(msgs)-1]
+		page.After = &conversation.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
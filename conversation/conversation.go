@@ -2,16 +2,42 @@ package conversation
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrNotFound 会话或消息不存在
+var ErrNotFound = errors.New("conversation: not found")
+
+// ErrDuplicateKey 同一用户的幂等键已经存在，通常是多个实例同时处理了平台的重试请求
+var ErrDuplicateKey = errors.New("conversation: duplicate idempotency key")
+
+// DefaultChannel 默认消息渠道，Handler 中不带渠道的方法都作用于默认渠道
+const DefaultChannel = "default"
+
+// 消息内容片段的类型。text 和 image_url 与 OpenAI 多模态消息的类型一致，
+// audio 为语音消息的原始音频，只用于记录，不会发送给模型
+const (
+	ContentPartText  = "text"
+	ContentPartImage = "image_url"
+	ContentPartAudio = "audio"
+)
+
 type Session struct {
 	// ID of the session.
 	ID int `json:"id,omitempty"`
 	// 用户Id
 	UserID string `json:"user_id,omitempty"`
+	// 消息渠道
+	Channel string `json:"channel,omitempty"`
 	// 会话是否开启
 	Status bool `json:"status,omitempty"`
+	// 会话标题
+	Title string `json:"title,omitempty"`
+	// 是否为群聊会话，群聊会话的 UserID 为群Id
+	GroupChat bool `json:"group_chat,omitempty"`
+	// 群聊会话的参与者
+	Participants []string `json:"participants,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -33,6 +59,64 @@ type Message struct {
 	Content string `json:"content,omitempty"`
 	// SpouseID holds the value of the "spouse_id" field.
 	SpouseID int `json:"spouse_id,omitempty"`
+	// 回复引用的资料
+	Citations []Citation `json:"citations,omitempty"`
+	// 多模态消息的内容片段，Content 为其中文本片段的拼接
+	Parts []ContentPart `json:"parts,omitempty"`
+	// 幂等键，通常是平台的消息Id
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// 消息所属用户的数据密钥已删除，内容无法解密，Content 和 Parts 为空
+	Shredded bool `json:"shredded,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// ContentPart 多模态消息的内容片段
+type ContentPart struct {
+	// 片段类型：text、image_url 或 audio
+	Type string `json:"type"`
+	// 文本片段的内容
+	Text string `json:"text,omitempty"`
+	// 图片地址，可以是 http(s) 地址或 base64 编码的 data URL
+	ImageURL string `json:"image_url,omitempty"`
+	// 图片精度：low、high 或 auto
+	Detail string `json:"detail,omitempty"`
+	// 音频的引用，例如对象存储地址或聊天平台的媒体Id
+	AudioURL string `json:"audio_url,omitempty"`
+}
+
+type Citation struct {
+	// 引用编号
+	Index int `json:"index"`
+	// 资料来源
+	Source string `json:"source,omitempty"`
+	// 资料所在章节
+	Heading string `json:"heading,omitempty"`
+	// 资料片段的向量Id
+	EmbeddingID int `json:"embedding_id,omitempty"`
+	// 资料片段内容
+	Content string `json:"content,omitempty"`
+	// 相似度
+	Score float32 `json:"score,omitempty"`
+}
+
+type Embedding struct {
+	// ID of the embedding.
+	ID int `json:"id,omitempty"`
+	// 命名空间
+	Namespace string `json:"namespace,omitempty"`
+	// 用户Id
+	UserID string `json:"user_id,omitempty"`
+	// 会话Id
+	SessionID int `json:"session_id,omitempty"`
+	// 消息Id
+	MessageID int `json:"message_id,omitempty"`
+	// 向量对应的文本
+	Content string `json:"content,omitempty"`
+	// 向量
+	Vector []float32 `json:"vector,omitempty"`
+	// 附加信息
+	Metadata map[string]string `json:"metadata,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 }
@@ -42,7 +126,7 @@ type Handler interface {
 	CreateSession(ctx context.Context, userId string) (*Session, error)
 	// 关闭会话
 	CloseSession(ctx context.Context, userId string) error
-	// 获取最近一次开启的会话
+	// 获取最近一次开启的会话，没有开启的会话时返回 ErrNotFound
 	GetLatestActiveSession(ctx context.Context, userId string) (*Session, error)
 	// 创建消息
 	CreateMessage(ctx context.Context, session *Session, fromUserId, toUserId string, content string) (*Message, error)
@@ -51,3 +135,116 @@ type Handler interface {
 	// 获取会话内最近的消息列表
 	ListLatestMessagesWithSpouse(ctx context.Context, session *Session, userId string, turns int) ([]*Message, error)
 }
+
+// ChannelHandler 按 (用户, 渠道) 管理会话。同一用户在不同渠道中的会话相互独立
+type ChannelHandler interface {
+	// 在指定渠道中创建会话
+	CreateChannelSession(ctx context.Context, userId, channel string) (*Session, error)
+	// 关闭用户在指定渠道中的会话
+	CloseChannelSession(ctx context.Context, userId, channel string) error
+	// 获取用户在指定渠道中最近一次开启的会话，没有开启的会话时返回 ErrNotFound
+	GetLatestActiveChannelSession(ctx context.Context, userId, channel string) (*Session, error)
+}
+
+// GroupHandler 管理群聊会话。群聊会话的 UserID 为群Id，会话内有多个用户发言：
+// 用户消息的 FromUserID 为发言的用户，回复消息的 FromUserID 为渠道
+type GroupHandler interface {
+	// 在指定渠道中创建群聊会话
+	CreateGroupSession(ctx context.Context, groupId, channel string) (*Session, error)
+	// 获取群聊在指定渠道中最近一次开启的会话
+	GetLatestActiveGroupSession(ctx context.Context, groupId, channel string) (*Session, error)
+	// 将用户加入会话的参与者列表，已经在列表中时不做处理
+	AddParticipant(ctx context.Context, session *Session, userId string) error
+	// 获取群聊会话内最近的消息，包括没有回复的消息，按创建时间正序
+	ListLatestGroupMessages(ctx context.Context, session *Session, limit int) ([]*Message, error)
+}
+
+// CitationStore 保存回复引用的资料，用于审计
+type CitationStore interface {
+	// 保存回复消息引用的资料
+	SaveCitations(ctx context.Context, message *Message, citations []Citation) error
+}
+
+// ContentPartStore 保存多模态消息的内容片段，用于在之后的对话中还原图片等非文本内容
+type ContentPartStore interface {
+	// 保存会话内消息的内容片段
+	SaveContentParts(ctx context.Context, session *Session, message *Message, parts []ContentPart) error
+}
+
+// EmbeddingStore 持久化向量，供语义缓存、长期记忆等功能使用
+type EmbeddingStore interface {
+	// 保存向量
+	CreateEmbedding(ctx context.Context, e *Embedding) (*Embedding, error)
+	// 获取命名空间下的所有向量
+	ListEmbeddings(ctx context.Context, namespace string) ([]*Embedding, error)
+}
+
+// EmbeddingDeleter 删除向量，知识库重新导入资料时删除旧的片段
+type EmbeddingDeleter interface {
+	// 删除命名空间下指定Id的向量
+	DeleteEmbeddings(ctx context.Context, namespace string, ids []int) error
+}
+
+// IdempotencyStore 按幂等键保存和查询用户消息，用于吸收聊天平台的重试请求
+type IdempotencyStore interface {
+	// 创建带幂等键的用户消息。同一用户的幂等键唯一，已存在时返回 ErrDuplicateKey
+	CreateMessageWithKey(ctx context.Context, session *Session, fromUserId, toUserId, content, key string) (*Message, error)
+	// 按幂等键获取用户发送的消息及其回复，尚未回复时 reply 为空，消息不存在时返回 ErrNotFound
+	GetMessageByKey(ctx context.Context, userId, key string) (msg *Message, reply *Message, err error)
+}
+
+// UserShredder 删除用户的数据密钥 (crypto-shredding)，之后该用户的历史消息无法再解密
+type UserShredder interface {
+	// 删除用户的数据密钥，以及由用户消息生成的缓存、记忆等数据
+	ShredUser(ctx context.Context, userId string) error
+}
+
+// Cursor 分页游标，指向上一页的最后一条记录
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// Page 分页参数，After 为空时从第一条记录开始
+type Page struct {
+	After *Cursor
+	Limit int
+}
+
+// SessionManager 按会话Id管理用户的会话，供会话管理接口使用
+type SessionManager interface {
+	// 获取用户的会话列表，按创建时间倒序，不包含已删除的会话
+	ListSessions(ctx context.Context, userId string, page Page) ([]*Session, error)
+	// 获取用户的会话，会话不存在或已删除时返回 ErrNotFound
+	GetSession(ctx context.Context, userId string, sessionId int) (*Session, error)
+	// 获取会话内的消息，按创建时间正序
+	ListMessages(ctx context.Context, session *Session, page Page) ([]*Message, error)
+	// 关闭指定会话
+	CloseSessionByID(ctx context.Context, userId string, sessionId int) error
+	// 重新开启指定会话，同时关闭用户在同一渠道中的其他会话，之后的对话将在该会话中继续
+	ReopenSession(ctx context.Context, userId string, sessionId int) error
+	// 修改会话标题
+	RenameSession(ctx context.Context, userId string, sessionId int, title string) error
+	// 删除会话
+	DeleteSession(ctx context.Context, userId string, sessionId int) error
+	// 删除会话内的消息及其配对消息
+	DeleteMessage(ctx context.Context, session *Session, messageId int) error
+}
+
+// SessionFilter 会话筛选条件，零值表示不限制
+type SessionFilter struct {
+	// 用户Id
+	UserID string
+	// 消息渠道
+	Channel string
+	// 创建时间不早于
+	Since time.Time
+	// 创建时间早于
+	Until time.Time
+}
+
+// SessionScanner 按条件遍历所有用户的会话，供导出等批量任务使用
+type SessionScanner interface {
+	// 按创建时间正序获取会话，不包含已删除的会话
+	ScanSessions(ctx context.Context, filter SessionFilter, page Page) ([]*Session, error)
+}
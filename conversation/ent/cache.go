@@ -0,0 +1,78 @@
+package ent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fanchunke/xgpt3/cache"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+// ResponseCache 基于 response_caches 表的缓存存储
+type ResponseCache struct {
+	client *chatent.Client
+	ttl    time.Duration
+	keys   KeyProvider
+}
+
+// NewResponseCache 创建缓存存储。ttl 为 0 时缓存不过期
+func NewResponseCache(client *chatent.Client, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{client: client, ttl: ttl}
+}
+
+// WithEncryption 使用缓存所属用户的数据密钥加密缓存的回复，与 ConversationHandler 使用同一个 KeyProvider
+func (c *ResponseCache) WithEncryption(kp KeyProvider) *ResponseCache {
+	c.keys = kp
+	return c
+}
+
+func (c *ResponseCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	r, err := c.client.ResponseCache.
+		Query().
+		Where(responsecache.KeyEQ(key)).
+		Only(ctx)
+	if chatent.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("query response cache failed: %w", err)
+	}
+	if c.ttl > 0 && time.Since(r.UpdatedAt) > c.ttl {
+		return nil, false, nil
+	}
+	value, err := decryptContent(ctx, c.keys, r.UserID, r.Value)
+	// 用户密钥已删除，缓存的回复无法再使用
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return []byte(value), true, nil
+}
+
+func (c *ResponseCache) Set(ctx context.Context, key string, value []byte) error {
+	owner := cache.Owner(ctx)
+	if c.keys != nil && owner == "" {
+		return errors.New("response cache owner is required when encryption is enabled")
+	}
+	encrypted, err := encryptContent(ctx, c.keys, owner, string(value))
+	if err != nil {
+		return err
+	}
+	err = c.client.ResponseCache.
+		Create().
+		SetKey(key).
+		SetValue(encrypted).
+		SetUserID(owner).
+		OnConflictColumns(responsecache.FieldKey).
+		UpdateNewValues().
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("save response cache failed: %w", err)
+	}
+	return nil
+}
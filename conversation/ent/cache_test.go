@@ -0,0 +1,47 @@
+package ent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/cache"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+func TestResponseCacheEncryption(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	kp, err := NewKeyProvider(client, []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewKeyProvider() error = %v", err)
+	}
+	c := NewResponseCache(client, 0).WithEncryption(kp)
+
+	if err := c.Set(ctx, "key", []byte("回复内容")); err == nil {
+		t.Fatal("Set() without owner error = nil")
+	}
+	if err := c.Set(cache.WithOwner(ctx, "alice"), "key", []byte("回复内容")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	r, err := client.ResponseCache.Query().Where(responsecache.KeyEQ("key")).Only(ctx)
+	if err != nil {
+		t.Fatalf("query response cache failed: %v", err)
+	}
+	if !strings.HasPrefix(r.Value, encryptedPrefix) {
+		t.Fatalf("stored value = %q, want ciphertext", r.Value)
+	}
+
+	got, ok, err := c.Get(ctx, "key")
+	if err != nil || !ok || string(got) != "回复内容" {
+		t.Fatalf("Get() = %q, %v, %v", got, ok, err)
+	}
+
+	// 用户密钥删除后缓存不再命中
+	if err := kp.DeleteKeys(ctx, "alice"); err != nil {
+		t.Fatalf("DeleteKeys() error = %v", err)
+	}
+	if _, ok, err := c.Get(ctx, "key"); err != nil || ok {
+		t.Fatalf("Get() after shred = %v, %v", ok, err)
+	}
+}
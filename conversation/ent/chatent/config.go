@@ -31,12 +31,18 @@ type config struct {
 // hooks and interceptors per client, for fast access.
 type (
 	hooks struct {
-		Message []ent.Hook
-		Session []ent.Hook
+		DataKey       []ent.Hook
+		Embedding     []ent.Hook
+		Message       []ent.Hook
+		ResponseCache []ent.Hook
+		Session       []ent.Hook
 	}
 	inters struct {
-		Message []ent.Interceptor
-		Session []ent.Interceptor
+		DataKey       []ent.Interceptor
+		Embedding     []ent.Interceptor
+		Message       []ent.Interceptor
+		ResponseCache []ent.Interceptor
+		Session       []ent.Interceptor
 	}
 )
 
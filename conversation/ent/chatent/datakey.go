@@ -0,0 +1,136 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+)
+
+// DataKey is the model entity for the DataKey schema.
+type DataKey struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// 用户Id
+	UserID string `json:"user_id,omitempty"`
+	// 密钥版本
+	Version int `json:"version,omitempty"`
+	// 主密钥加密后的数据密钥
+	WrappedKey []byte `json:"-"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*DataKey) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case datakey.FieldWrappedKey:
+			values[i] = new([]byte)
+		case datakey.FieldID, datakey.FieldVersion:
+			values[i] = new(sql.NullInt64)
+		case datakey.FieldUserID:
+			values[i] = new(sql.NullString)
+		case datakey.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			return nil, fmt.Errorf("unexpected column %q for type DataKey", columns[i])
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the DataKey fields.
+func (dk *DataKey) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case datakey.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			dk.ID = int(value.Int64)
+		case datakey.FieldUserID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				dk.UserID = value.String
+			}
+		case datakey.FieldVersion:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field version", values[i])
+			} else if value.Valid {
+				dk.Version = int(value.Int64)
+			}
+		case datakey.FieldWrappedKey:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field wrapped_key", values[i])
+			} else if value != nil {
+				dk.WrappedKey = *value
+			}
+		case datakey.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				dk.CreatedAt = value.Time
+			}
+		}
+	}
+	return nil
+}
+
+// Update returns a builder for updating this DataKey.
+// Note that you need to call DataKey.Unwrap() before calling this method if this DataKey
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (dk *DataKey) Update() *DataKeyUpdateOne {
+	return NewDataKeyClient(dk.config).UpdateOne(dk)
+}
+
+// Unwrap unwraps the DataKey entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (dk *DataKey) Unwrap() *DataKey {
+	_tx, ok := dk.config.driver.(*txDriver)
+	if !ok {
+		panic("chatent: DataKey is not a transactional entity")
+	}
+	dk.config.driver = _tx.drv
+	return dk
+}
+
+// String implements the fmt.Stringer.
+func (dk *DataKey) String() string {
+	var builder strings.Builder
+	builder.WriteString("DataKey(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", dk.ID))
+	builder.WriteString("user_id=")
+	builder.WriteString(dk.UserID)
+	builder.WriteString(", ")
+	builder.WriteString("version=")
+	builder.WriteString(fmt.Sprintf("%v", dk.Version))
+	builder.WriteString(", ")
+	builder.WriteString("wrapped_key=<sensitive>")
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(dk.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// DataKeys is a parsable slice of DataKey.
+type DataKeys []*DataKey
+
+func (dk DataKeys) config(cfg config) {
+	for _i := range dk {
+		dk[_i].config = cfg
+	}
+}
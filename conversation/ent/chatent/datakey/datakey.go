@@ -0,0 +1,48 @@
+// Code generated by ent, DO NOT EDIT.
+
+package datakey
+
+import (
+	"time"
+)
+
+const (
+	// Label holds the string label denoting the datakey type in the database.
+	Label = "data_key"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldUserID holds the string denoting the user_id field in the database.
+	FieldUserID = "user_id"
+	// FieldVersion holds the string denoting the version field in the database.
+	FieldVersion = "version"
+	// FieldWrappedKey holds the string denoting the wrapped_key field in the database.
+	FieldWrappedKey = "wrapped_key"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// Table holds the table name of the datakey in the database.
+	Table = "data_keys"
+)
+
+// Columns holds all SQL columns for datakey fields.
+var Columns = []string{
+	FieldID,
+	FieldUserID,
+	FieldVersion,
+	FieldWrappedKey,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
@@ -0,0 +1,623 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+)
+
+// DataKeyCreate is the builder for creating a DataKey entity.
+type DataKeyCreate struct {
+	config
+	mutation *DataKeyMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetUserID sets the "user_id" field.
+func (dkc *DataKeyCreate) SetUserID(s string) *DataKeyCreate {
+	dkc.mutation.SetUserID(s)
+	return dkc
+}
+
+// SetVersion sets the "version" field.
+func (dkc *DataKeyCreate) SetVersion(i int) *DataKeyCreate {
+	dkc.mutation.SetVersion(i)
+	return dkc
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (dkc *DataKeyCreate) SetWrappedKey(b []byte) *DataKeyCreate {
+	dkc.mutation.SetWrappedKey(b)
+	return dkc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (dkc *DataKeyCreate) SetCreatedAt(t time.Time) *DataKeyCreate {
+	dkc.mutation.SetCreatedAt(t)
+	return dkc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (dkc *DataKeyCreate) SetNillableCreatedAt(t *time.Time) *DataKeyCreate {
+	if t != nil {
+		dkc.SetCreatedAt(*t)
+	}
+	return dkc
+}
+
+// Mutation returns the DataKeyMutation object of the builder.
+func (dkc *DataKeyCreate) Mutation() *DataKeyMutation {
+	return dkc.mutation
+}
+
+// Save creates the DataKey in the database.
+func (dkc *DataKeyCreate) Save(ctx context.Context) (*DataKey, error) {
+	dkc.defaults()
+	return withHooks[*DataKey, DataKeyMutation](ctx, dkc.sqlSave, dkc.mutation, dkc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (dkc *DataKeyCreate) SaveX(ctx context.Context) *DataKey {
+	v, err := dkc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dkc *DataKeyCreate) Exec(ctx context.Context) error {
+	_, err := dkc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dkc *DataKeyCreate) ExecX(ctx context.Context) {
+	if err := dkc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (dkc *DataKeyCreate) defaults() {
+	if _, ok := dkc.mutation.CreatedAt(); !ok {
+		v := datakey.DefaultCreatedAt()
+		dkc.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (dkc *DataKeyCreate) check() error {
+	if _, ok := dkc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`chatent: missing required field "DataKey.user_id"`)}
+	}
+	if _, ok := dkc.mutation.Version(); !ok {
+		return &ValidationError{Name: "version", err: errors.New(`chatent: missing required field "DataKey.version"`)}
+	}
+	if _, ok := dkc.mutation.WrappedKey(); !ok {
+		return &ValidationError{Name: "wrapped_key", err: errors.New(`chatent: missing required field "DataKey.wrapped_key"`)}
+	}
+	if _, ok := dkc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`chatent: missing required field "DataKey.created_at"`)}
+	}
+	return nil
+}
+
+func (dkc *DataKeyCreate) sqlSave(ctx context.Context) (*DataKey, error) {
+	if err := dkc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := dkc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, dkc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	dkc.mutation.id = &_node.ID
+	dkc.mutation.done = true
+	return _node, nil
+}
+
+func (dkc *DataKeyCreate) createSpec() (*DataKey, *sqlgraph.CreateSpec) {
+	var (
+		_node = &DataKey{config: dkc.config}
+		_spec = &sqlgraph.CreateSpec{
+			Table: datakey.Table,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: datakey.FieldID,
+			},
+		}
+	)
+	_spec.OnConflict = dkc.conflict
+	if value, ok := dkc.mutation.UserID(); ok {
+		_spec.SetField(datakey.FieldUserID, field.TypeString, value)
+		_node.UserID = value
+	}
+	if value, ok := dkc.mutation.Version(); ok {
+		_spec.SetField(datakey.FieldVersion, field.TypeInt, value)
+		_node.Version = value
+	}
+	if value, ok := dkc.mutation.WrappedKey(); ok {
+		_spec.SetField(datakey.FieldWrappedKey, field.TypeBytes, value)
+		_node.WrappedKey = value
+	}
+	if value, ok := dkc.mutation.CreatedAt(); ok {
+		_spec.SetField(datakey.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.DataKey.Create().
+//		SetUserID(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.DataKeyUpsert) {
+//			SetUserID(v+v).
+//		}).
+//		Exec(ctx)
+func (dkc *DataKeyCreate) OnConflict(opts ...sql.ConflictOption) *DataKeyUpsertOne {
+	dkc.conflict = opts
+	return &DataKeyUpsertOne{
+		create: dkc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.DataKey.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (dkc *DataKeyCreate) OnConflictColumns(columns ...string) *DataKeyUpsertOne {
+	dkc.conflict = append(dkc.conflict, sql.ConflictColumns(columns...))
+	return &DataKeyUpsertOne{
+		create: dkc,
+	}
+}
+
+type (
+	// DataKeyUpsertOne is the builder for "upsert"-ing
+	//  one DataKey node.
+	DataKeyUpsertOne struct {
+		create *DataKeyCreate
+	}
+
+	// DataKeyUpsert is the "OnConflict" setter.
+	DataKeyUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetUserID sets the "user_id" field.
+func (u *DataKeyUpsert) SetUserID(v string) *DataKeyUpsert {
+	u.Set(datakey.FieldUserID, v)
+	return u
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *DataKeyUpsert) UpdateUserID() *DataKeyUpsert {
+	u.SetExcluded(datakey.FieldUserID)
+	return u
+}
+
+// SetVersion sets the "version" field.
+func (u *DataKeyUpsert) SetVersion(v int) *DataKeyUpsert {
+	u.Set(datakey.FieldVersion, v)
+	return u
+}
+
+// UpdateVersion sets the "version" field to the value that was provided on create.
+func (u *DataKeyUpsert) UpdateVersion() *DataKeyUpsert {
+	u.SetExcluded(datakey.FieldVersion)
+	return u
+}
+
+// AddVersion adds v to the "version" field.
+func (u *DataKeyUpsert) AddVersion(v int) *DataKeyUpsert {
+	u.Add(datakey.FieldVersion, v)
+	return u
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (u *DataKeyUpsert) SetWrappedKey(v []byte) *DataKeyUpsert {
+	u.Set(datakey.FieldWrappedKey, v)
+	return u
+}
+
+// UpdateWrappedKey sets the "wrapped_key" field to the value that was provided on create.
+func (u *DataKeyUpsert) UpdateWrappedKey() *DataKeyUpsert {
+	u.SetExcluded(datakey.FieldWrappedKey)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.DataKey.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *DataKeyUpsertOne) UpdateNewValues() *DataKeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.CreatedAt(); exists {
+			s.SetIgnore(datakey.FieldCreatedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.DataKey.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *DataKeyUpsertOne) Ignore() *DataKeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *DataKeyUpsertOne) DoNothing() *DataKeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the DataKeyCreate.OnConflict
+// documentation for more info.
+func (u *DataKeyUpsertOne) Update(set func(*DataKeyUpsert)) *DataKeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&DataKeyUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetUserID sets the "user_id" field.
+func (u *DataKeyUpsertOne) SetUserID(v string) *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.SetUserID(v)
+	})
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *DataKeyUpsertOne) UpdateUserID() *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.UpdateUserID()
+	})
+}
+
+// SetVersion sets the "version" field.
+func (u *DataKeyUpsertOne) SetVersion(v int) *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.SetVersion(v)
+	})
+}
+
+// AddVersion adds v to the "version" field.
+func (u *DataKeyUpsertOne) AddVersion(v int) *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.AddVersion(v)
+	})
+}
+
+// UpdateVersion sets the "version" field to the value that was provided on create.
+func (u *DataKeyUpsertOne) UpdateVersion() *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.UpdateVersion()
+	})
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (u *DataKeyUpsertOne) SetWrappedKey(v []byte) *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.SetWrappedKey(v)
+	})
+}
+
+// UpdateWrappedKey sets the "wrapped_key" field to the value that was provided on create.
+func (u *DataKeyUpsertOne) UpdateWrappedKey() *DataKeyUpsertOne {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.UpdateWrappedKey()
+	})
+}
+
+// Exec executes the query.
+func (u *DataKeyUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("chatent: missing options for DataKeyCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *DataKeyUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *DataKeyUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *DataKeyUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// DataKeyCreateBulk is the builder for creating many DataKey entities in bulk.
+type DataKeyCreateBulk struct {
+	config
+	builders []*DataKeyCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the DataKey entities in the database.
+func (dkcb *DataKeyCreateBulk) Save(ctx context.Context) ([]*DataKey, error) {
+	specs := make([]*sqlgraph.CreateSpec, len(dkcb.builders))
+	nodes := make([]*DataKey, len(dkcb.builders))
+	mutators := make([]Mutator, len(dkcb.builders))
+	for i := range dkcb.builders {
+		func(i int, root context.Context) {
+			builder := dkcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*DataKeyMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				nodes[i], specs[i] = builder.createSpec()
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, dkcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = dkcb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, dkcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, dkcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dkcb *DataKeyCreateBulk) SaveX(ctx context.Context) []*DataKey {
+	v, err := dkcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (dkcb *DataKeyCreateBulk) Exec(ctx context.Context) error {
+	_, err := dkcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dkcb *DataKeyCreateBulk) ExecX(ctx context.Context) {
+	if err := dkcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.DataKey.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.DataKeyUpsert) {
+//			SetUserID(v+v).
+//		}).
+//		Exec(ctx)
+func (dkcb *DataKeyCreateBulk) OnConflict(opts ...sql.ConflictOption) *DataKeyUpsertBulk {
+	dkcb.conflict = opts
+	return &DataKeyUpsertBulk{
+		create: dkcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.DataKey.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (dkcb *DataKeyCreateBulk) OnConflictColumns(columns ...string) *DataKeyUpsertBulk {
+	dkcb.conflict = append(dkcb.conflict, sql.ConflictColumns(columns...))
+	return &DataKeyUpsertBulk{
+		create: dkcb,
+	}
+}
+
+// DataKeyUpsertBulk is the builder for "upsert"-ing
+// a bulk of DataKey nodes.
+type DataKeyUpsertBulk struct {
+	create *DataKeyCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.DataKey.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *DataKeyUpsertBulk) UpdateNewValues() *DataKeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.CreatedAt(); exists {
+				s.SetIgnore(datakey.FieldCreatedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.DataKey.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *DataKeyUpsertBulk) Ignore() *DataKeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *DataKeyUpsertBulk) DoNothing() *DataKeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the DataKeyCreateBulk.OnConflict
+// documentation for more info.
+func (u *DataKeyUpsertBulk) Update(set func(*DataKeyUpsert)) *DataKeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&DataKeyUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetUserID sets the "user_id" field.
+func (u *DataKeyUpsertBulk) SetUserID(v string) *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.SetUserID(v)
+	})
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *DataKeyUpsertBulk) UpdateUserID() *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.UpdateUserID()
+	})
+}
+
+// SetVersion sets the "version" field.
+func (u *DataKeyUpsertBulk) SetVersion(v int) *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.SetVersion(v)
+	})
+}
+
+// AddVersion adds v to the "version" field.
+func (u *DataKeyUpsertBulk) AddVersion(v int) *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.AddVersion(v)
+	})
+}
+
+// UpdateVersion sets the "version" field to the value that was provided on create.
+func (u *DataKeyUpsertBulk) UpdateVersion() *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.UpdateVersion()
+	})
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (u *DataKeyUpsertBulk) SetWrappedKey(v []byte) *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.SetWrappedKey(v)
+	})
+}
+
+// UpdateWrappedKey sets the "wrapped_key" field to the value that was provided on create.
+func (u *DataKeyUpsertBulk) UpdateWrappedKey() *DataKeyUpsertBulk {
+	return u.Update(func(s *DataKeyUpsert) {
+		s.UpdateWrappedKey()
+	})
+}
+
+// Exec executes the query.
+func (u *DataKeyUpsertBulk) Exec(ctx context.Context) error {
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("chatent: OnConflict was set for builder %d. Set it on the DataKeyCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("chatent: missing options for DataKeyCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *DataKeyUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
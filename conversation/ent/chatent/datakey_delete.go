@@ -0,0 +1,96 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+)
+
+// DataKeyDelete is the builder for deleting a DataKey entity.
+type DataKeyDelete struct {
+	config
+	hooks    []Hook
+	mutation *DataKeyMutation
+}
+
+// Where appends a list predicates to the DataKeyDelete builder.
+func (dkd *DataKeyDelete) Where(ps ...predicate.DataKey) *DataKeyDelete {
+	dkd.mutation.Where(ps...)
+	return dkd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (dkd *DataKeyDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks[int, DataKeyMutation](ctx, dkd.sqlExec, dkd.mutation, dkd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dkd *DataKeyDelete) ExecX(ctx context.Context) int {
+	n, err := dkd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (dkd *DataKeyDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := &sqlgraph.DeleteSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table: datakey.Table,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: datakey.FieldID,
+			},
+		},
+	}
+	if ps := dkd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, dkd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	dkd.mutation.done = true
+	return affected, err
+}
+
+// DataKeyDeleteOne is the builder for deleting a single DataKey entity.
+type DataKeyDeleteOne struct {
+	dkd *DataKeyDelete
+}
+
+// Where appends a list predicates to the DataKeyDelete builder.
+func (dkdo *DataKeyDeleteOne) Where(ps ...predicate.DataKey) *DataKeyDeleteOne {
+	dkdo.dkd.mutation.Where(ps...)
+	return dkdo
+}
+
+// Exec executes the deletion query.
+func (dkdo *DataKeyDeleteOne) Exec(ctx context.Context) error {
+	n, err := dkdo.dkd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{datakey.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dkdo *DataKeyDeleteOne) ExecX(ctx context.Context) {
+	if err := dkdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
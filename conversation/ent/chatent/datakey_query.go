@@ -0,0 +1,581 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+)
+
+// DataKeyQuery is the builder for querying DataKey entities.
+type DataKeyQuery struct {
+	config
+	ctx        *QueryContext
+	order      []OrderFunc
+	inters     []Interceptor
+	predicates []predicate.DataKey
+	modifiers  []func(*sql.Selector)
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the DataKeyQuery builder.
+func (dkq *DataKeyQuery) Where(ps ...predicate.DataKey) *DataKeyQuery {
+	dkq.predicates = append(dkq.predicates, ps...)
+	return dkq
+}
+
+// Limit the number of records to be returned by this query.
+func (dkq *DataKeyQuery) Limit(limit int) *DataKeyQuery {
+	dkq.ctx.Limit = &limit
+	return dkq
+}
+
+// Offset to start from.
+func (dkq *DataKeyQuery) Offset(offset int) *DataKeyQuery {
+	dkq.ctx.Offset = &offset
+	return dkq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (dkq *DataKeyQuery) Unique(unique bool) *DataKeyQuery {
+	dkq.ctx.Unique = &unique
+	return dkq
+}
+
+// Order specifies how the records should be ordered.
+func (dkq *DataKeyQuery) Order(o ...OrderFunc) *DataKeyQuery {
+	dkq.order = append(dkq.order, o...)
+	return dkq
+}
+
+// First returns the first DataKey entity from the query.
+// Returns a *NotFoundError when no DataKey was found.
+func (dkq *DataKeyQuery) First(ctx context.Context) (*DataKey, error) {
+	nodes, err := dkq.Limit(1).All(setContextOp(ctx, dkq.ctx, "First"))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{datakey.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (dkq *DataKeyQuery) FirstX(ctx context.Context) *DataKey {
+	node, err := dkq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first DataKey ID from the query.
+// Returns a *NotFoundError when no DataKey ID was found.
+func (dkq *DataKeyQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = dkq.Limit(1).IDs(setContextOp(ctx, dkq.ctx, "FirstID")); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{datakey.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (dkq *DataKeyQuery) FirstIDX(ctx context.Context) int {
+	id, err := dkq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single DataKey entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one DataKey entity is found.
+// Returns a *NotFoundError when no DataKey entities are found.
+func (dkq *DataKeyQuery) Only(ctx context.Context) (*DataKey, error) {
+	nodes, err := dkq.Limit(2).All(setContextOp(ctx, dkq.ctx, "Only"))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{datakey.Label}
+	default:
+		return nil, &NotSingularError{datakey.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (dkq *DataKeyQuery) OnlyX(ctx context.Context) *DataKey {
+	node, err := dkq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only DataKey ID in the query.
+// Returns a *NotSingularError when more than one DataKey ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (dkq *DataKeyQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = dkq.Limit(2).IDs(setContextOp(ctx, dkq.ctx, "OnlyID")); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{datakey.Label}
+	default:
+		err = &NotSingularError{datakey.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (dkq *DataKeyQuery) OnlyIDX(ctx context.Context) int {
+	id, err := dkq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of DataKeys.
+func (dkq *DataKeyQuery) All(ctx context.Context) ([]*DataKey, error) {
+	ctx = setContextOp(ctx, dkq.ctx, "All")
+	if err := dkq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*DataKey, *DataKeyQuery]()
+	return withInterceptors[[]*DataKey](ctx, dkq, qr, dkq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (dkq *DataKeyQuery) AllX(ctx context.Context) []*DataKey {
+	nodes, err := dkq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of DataKey IDs.
+func (dkq *DataKeyQuery) IDs(ctx context.Context) ([]int, error) {
+	var ids []int
+	ctx = setContextOp(ctx, dkq.ctx, "IDs")
+	if err := dkq.Select(datakey.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (dkq *DataKeyQuery) IDsX(ctx context.Context) []int {
+	ids, err := dkq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (dkq *DataKeyQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, dkq.ctx, "Count")
+	if err := dkq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, dkq, querierCount[*DataKeyQuery](), dkq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (dkq *DataKeyQuery) CountX(ctx context.Context) int {
+	count, err := dkq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (dkq *DataKeyQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, dkq.ctx, "Exist")
+	switch _, err := dkq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("chatent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (dkq *DataKeyQuery) ExistX(ctx context.Context) bool {
+	exist, err := dkq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the DataKeyQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (dkq *DataKeyQuery) Clone() *DataKeyQuery {
+	if dkq == nil {
+		return nil
+	}
+	return &DataKeyQuery{
+		config:     dkq.config,
+		ctx:        dkq.ctx.Clone(),
+		order:      append([]OrderFunc{}, dkq.order...),
+		inters:     append([]Interceptor{}, dkq.inters...),
+		predicates: append([]predicate.DataKey{}, dkq.predicates...),
+		// clone intermediate query.
+		sql:  dkq.sql.Clone(),
+		path: dkq.path,
+	}
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		UserID string `json:"user_id,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.DataKey.Query().
+//		GroupBy(datakey.FieldUserID).
+//		Aggregate(chatent.Count()).
+//		Scan(ctx, &v)
+func (dkq *DataKeyQuery) GroupBy(field string, fields ...string) *DataKeyGroupBy {
+	dkq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &DataKeyGroupBy{build: dkq}
+	grbuild.flds = &dkq.ctx.Fields
+	grbuild.label = datakey.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		UserID string `json:"user_id,omitempty"`
+//	}
+//
+//	client.DataKey.Query().
+//		Select(datakey.FieldUserID).
+//		Scan(ctx, &v)
+func (dkq *DataKeyQuery) Select(fields ...string) *DataKeySelect {
+	dkq.ctx.Fields = append(dkq.ctx.Fields, fields...)
+	sbuild := &DataKeySelect{DataKeyQuery: dkq}
+	sbuild.label = datakey.Label
+	sbuild.flds, sbuild.scan = &dkq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a DataKeySelect configured with the given aggregations.
+func (dkq *DataKeyQuery) Aggregate(fns ...AggregateFunc) *DataKeySelect {
+	return dkq.Select().Aggregate(fns...)
+}
+
+func (dkq *DataKeyQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range dkq.inters {
+		if inter == nil {
+			return fmt.Errorf("chatent: uninitialized interceptor (forgotten import chatent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, dkq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range dkq.ctx.Fields {
+		if !datakey.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("chatent: invalid field %q for query", f)}
+		}
+	}
+	if dkq.path != nil {
+		prev, err := dkq.path(ctx)
+		if err != nil {
+			return err
+		}
+		dkq.sql = prev
+	}
+	return nil
+}
+
+func (dkq *DataKeyQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*DataKey, error) {
+	var (
+		nodes = []*DataKey{}
+		_spec = dkq.querySpec()
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*DataKey).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &DataKey{config: dkq.config}
+		nodes = append(nodes, node)
+		return node.assignValues(columns, values)
+	}
+	if len(dkq.modifiers) > 0 {
+		_spec.Modifiers = dkq.modifiers
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, dkq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	return nodes, nil
+}
+
+func (dkq *DataKeyQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := dkq.querySpec()
+	if len(dkq.modifiers) > 0 {
+		_spec.Modifiers = dkq.modifiers
+	}
+	_spec.Node.Columns = dkq.ctx.Fields
+	if len(dkq.ctx.Fields) > 0 {
+		_spec.Unique = dkq.ctx.Unique != nil && *dkq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, dkq.driver, _spec)
+}
+
+func (dkq *DataKeyQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := &sqlgraph.QuerySpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   datakey.Table,
+			Columns: datakey.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: datakey.FieldID,
+			},
+		},
+		From:   dkq.sql,
+		Unique: true,
+	}
+	if unique := dkq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	}
+	if fields := dkq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, datakey.FieldID)
+		for i := range fields {
+			if fields[i] != datakey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := dkq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := dkq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := dkq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := dkq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (dkq *DataKeyQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(dkq.driver.Dialect())
+	t1 := builder.Table(datakey.Table)
+	columns := dkq.ctx.Fields
+	if len(columns) == 0 {
+		columns = datakey.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if dkq.sql != nil {
+		selector = dkq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if dkq.ctx.Unique != nil && *dkq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, m := range dkq.modifiers {
+		m(selector)
+	}
+	for _, p := range dkq.predicates {
+		p(selector)
+	}
+	for _, p := range dkq.order {
+		p(selector)
+	}
+	if offset := dkq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := dkq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// ForUpdate locks the selected rows against concurrent updates, and prevent them from being
+// updated, deleted or "selected ... for update" by other sessions, until the transaction is
+// either committed or rolled-back.
+func (dkq *DataKeyQuery) ForUpdate(opts ...sql.LockOption) *DataKeyQuery {
+	if dkq.driver.Dialect() == dialect.Postgres {
+		dkq.Unique(false)
+	}
+	dkq.modifiers = append(dkq.modifiers, func(s *sql.Selector) {
+		s.ForUpdate(opts...)
+	})
+	return dkq
+}
+
+// ForShare behaves similarly to ForUpdate, except that it acquires a shared mode lock
+// on any rows that are read. Other sessions can read the rows, but cannot modify them
+// until your transaction commits.
+func (dkq *DataKeyQuery) ForShare(opts ...sql.LockOption) *DataKeyQuery {
+	if dkq.driver.Dialect() == dialect.Postgres {
+		dkq.Unique(false)
+	}
+	dkq.modifiers = append(dkq.modifiers, func(s *sql.Selector) {
+		s.ForShare(opts...)
+	})
+	return dkq
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (dkq *DataKeyQuery) Modify(modifiers ...func(s *sql.Selector)) *DataKeySelect {
+	dkq.modifiers = append(dkq.modifiers, modifiers...)
+	return dkq.Select()
+}
+
+// DataKeyGroupBy is the group-by builder for DataKey entities.
+type DataKeyGroupBy struct {
+	selector
+	build *DataKeyQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (dkgb *DataKeyGroupBy) Aggregate(fns ...AggregateFunc) *DataKeyGroupBy {
+	dkgb.fns = append(dkgb.fns, fns...)
+	return dkgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (dkgb *DataKeyGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, dkgb.build.ctx, "GroupBy")
+	if err := dkgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*DataKeyQuery, *DataKeyGroupBy](ctx, dkgb.build, dkgb, dkgb.build.inters, v)
+}
+
+func (dkgb *DataKeyGroupBy) sqlScan(ctx context.Context, root *DataKeyQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(dkgb.fns))
+	for _, fn := range dkgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*dkgb.flds)+len(dkgb.fns))
+		for _, f := range *dkgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*dkgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := dkgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// DataKeySelect is the builder for selecting fields of DataKey entities.
+type DataKeySelect struct {
+	*DataKeyQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (dks *DataKeySelect) Aggregate(fns ...AggregateFunc) *DataKeySelect {
+	dks.fns = append(dks.fns, fns...)
+	return dks
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (dks *DataKeySelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, dks.ctx, "Select")
+	if err := dks.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*DataKeyQuery, *DataKeySelect](ctx, dks.DataKeyQuery, dks, dks.inters, v)
+}
+
+func (dks *DataKeySelect) sqlScan(ctx context.Context, root *DataKeyQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(dks.fns))
+	for _, fn := range dks.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*dks.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := dks.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (dks *DataKeySelect) Modify(modifiers ...func(s *sql.Selector)) *DataKeySelect {
+	dks.modifiers = append(dks.modifiers, modifiers...)
+	return dks
+}
@@ -0,0 +1,277 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+)
+
+// DataKeyUpdate is the builder for updating DataKey entities.
+type DataKeyUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *DataKeyMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the DataKeyUpdate builder.
+func (dku *DataKeyUpdate) Where(ps ...predicate.DataKey) *DataKeyUpdate {
+	dku.mutation.Where(ps...)
+	return dku
+}
+
+// SetUserID sets the "user_id" field.
+func (dku *DataKeyUpdate) SetUserID(s string) *DataKeyUpdate {
+	dku.mutation.SetUserID(s)
+	return dku
+}
+
+// SetVersion sets the "version" field.
+func (dku *DataKeyUpdate) SetVersion(i int) *DataKeyUpdate {
+	dku.mutation.ResetVersion()
+	dku.mutation.SetVersion(i)
+	return dku
+}
+
+// AddVersion adds i to the "version" field.
+func (dku *DataKeyUpdate) AddVersion(i int) *DataKeyUpdate {
+	dku.mutation.AddVersion(i)
+	return dku
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (dku *DataKeyUpdate) SetWrappedKey(b []byte) *DataKeyUpdate {
+	dku.mutation.SetWrappedKey(b)
+	return dku
+}
+
+// Mutation returns the DataKeyMutation object of the builder.
+func (dku *DataKeyUpdate) Mutation() *DataKeyMutation {
+	return dku.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (dku *DataKeyUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks[int, DataKeyMutation](ctx, dku.sqlSave, dku.mutation, dku.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dku *DataKeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := dku.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (dku *DataKeyUpdate) Exec(ctx context.Context) error {
+	_, err := dku.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dku *DataKeyUpdate) ExecX(ctx context.Context) {
+	if err := dku.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (dku *DataKeyUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *DataKeyUpdate {
+	dku.modifiers = append(dku.modifiers, modifiers...)
+	return dku
+}
+
+func (dku *DataKeyUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   datakey.Table,
+			Columns: datakey.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: datakey.FieldID,
+			},
+		},
+	}
+	if ps := dku.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := dku.mutation.UserID(); ok {
+		_spec.SetField(datakey.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := dku.mutation.Version(); ok {
+		_spec.SetField(datakey.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := dku.mutation.AddedVersion(); ok {
+		_spec.AddField(datakey.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := dku.mutation.WrappedKey(); ok {
+		_spec.SetField(datakey.FieldWrappedKey, field.TypeBytes, value)
+	}
+	_spec.AddModifiers(dku.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, dku.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{datakey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	dku.mutation.done = true
+	return n, nil
+}
+
+// DataKeyUpdateOne is the builder for updating a single DataKey entity.
+type DataKeyUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *DataKeyMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetUserID sets the "user_id" field.
+func (dkuo *DataKeyUpdateOne) SetUserID(s string) *DataKeyUpdateOne {
+	dkuo.mutation.SetUserID(s)
+	return dkuo
+}
+
+// SetVersion sets the "version" field.
+func (dkuo *DataKeyUpdateOne) SetVersion(i int) *DataKeyUpdateOne {
+	dkuo.mutation.ResetVersion()
+	dkuo.mutation.SetVersion(i)
+	return dkuo
+}
+
+// AddVersion adds i to the "version" field.
+func (dkuo *DataKeyUpdateOne) AddVersion(i int) *DataKeyUpdateOne {
+	dkuo.mutation.AddVersion(i)
+	return dkuo
+}
+
+// SetWrappedKey sets the "wrapped_key" field.
+func (dkuo *DataKeyUpdateOne) SetWrappedKey(b []byte) *DataKeyUpdateOne {
+	dkuo.mutation.SetWrappedKey(b)
+	return dkuo
+}
+
+// Mutation returns the DataKeyMutation object of the builder.
+func (dkuo *DataKeyUpdateOne) Mutation() *DataKeyMutation {
+	return dkuo.mutation
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (dkuo *DataKeyUpdateOne) Select(field string, fields ...string) *DataKeyUpdateOne {
+	dkuo.fields = append([]string{field}, fields...)
+	return dkuo
+}
+
+// Save executes the query and returns the updated DataKey entity.
+func (dkuo *DataKeyUpdateOne) Save(ctx context.Context) (*DataKey, error) {
+	return withHooks[*DataKey, DataKeyMutation](ctx, dkuo.sqlSave, dkuo.mutation, dkuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (dkuo *DataKeyUpdateOne) SaveX(ctx context.Context) *DataKey {
+	node, err := dkuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (dkuo *DataKeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := dkuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (dkuo *DataKeyUpdateOne) ExecX(ctx context.Context) {
+	if err := dkuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (dkuo *DataKeyUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *DataKeyUpdateOne {
+	dkuo.modifiers = append(dkuo.modifiers, modifiers...)
+	return dkuo
+}
+
+func (dkuo *DataKeyUpdateOne) sqlSave(ctx context.Context) (_node *DataKey, err error) {
+	_spec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   datakey.Table,
+			Columns: datakey.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: datakey.FieldID,
+			},
+		},
+	}
+	id, ok := dkuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`chatent: missing "DataKey.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := dkuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, datakey.FieldID)
+		for _, f := range fields {
+			if !datakey.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("chatent: invalid field %q for query", f)}
+			}
+			if f != datakey.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := dkuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := dkuo.mutation.UserID(); ok {
+		_spec.SetField(datakey.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := dkuo.mutation.Version(); ok {
+		_spec.SetField(datakey.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := dkuo.mutation.AddedVersion(); ok {
+		_spec.AddField(datakey.FieldVersion, field.TypeInt, value)
+	}
+	if value, ok := dkuo.mutation.WrappedKey(); ok {
+		_spec.SetField(datakey.FieldWrappedKey, field.TypeBytes, value)
+	}
+	_spec.AddModifiers(dkuo.modifiers...)
+	_node = &DataKey{config: dkuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, dkuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{datakey.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	dkuo.mutation.done = true
+	return _node, nil
+}
@@ -0,0 +1,184 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
+)
+
+// Embedding is the model entity for the Embedding schema.
+type Embedding struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// 命名空间
+	Namespace string `json:"namespace,omitempty"`
+	// 用户Id
+	UserID string `json:"user_id,omitempty"`
+	// 会话Id
+	SessionID int `json:"session_id,omitempty"`
+	// 消息Id
+	MessageID int `json:"message_id,omitempty"`
+	// 向量对应的文本
+	Content string `json:"content,omitempty"`
+	// 向量
+	Vector []byte `json:"vector,omitempty"`
+	// 附加信息
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Embedding) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case embedding.FieldVector, embedding.FieldMetadata:
+			values[i] = new([]byte)
+		case embedding.FieldID, embedding.FieldSessionID, embedding.FieldMessageID:
+			values[i] = new(sql.NullInt64)
+		case embedding.FieldNamespace, embedding.FieldUserID, embedding.FieldContent:
+			values[i] = new(sql.NullString)
+		case embedding.FieldCreatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			return nil, fmt.Errorf("unexpected column %q for type Embedding", columns[i])
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Embedding fields.
+func (e *Embedding) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case embedding.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			e.ID = int(value.Int64)
+		case embedding.FieldNamespace:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field namespace", values[i])
+			} else if value.Valid {
+				e.Namespace = value.String
+			}
+		case embedding.FieldUserID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				e.UserID = value.String
+			}
+		case embedding.FieldSessionID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field session_id", values[i])
+			} else if value.Valid {
+				e.SessionID = int(value.Int64)
+			}
+		case embedding.FieldMessageID:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field message_id", values[i])
+			} else if value.Valid {
+				e.MessageID = int(value.Int64)
+			}
+		case embedding.FieldContent:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field content", values[i])
+			} else if value.Valid {
+				e.Content = value.String
+			}
+		case embedding.FieldVector:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field vector", values[i])
+			} else if value != nil {
+				e.Vector = *value
+			}
+		case embedding.FieldMetadata:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field metadata", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &e.Metadata); err != nil {
+					return fmt.Errorf("unmarshal field metadata: %w", err)
+				}
+			}
+		case embedding.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				e.CreatedAt = value.Time
+			}
+		}
+	}
+	return nil
+}
+
+// Update returns a builder for updating this Embedding.
+// Note that you need to call Embedding.Unwrap() before calling this method if this Embedding
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (e *Embedding) Update() *EmbeddingUpdateOne {
+	return NewEmbeddingClient(e.config).UpdateOne(e)
+}
+
+// Unwrap unwraps the Embedding entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (e *Embedding) Unwrap() *Embedding {
+	_tx, ok := e.config.driver.(*txDriver)
+	if !ok {
+		panic("chatent: Embedding is not a transactional entity")
+	}
+	e.config.driver = _tx.drv
+	return e
+}
+
+// String implements the fmt.Stringer.
+func (e *Embedding) String() string {
+	var builder strings.Builder
+	builder.WriteString("Embedding(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", e.ID))
+	builder.WriteString("namespace=")
+	builder.WriteString(e.Namespace)
+	builder.WriteString(", ")
+	builder.WriteString("user_id=")
+	builder.WriteString(e.UserID)
+	builder.WriteString(", ")
+	builder.WriteString("session_id=")
+	builder.WriteString(fmt.Sprintf("%v", e.SessionID))
+	builder.WriteString(", ")
+	builder.WriteString("message_id=")
+	builder.WriteString(fmt.Sprintf("%v", e.MessageID))
+	builder.WriteString(", ")
+	builder.WriteString("content=")
+	builder.WriteString(e.Content)
+	builder.WriteString(", ")
+	builder.WriteString("vector=")
+	builder.WriteString(fmt.Sprintf("%v", e.Vector))
+	builder.WriteString(", ")
+	builder.WriteString("metadata=")
+	builder.WriteString(fmt.Sprintf("%v", e.Metadata))
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(e.CreatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Embeddings is a parsable slice of Embedding.
+type Embeddings []*Embedding
+
+func (e Embeddings) config(cfg config) {
+	for _i := range e {
+		e[_i].config = cfg
+	}
+}
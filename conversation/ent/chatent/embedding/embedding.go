@@ -0,0 +1,66 @@
+// Code generated by ent, DO NOT EDIT.
+
+package embedding
+
+import (
+	"time"
+)
+
+const (
+	// Label holds the string label denoting the embedding type in the database.
+	Label = "embedding"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldNamespace holds the string denoting the namespace field in the database.
+	FieldNamespace = "namespace"
+	// FieldUserID holds the string denoting the user_id field in the database.
+	FieldUserID = "user_id"
+	// FieldSessionID holds the string denoting the session_id field in the database.
+	FieldSessionID = "session_id"
+	// FieldMessageID holds the string denoting the message_id field in the database.
+	FieldMessageID = "message_id"
+	// FieldContent holds the string denoting the content field in the database.
+	FieldContent = "content"
+	// FieldVector holds the string denoting the vector field in the database.
+	FieldVector = "vector"
+	// FieldMetadata holds the string denoting the metadata field in the database.
+	FieldMetadata = "metadata"
+	// FieldCreatedAt holds the string denoting the created_at field in the database.
+	FieldCreatedAt = "created_at"
+	// Table holds the table name of the embedding in the database.
+	Table = "embeddings"
+)
+
+// Columns holds all SQL columns for embedding fields.
+var Columns = []string{
+	FieldID,
+	FieldNamespace,
+	FieldUserID,
+	FieldSessionID,
+	FieldMessageID,
+	FieldContent,
+	FieldVector,
+	FieldMetadata,
+	FieldCreatedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultUserID holds the default value on creation for the "user_id" field.
+	DefaultUserID string
+	// DefaultSessionID holds the default value on creation for the "session_id" field.
+	DefaultSessionID int
+	// DefaultMessageID holds the default value on creation for the "message_id" field.
+	DefaultMessageID int
+	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
+	DefaultCreatedAt func() time.Time
+)
@@ -0,0 +1,908 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
+)
+
+// EmbeddingCreate is the builder for creating a Embedding entity.
+type EmbeddingCreate struct {
+	config
+	mutation *EmbeddingMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetNamespace sets the "namespace" field.
+func (ec *EmbeddingCreate) SetNamespace(s string) *EmbeddingCreate {
+	ec.mutation.SetNamespace(s)
+	return ec
+}
+
+// SetUserID sets the "user_id" field.
+func (ec *EmbeddingCreate) SetUserID(s string) *EmbeddingCreate {
+	ec.mutation.SetUserID(s)
+	return ec
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (ec *EmbeddingCreate) SetNillableUserID(s *string) *EmbeddingCreate {
+	if s != nil {
+		ec.SetUserID(*s)
+	}
+	return ec
+}
+
+// SetSessionID sets the "session_id" field.
+func (ec *EmbeddingCreate) SetSessionID(i int) *EmbeddingCreate {
+	ec.mutation.SetSessionID(i)
+	return ec
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (ec *EmbeddingCreate) SetNillableSessionID(i *int) *EmbeddingCreate {
+	if i != nil {
+		ec.SetSessionID(*i)
+	}
+	return ec
+}
+
+// SetMessageID sets the "message_id" field.
+func (ec *EmbeddingCreate) SetMessageID(i int) *EmbeddingCreate {
+	ec.mutation.SetMessageID(i)
+	return ec
+}
+
+// SetNillableMessageID sets the "message_id" field if the given value is not nil.
+func (ec *EmbeddingCreate) SetNillableMessageID(i *int) *EmbeddingCreate {
+	if i != nil {
+		ec.SetMessageID(*i)
+	}
+	return ec
+}
+
+// SetContent sets the "content" field.
+func (ec *EmbeddingCreate) SetContent(s string) *EmbeddingCreate {
+	ec.mutation.SetContent(s)
+	return ec
+}
+
+// SetVector sets the "vector" field.
+func (ec *EmbeddingCreate) SetVector(b []byte) *EmbeddingCreate {
+	ec.mutation.SetVector(b)
+	return ec
+}
+
+// SetMetadata sets the "metadata" field.
+func (ec *EmbeddingCreate) SetMetadata(m map[string]string) *EmbeddingCreate {
+	ec.mutation.SetMetadata(m)
+	return ec
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (ec *EmbeddingCreate) SetCreatedAt(t time.Time) *EmbeddingCreate {
+	ec.mutation.SetCreatedAt(t)
+	return ec
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (ec *EmbeddingCreate) SetNillableCreatedAt(t *time.Time) *EmbeddingCreate {
+	if t != nil {
+		ec.SetCreatedAt(*t)
+	}
+	return ec
+}
+
+// Mutation returns the EmbeddingMutation object of the builder.
+func (ec *EmbeddingCreate) Mutation() *EmbeddingMutation {
+	return ec.mutation
+}
+
+// Save creates the Embedding in the database.
+func (ec *EmbeddingCreate) Save(ctx context.Context) (*Embedding, error) {
+	ec.defaults()
+	return withHooks[*Embedding, EmbeddingMutation](ctx, ec.sqlSave, ec.mutation, ec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (ec *EmbeddingCreate) SaveX(ctx context.Context) *Embedding {
+	v, err := ec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ec *EmbeddingCreate) Exec(ctx context.Context) error {
+	_, err := ec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ec *EmbeddingCreate) ExecX(ctx context.Context) {
+	if err := ec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (ec *EmbeddingCreate) defaults() {
+	if _, ok := ec.mutation.UserID(); !ok {
+		v := embedding.DefaultUserID
+		ec.mutation.SetUserID(v)
+	}
+	if _, ok := ec.mutation.SessionID(); !ok {
+		v := embedding.DefaultSessionID
+		ec.mutation.SetSessionID(v)
+	}
+	if _, ok := ec.mutation.MessageID(); !ok {
+		v := embedding.DefaultMessageID
+		ec.mutation.SetMessageID(v)
+	}
+	if _, ok := ec.mutation.CreatedAt(); !ok {
+		v := embedding.DefaultCreatedAt()
+		ec.mutation.SetCreatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ec *EmbeddingCreate) check() error {
+	if _, ok := ec.mutation.Namespace(); !ok {
+		return &ValidationError{Name: "namespace", err: errors.New(`chatent: missing required field "Embedding.namespace"`)}
+	}
+	if _, ok := ec.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`chatent: missing required field "Embedding.user_id"`)}
+	}
+	if _, ok := ec.mutation.SessionID(); !ok {
+		return &ValidationError{Name: "session_id", err: errors.New(`chatent: missing required field "Embedding.session_id"`)}
+	}
+	if _, ok := ec.mutation.MessageID(); !ok {
+		return &ValidationError{Name: "message_id", err: errors.New(`chatent: missing required field "Embedding.message_id"`)}
+	}
+	if _, ok := ec.mutation.Content(); !ok {
+		return &ValidationError{Name: "content", err: errors.New(`chatent: missing required field "Embedding.content"`)}
+	}
+	if _, ok := ec.mutation.Vector(); !ok {
+		return &ValidationError{Name: "vector", err: errors.New(`chatent: missing required field "Embedding.vector"`)}
+	}
+	if _, ok := ec.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`chatent: missing required field "Embedding.created_at"`)}
+	}
+	return nil
+}
+
+func (ec *EmbeddingCreate) sqlSave(ctx context.Context) (*Embedding, error) {
+	if err := ec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := ec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, ec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	ec.mutation.id = &_node.ID
+	ec.mutation.done = true
+	return _node, nil
+}
+
+func (ec *EmbeddingCreate) createSpec() (*Embedding, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Embedding{config: ec.config}
+		_spec = &sqlgraph.CreateSpec{
+			Table: embedding.Table,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: embedding.FieldID,
+			},
+		}
+	)
+	_spec.OnConflict = ec.conflict
+	if value, ok := ec.mutation.Namespace(); ok {
+		_spec.SetField(embedding.FieldNamespace, field.TypeString, value)
+		_node.Namespace = value
+	}
+	if value, ok := ec.mutation.UserID(); ok {
+		_spec.SetField(embedding.FieldUserID, field.TypeString, value)
+		_node.UserID = value
+	}
+	if value, ok := ec.mutation.SessionID(); ok {
+		_spec.SetField(embedding.FieldSessionID, field.TypeInt, value)
+		_node.SessionID = value
+	}
+	if value, ok := ec.mutation.MessageID(); ok {
+		_spec.SetField(embedding.FieldMessageID, field.TypeInt, value)
+		_node.MessageID = value
+	}
+	if value, ok := ec.mutation.Content(); ok {
+		_spec.SetField(embedding.FieldContent, field.TypeString, value)
+		_node.Content = value
+	}
+	if value, ok := ec.mutation.Vector(); ok {
+		_spec.SetField(embedding.FieldVector, field.TypeBytes, value)
+		_node.Vector = value
+	}
+	if value, ok := ec.mutation.Metadata(); ok {
+		_spec.SetField(embedding.FieldMetadata, field.TypeJSON, value)
+		_node.Metadata = value
+	}
+	if value, ok := ec.mutation.CreatedAt(); ok {
+		_spec.SetField(embedding.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Embedding.Create().
+//		SetNamespace(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.EmbeddingUpsert) {
+//			SetNamespace(v+v).
+//		}).
+//		Exec(ctx)
+func (ec *EmbeddingCreate) OnConflict(opts ...sql.ConflictOption) *EmbeddingUpsertOne {
+	ec.conflict = opts
+	return &EmbeddingUpsertOne{
+		create: ec,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Embedding.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (ec *EmbeddingCreate) OnConflictColumns(columns ...string) *EmbeddingUpsertOne {
+	ec.conflict = append(ec.conflict, sql.ConflictColumns(columns...))
+	return &EmbeddingUpsertOne{
+		create: ec,
+	}
+}
+
+type (
+	// EmbeddingUpsertOne is the builder for "upsert"-ing
+	//  one Embedding node.
+	EmbeddingUpsertOne struct {
+		create *EmbeddingCreate
+	}
+
+	// EmbeddingUpsert is the "OnConflict" setter.
+	EmbeddingUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetNamespace sets the "namespace" field.
+func (u *EmbeddingUpsert) SetNamespace(v string) *EmbeddingUpsert {
+	u.Set(embedding.FieldNamespace, v)
+	return u
+}
+
+// UpdateNamespace sets the "namespace" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateNamespace() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldNamespace)
+	return u
+}
+
+// SetUserID sets the "user_id" field.
+func (u *EmbeddingUpsert) SetUserID(v string) *EmbeddingUpsert {
+	u.Set(embedding.FieldUserID, v)
+	return u
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateUserID() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldUserID)
+	return u
+}
+
+// SetSessionID sets the "session_id" field.
+func (u *EmbeddingUpsert) SetSessionID(v int) *EmbeddingUpsert {
+	u.Set(embedding.FieldSessionID, v)
+	return u
+}
+
+// UpdateSessionID sets the "session_id" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateSessionID() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldSessionID)
+	return u
+}
+
+// AddSessionID adds v to the "session_id" field.
+func (u *EmbeddingUpsert) AddSessionID(v int) *EmbeddingUpsert {
+	u.Add(embedding.FieldSessionID, v)
+	return u
+}
+
+// SetMessageID sets the "message_id" field.
+func (u *EmbeddingUpsert) SetMessageID(v int) *EmbeddingUpsert {
+	u.Set(embedding.FieldMessageID, v)
+	return u
+}
+
+// UpdateMessageID sets the "message_id" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateMessageID() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldMessageID)
+	return u
+}
+
+// AddMessageID adds v to the "message_id" field.
+func (u *EmbeddingUpsert) AddMessageID(v int) *EmbeddingUpsert {
+	u.Add(embedding.FieldMessageID, v)
+	return u
+}
+
+// SetContent sets the "content" field.
+func (u *EmbeddingUpsert) SetContent(v string) *EmbeddingUpsert {
+	u.Set(embedding.FieldContent, v)
+	return u
+}
+
+// UpdateContent sets the "content" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateContent() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldContent)
+	return u
+}
+
+// SetVector sets the "vector" field.
+func (u *EmbeddingUpsert) SetVector(v []byte) *EmbeddingUpsert {
+	u.Set(embedding.FieldVector, v)
+	return u
+}
+
+// UpdateVector sets the "vector" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateVector() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldVector)
+	return u
+}
+
+// SetMetadata sets the "metadata" field.
+func (u *EmbeddingUpsert) SetMetadata(v map[string]string) *EmbeddingUpsert {
+	u.Set(embedding.FieldMetadata, v)
+	return u
+}
+
+// UpdateMetadata sets the "metadata" field to the value that was provided on create.
+func (u *EmbeddingUpsert) UpdateMetadata() *EmbeddingUpsert {
+	u.SetExcluded(embedding.FieldMetadata)
+	return u
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (u *EmbeddingUpsert) ClearMetadata() *EmbeddingUpsert {
+	u.SetNull(embedding.FieldMetadata)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.Embedding.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *EmbeddingUpsertOne) UpdateNewValues() *EmbeddingUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.CreatedAt(); exists {
+			s.SetIgnore(embedding.FieldCreatedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Embedding.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *EmbeddingUpsertOne) Ignore() *EmbeddingUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *EmbeddingUpsertOne) DoNothing() *EmbeddingUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the EmbeddingCreate.OnConflict
+// documentation for more info.
+func (u *EmbeddingUpsertOne) Update(set func(*EmbeddingUpsert)) *EmbeddingUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&EmbeddingUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetNamespace sets the "namespace" field.
+func (u *EmbeddingUpsertOne) SetNamespace(v string) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetNamespace(v)
+	})
+}
+
+// UpdateNamespace sets the "namespace" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateNamespace() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateNamespace()
+	})
+}
+
+// SetUserID sets the "user_id" field.
+func (u *EmbeddingUpsertOne) SetUserID(v string) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetUserID(v)
+	})
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateUserID() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateUserID()
+	})
+}
+
+// SetSessionID sets the "session_id" field.
+func (u *EmbeddingUpsertOne) SetSessionID(v int) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetSessionID(v)
+	})
+}
+
+// AddSessionID adds v to the "session_id" field.
+func (u *EmbeddingUpsertOne) AddSessionID(v int) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.AddSessionID(v)
+	})
+}
+
+// UpdateSessionID sets the "session_id" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateSessionID() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateSessionID()
+	})
+}
+
+// SetMessageID sets the "message_id" field.
+func (u *EmbeddingUpsertOne) SetMessageID(v int) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetMessageID(v)
+	})
+}
+
+// AddMessageID adds v to the "message_id" field.
+func (u *EmbeddingUpsertOne) AddMessageID(v int) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.AddMessageID(v)
+	})
+}
+
+// UpdateMessageID sets the "message_id" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateMessageID() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateMessageID()
+	})
+}
+
+// SetContent sets the "content" field.
+func (u *EmbeddingUpsertOne) SetContent(v string) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetContent(v)
+	})
+}
+
+// UpdateContent sets the "content" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateContent() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateContent()
+	})
+}
+
+// SetVector sets the "vector" field.
+func (u *EmbeddingUpsertOne) SetVector(v []byte) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetVector(v)
+	})
+}
+
+// UpdateVector sets the "vector" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateVector() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateVector()
+	})
+}
+
+// SetMetadata sets the "metadata" field.
+func (u *EmbeddingUpsertOne) SetMetadata(v map[string]string) *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetMetadata(v)
+	})
+}
+
+// UpdateMetadata sets the "metadata" field to the value that was provided on create.
+func (u *EmbeddingUpsertOne) UpdateMetadata() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateMetadata()
+	})
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (u *EmbeddingUpsertOne) ClearMetadata() *EmbeddingUpsertOne {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.ClearMetadata()
+	})
+}
+
+// Exec executes the query.
+func (u *EmbeddingUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("chatent: missing options for EmbeddingCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *EmbeddingUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *EmbeddingUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *EmbeddingUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// EmbeddingCreateBulk is the builder for creating many Embedding entities in bulk.
+type EmbeddingCreateBulk struct {
+	config
+	builders []*EmbeddingCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the Embedding entities in the database.
+func (ecb *EmbeddingCreateBulk) Save(ctx context.Context) ([]*Embedding, error) {
+	specs := make([]*sqlgraph.CreateSpec, len(ecb.builders))
+	nodes := make([]*Embedding, len(ecb.builders))
+	mutators := make([]Mutator, len(ecb.builders))
+	for i := range ecb.builders {
+		func(i int, root context.Context) {
+			builder := ecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*EmbeddingMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				nodes[i], specs[i] = builder.createSpec()
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, ecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = ecb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, ecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, ecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ecb *EmbeddingCreateBulk) SaveX(ctx context.Context) []*Embedding {
+	v, err := ecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (ecb *EmbeddingCreateBulk) Exec(ctx context.Context) error {
+	_, err := ecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ecb *EmbeddingCreateBulk) ExecX(ctx context.Context) {
+	if err := ecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Embedding.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.EmbeddingUpsert) {
+//			SetNamespace(v+v).
+//		}).
+//		Exec(ctx)
+func (ecb *EmbeddingCreateBulk) OnConflict(opts ...sql.ConflictOption) *EmbeddingUpsertBulk {
+	ecb.conflict = opts
+	return &EmbeddingUpsertBulk{
+		create: ecb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Embedding.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (ecb *EmbeddingCreateBulk) OnConflictColumns(columns ...string) *EmbeddingUpsertBulk {
+	ecb.conflict = append(ecb.conflict, sql.ConflictColumns(columns...))
+	return &EmbeddingUpsertBulk{
+		create: ecb,
+	}
+}
+
+// EmbeddingUpsertBulk is the builder for "upsert"-ing
+// a bulk of Embedding nodes.
+type EmbeddingUpsertBulk struct {
+	create *EmbeddingCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.Embedding.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *EmbeddingUpsertBulk) UpdateNewValues() *EmbeddingUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.CreatedAt(); exists {
+				s.SetIgnore(embedding.FieldCreatedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Embedding.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *EmbeddingUpsertBulk) Ignore() *EmbeddingUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *EmbeddingUpsertBulk) DoNothing() *EmbeddingUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the EmbeddingCreateBulk.OnConflict
+// documentation for more info.
+func (u *EmbeddingUpsertBulk) Update(set func(*EmbeddingUpsert)) *EmbeddingUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&EmbeddingUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetNamespace sets the "namespace" field.
+func (u *EmbeddingUpsertBulk) SetNamespace(v string) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetNamespace(v)
+	})
+}
+
+// UpdateNamespace sets the "namespace" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateNamespace() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateNamespace()
+	})
+}
+
+// SetUserID sets the "user_id" field.
+func (u *EmbeddingUpsertBulk) SetUserID(v string) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetUserID(v)
+	})
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateUserID() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateUserID()
+	})
+}
+
+// SetSessionID sets the "session_id" field.
+func (u *EmbeddingUpsertBulk) SetSessionID(v int) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetSessionID(v)
+	})
+}
+
+// AddSessionID adds v to the "session_id" field.
+func (u *EmbeddingUpsertBulk) AddSessionID(v int) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.AddSessionID(v)
+	})
+}
+
+// UpdateSessionID sets the "session_id" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateSessionID() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateSessionID()
+	})
+}
+
+// SetMessageID sets the "message_id" field.
+func (u *EmbeddingUpsertBulk) SetMessageID(v int) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetMessageID(v)
+	})
+}
+
+// AddMessageID adds v to the "message_id" field.
+func (u *EmbeddingUpsertBulk) AddMessageID(v int) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.AddMessageID(v)
+	})
+}
+
+// UpdateMessageID sets the "message_id" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateMessageID() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateMessageID()
+	})
+}
+
+// SetContent sets the "content" field.
+func (u *EmbeddingUpsertBulk) SetContent(v string) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetContent(v)
+	})
+}
+
+// UpdateContent sets the "content" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateContent() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateContent()
+	})
+}
+
+// SetVector sets the "vector" field.
+func (u *EmbeddingUpsertBulk) SetVector(v []byte) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetVector(v)
+	})
+}
+
+// UpdateVector sets the "vector" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateVector() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateVector()
+	})
+}
+
+// SetMetadata sets the "metadata" field.
+func (u *EmbeddingUpsertBulk) SetMetadata(v map[string]string) *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.SetMetadata(v)
+	})
+}
+
+// UpdateMetadata sets the "metadata" field to the value that was provided on create.
+func (u *EmbeddingUpsertBulk) UpdateMetadata() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.UpdateMetadata()
+	})
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (u *EmbeddingUpsertBulk) ClearMetadata() *EmbeddingUpsertBulk {
+	return u.Update(func(s *EmbeddingUpsert) {
+		s.ClearMetadata()
+	})
+}
+
+// Exec executes the query.
+func (u *EmbeddingUpsertBulk) Exec(ctx context.Context) error {
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("chatent: OnConflict was set for builder %d. Set it on the EmbeddingCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("chatent: missing options for EmbeddingCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *EmbeddingUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,435 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+)
+
+// EmbeddingUpdate is the builder for updating Embedding entities.
+type EmbeddingUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *EmbeddingMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the EmbeddingUpdate builder.
+func (eu *EmbeddingUpdate) Where(ps ...predicate.Embedding) *EmbeddingUpdate {
+	eu.mutation.Where(ps...)
+	return eu
+}
+
+// SetNamespace sets the "namespace" field.
+func (eu *EmbeddingUpdate) SetNamespace(s string) *EmbeddingUpdate {
+	eu.mutation.SetNamespace(s)
+	return eu
+}
+
+// SetUserID sets the "user_id" field.
+func (eu *EmbeddingUpdate) SetUserID(s string) *EmbeddingUpdate {
+	eu.mutation.SetUserID(s)
+	return eu
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (eu *EmbeddingUpdate) SetNillableUserID(s *string) *EmbeddingUpdate {
+	if s != nil {
+		eu.SetUserID(*s)
+	}
+	return eu
+}
+
+// SetSessionID sets the "session_id" field.
+func (eu *EmbeddingUpdate) SetSessionID(i int) *EmbeddingUpdate {
+	eu.mutation.ResetSessionID()
+	eu.mutation.SetSessionID(i)
+	return eu
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (eu *EmbeddingUpdate) SetNillableSessionID(i *int) *EmbeddingUpdate {
+	if i != nil {
+		eu.SetSessionID(*i)
+	}
+	return eu
+}
+
+// AddSessionID adds i to the "session_id" field.
+func (eu *EmbeddingUpdate) AddSessionID(i int) *EmbeddingUpdate {
+	eu.mutation.AddSessionID(i)
+	return eu
+}
+
+// SetMessageID sets the "message_id" field.
+func (eu *EmbeddingUpdate) SetMessageID(i int) *EmbeddingUpdate {
+	eu.mutation.ResetMessageID()
+	eu.mutation.SetMessageID(i)
+	return eu
+}
+
+// SetNillableMessageID sets the "message_id" field if the given value is not nil.
+func (eu *EmbeddingUpdate) SetNillableMessageID(i *int) *EmbeddingUpdate {
+	if i != nil {
+		eu.SetMessageID(*i)
+	}
+	return eu
+}
+
+// AddMessageID adds i to the "message_id" field.
+func (eu *EmbeddingUpdate) AddMessageID(i int) *EmbeddingUpdate {
+	eu.mutation.AddMessageID(i)
+	return eu
+}
+
+// SetContent sets the "content" field.
+func (eu *EmbeddingUpdate) SetContent(s string) *EmbeddingUpdate {
+	eu.mutation.SetContent(s)
+	return eu
+}
+
+// SetVector sets the "vector" field.
+func (eu *EmbeddingUpdate) SetVector(b []byte) *EmbeddingUpdate {
+	eu.mutation.SetVector(b)
+	return eu
+}
+
+// SetMetadata sets the "metadata" field.
+func (eu *EmbeddingUpdate) SetMetadata(m map[string]string) *EmbeddingUpdate {
+	eu.mutation.SetMetadata(m)
+	return eu
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (eu *EmbeddingUpdate) ClearMetadata() *EmbeddingUpdate {
+	eu.mutation.ClearMetadata()
+	return eu
+}
+
+// Mutation returns the EmbeddingMutation object of the builder.
+func (eu *EmbeddingUpdate) Mutation() *EmbeddingMutation {
+	return eu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (eu *EmbeddingUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks[int, EmbeddingMutation](ctx, eu.sqlSave, eu.mutation, eu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (eu *EmbeddingUpdate) SaveX(ctx context.Context) int {
+	affected, err := eu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (eu *EmbeddingUpdate) Exec(ctx context.Context) error {
+	_, err := eu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (eu *EmbeddingUpdate) ExecX(ctx context.Context) {
+	if err := eu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (eu *EmbeddingUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *EmbeddingUpdate {
+	eu.modifiers = append(eu.modifiers, modifiers...)
+	return eu
+}
+
+func (eu *EmbeddingUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   embedding.Table,
+			Columns: embedding.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: embedding.FieldID,
+			},
+		},
+	}
+	if ps := eu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := eu.mutation.Namespace(); ok {
+		_spec.SetField(embedding.FieldNamespace, field.TypeString, value)
+	}
+	if value, ok := eu.mutation.UserID(); ok {
+		_spec.SetField(embedding.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := eu.mutation.SessionID(); ok {
+		_spec.SetField(embedding.FieldSessionID, field.TypeInt, value)
+	}
+	if value, ok := eu.mutation.AddedSessionID(); ok {
+		_spec.AddField(embedding.FieldSessionID, field.TypeInt, value)
+	}
+	if value, ok := eu.mutation.MessageID(); ok {
+		_spec.SetField(embedding.FieldMessageID, field.TypeInt, value)
+	}
+	if value, ok := eu.mutation.AddedMessageID(); ok {
+		_spec.AddField(embedding.FieldMessageID, field.TypeInt, value)
+	}
+	if value, ok := eu.mutation.Content(); ok {
+		_spec.SetField(embedding.FieldContent, field.TypeString, value)
+	}
+	if value, ok := eu.mutation.Vector(); ok {
+		_spec.SetField(embedding.FieldVector, field.TypeBytes, value)
+	}
+	if value, ok := eu.mutation.Metadata(); ok {
+		_spec.SetField(embedding.FieldMetadata, field.TypeJSON, value)
+	}
+	if eu.mutation.MetadataCleared() {
+		_spec.ClearField(embedding.FieldMetadata, field.TypeJSON)
+	}
+	_spec.AddModifiers(eu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, eu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{embedding.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	eu.mutation.done = true
+	return n, nil
+}
+
+// EmbeddingUpdateOne is the builder for updating a single Embedding entity.
+type EmbeddingUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *EmbeddingMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetNamespace sets the "namespace" field.
+func (euo *EmbeddingUpdateOne) SetNamespace(s string) *EmbeddingUpdateOne {
+	euo.mutation.SetNamespace(s)
+	return euo
+}
+
+// SetUserID sets the "user_id" field.
+func (euo *EmbeddingUpdateOne) SetUserID(s string) *EmbeddingUpdateOne {
+	euo.mutation.SetUserID(s)
+	return euo
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (euo *EmbeddingUpdateOne) SetNillableUserID(s *string) *EmbeddingUpdateOne {
+	if s != nil {
+		euo.SetUserID(*s)
+	}
+	return euo
+}
+
+// SetSessionID sets the "session_id" field.
+func (euo *EmbeddingUpdateOne) SetSessionID(i int) *EmbeddingUpdateOne {
+	euo.mutation.ResetSessionID()
+	euo.mutation.SetSessionID(i)
+	return euo
+}
+
+// SetNillableSessionID sets the "session_id" field if the given value is not nil.
+func (euo *EmbeddingUpdateOne) SetNillableSessionID(i *int) *EmbeddingUpdateOne {
+	if i != nil {
+		euo.SetSessionID(*i)
+	}
+	return euo
+}
+
+// AddSessionID adds i to the "session_id" field.
+func (euo *EmbeddingUpdateOne) AddSessionID(i int) *EmbeddingUpdateOne {
+	euo.mutation.AddSessionID(i)
+	return euo
+}
+
+// SetMessageID sets the "message_id" field.
+func (euo *EmbeddingUpdateOne) SetMessageID(i int) *EmbeddingUpdateOne {
+	euo.mutation.ResetMessageID()
+	euo.mutation.SetMessageID(i)
+	return euo
+}
+
+// SetNillableMessageID sets the "message_id" field if the given value is not nil.
+func (euo *EmbeddingUpdateOne) SetNillableMessageID(i *int) *EmbeddingUpdateOne {
+	if i != nil {
+		euo.SetMessageID(*i)
+	}
+	return euo
+}
+
+// AddMessageID adds i to the "message_id" field.
+func (euo *EmbeddingUpdateOne) AddMessageID(i int) *EmbeddingUpdateOne {
+	euo.mutation.AddMessageID(i)
+	return euo
+}
+
+// SetContent sets the "content" field.
+func (euo *EmbeddingUpdateOne) SetContent(s string) *EmbeddingUpdateOne {
+	euo.mutation.SetContent(s)
+	return euo
+}
+
+// SetVector sets the "vector" field.
+func (euo *EmbeddingUpdateOne) SetVector(b []byte) *EmbeddingUpdateOne {
+	euo.mutation.SetVector(b)
+	return euo
+}
+
+// SetMetadata sets the "metadata" field.
+func (euo *EmbeddingUpdateOne) SetMetadata(m map[string]string) *EmbeddingUpdateOne {
+	euo.mutation.SetMetadata(m)
+	return euo
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (euo *EmbeddingUpdateOne) ClearMetadata() *EmbeddingUpdateOne {
+	euo.mutation.ClearMetadata()
+	return euo
+}
+
+// Mutation returns the EmbeddingMutation object of the builder.
+func (euo *EmbeddingUpdateOne) Mutation() *EmbeddingMutation {
+	return euo.mutation
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (euo *EmbeddingUpdateOne) Select(field string, fields ...string) *EmbeddingUpdateOne {
+	euo.fields = append([]string{field}, fields...)
+	return euo
+}
+
+// Save executes the query and returns the updated Embedding entity.
+func (euo *EmbeddingUpdateOne) Save(ctx context.Context) (*Embedding, error) {
+	return withHooks[*Embedding, EmbeddingMutation](ctx, euo.sqlSave, euo.mutation, euo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (euo *EmbeddingUpdateOne) SaveX(ctx context.Context) *Embedding {
+	node, err := euo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (euo *EmbeddingUpdateOne) Exec(ctx context.Context) error {
+	_, err := euo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (euo *EmbeddingUpdateOne) ExecX(ctx context.Context) {
+	if err := euo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (euo *EmbeddingUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *EmbeddingUpdateOne {
+	euo.modifiers = append(euo.modifiers, modifiers...)
+	return euo
+}
+
+func (euo *EmbeddingUpdateOne) sqlSave(ctx context.Context) (_node *Embedding, err error) {
+	_spec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   embedding.Table,
+			Columns: embedding.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: embedding.FieldID,
+			},
+		},
+	}
+	id, ok := euo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`chatent: missing "Embedding.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := euo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, embedding.FieldID)
+		for _, f := range fields {
+			if !embedding.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("chatent: invalid field %q for query", f)}
+			}
+			if f != embedding.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := euo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := euo.mutation.Namespace(); ok {
+		_spec.SetField(embedding.FieldNamespace, field.TypeString, value)
+	}
+	if value, ok := euo.mutation.UserID(); ok {
+		_spec.SetField(embedding.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := euo.mutation.SessionID(); ok {
+		_spec.SetField(embedding.FieldSessionID, field.TypeInt, value)
+	}
+	if value, ok := euo.mutation.AddedSessionID(); ok {
+		_spec.AddField(embedding.FieldSessionID, field.TypeInt, value)
+	}
+	if value, ok := euo.mutation.MessageID(); ok {
+		_spec.SetField(embedding.FieldMessageID, field.TypeInt, value)
+	}
+	if value, ok := euo.mutation.AddedMessageID(); ok {
+		_spec.AddField(embedding.FieldMessageID, field.TypeInt, value)
+	}
+	if value, ok := euo.mutation.Content(); ok {
+		_spec.SetField(embedding.FieldContent, field.TypeString, value)
+	}
+	if value, ok := euo.mutation.Vector(); ok {
+		_spec.SetField(embedding.FieldVector, field.TypeBytes, value)
+	}
+	if value, ok := euo.mutation.Metadata(); ok {
+		_spec.SetField(embedding.FieldMetadata, field.TypeJSON, value)
+	}
+	if euo.mutation.MetadataCleared() {
+		_spec.ClearField(embedding.FieldMetadata, field.TypeJSON)
+	}
+	_spec.AddModifiers(euo.modifiers...)
+	_node = &Embedding{config: euo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, euo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{embedding.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	euo.mutation.done = true
+	return _node, nil
+}
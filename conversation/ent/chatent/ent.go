@@ -11,7 +11,10 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
 )
 
@@ -40,8 +43,11 @@ type OrderFunc func(*sql.Selector)
 // columnChecker returns a function indicates if the column exists in the given column.
 func columnChecker(table string) func(string) error {
 	checks := map[string]func(string) bool{
-		message.Table: message.ValidColumn,
-		session.Table: session.ValidColumn,
+		datakey.Table:       datakey.ValidColumn,
+		embedding.Table:     embedding.ValidColumn,
+		message.Table:       message.ValidColumn,
+		responsecache.Table: responsecache.ValidColumn,
+		session.Table:       session.ValidColumn,
 	}
 	check, ok := checks[table]
 	if !ok {
@@ -91,7 +97,6 @@ type AggregateFunc func(*sql.Selector) string
 //	GroupBy(field1, field2).
 //	Aggregate(chatent.As(chatent.Sum(field1), "sum_field1"), (chatent.As(chatent.Sum(field2), "sum_field2")).
 //	Scan(ctx, &v)
-//
 func As(fn AggregateFunc, end string) AggregateFunc {
 	return func(s *sql.Selector) string {
 		return sql.As(fn(s), end)
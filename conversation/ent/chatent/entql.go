@@ -3,8 +3,11 @@
 package chatent
 
 import (
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
 
 	"entgo.io/ent/dialect/sql"
@@ -15,8 +18,46 @@ import (
 
 // schemaGraph holds a representation of ent/schema at runtime.
 var schemaGraph = func() *sqlgraph.Schema {
-	graph := &sqlgraph.Schema{Nodes: make([]*sqlgraph.Node, 2)}
+	graph := &sqlgraph.Schema{Nodes: make([]*sqlgraph.Node, 5)}
 	graph.Nodes[0] = &sqlgraph.Node{
+		NodeSpec: sqlgraph.NodeSpec{
+			Table:   datakey.Table,
+			Columns: datakey.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: datakey.FieldID,
+			},
+		},
+		Type: "DataKey",
+		Fields: map[string]*sqlgraph.FieldSpec{
+			datakey.FieldUserID:     {Type: field.TypeString, Column: datakey.FieldUserID},
+			datakey.FieldVersion:    {Type: field.TypeInt, Column: datakey.FieldVersion},
+			datakey.FieldWrappedKey: {Type: field.TypeBytes, Column: datakey.FieldWrappedKey},
+			datakey.FieldCreatedAt:  {Type: field.TypeTime, Column: datakey.FieldCreatedAt},
+		},
+	}
+	graph.Nodes[1] = &sqlgraph.Node{
+		NodeSpec: sqlgraph.NodeSpec{
+			Table:   embedding.Table,
+			Columns: embedding.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: embedding.FieldID,
+			},
+		},
+		Type: "Embedding",
+		Fields: map[string]*sqlgraph.FieldSpec{
+			embedding.FieldNamespace: {Type: field.TypeString, Column: embedding.FieldNamespace},
+			embedding.FieldUserID:    {Type: field.TypeString, Column: embedding.FieldUserID},
+			embedding.FieldSessionID: {Type: field.TypeInt, Column: embedding.FieldSessionID},
+			embedding.FieldMessageID: {Type: field.TypeInt, Column: embedding.FieldMessageID},
+			embedding.FieldContent:   {Type: field.TypeString, Column: embedding.FieldContent},
+			embedding.FieldVector:    {Type: field.TypeBytes, Column: embedding.FieldVector},
+			embedding.FieldMetadata:  {Type: field.TypeJSON, Column: embedding.FieldMetadata},
+			embedding.FieldCreatedAt: {Type: field.TypeTime, Column: embedding.FieldCreatedAt},
+		},
+	}
+	graph.Nodes[2] = &sqlgraph.Node{
 		NodeSpec: sqlgraph.NodeSpec{
 			Table:   message.Table,
 			Columns: message.Columns,
@@ -27,15 +68,36 @@ var schemaGraph = func() *sqlgraph.Schema {
 		},
 		Type: "Message",
 		Fields: map[string]*sqlgraph.FieldSpec{
-			message.FieldSessionID:  {Type: field.TypeInt, Column: message.FieldSessionID},
-			message.FieldFromUserID: {Type: field.TypeString, Column: message.FieldFromUserID},
-			message.FieldToUserID:   {Type: field.TypeString, Column: message.FieldToUserID},
-			message.FieldContent:    {Type: field.TypeString, Column: message.FieldContent},
-			message.FieldSpouseID:   {Type: field.TypeInt, Column: message.FieldSpouseID},
-			message.FieldCreatedAt:  {Type: field.TypeTime, Column: message.FieldCreatedAt},
+			message.FieldSessionID:      {Type: field.TypeInt, Column: message.FieldSessionID},
+			message.FieldFromUserID:     {Type: field.TypeString, Column: message.FieldFromUserID},
+			message.FieldToUserID:       {Type: field.TypeString, Column: message.FieldToUserID},
+			message.FieldContent:        {Type: field.TypeString, Column: message.FieldContent},
+			message.FieldSpouseID:       {Type: field.TypeInt, Column: message.FieldSpouseID},
+			message.FieldCitations:      {Type: field.TypeJSON, Column: message.FieldCitations},
+			message.FieldParts:          {Type: field.TypeString, Column: message.FieldParts},
+			message.FieldIdempotencyKey: {Type: field.TypeString, Column: message.FieldIdempotencyKey},
+			message.FieldCreatedAt:      {Type: field.TypeTime, Column: message.FieldCreatedAt},
 		},
 	}
-	graph.Nodes[1] = &sqlgraph.Node{
+	graph.Nodes[3] = &sqlgraph.Node{
+		NodeSpec: sqlgraph.NodeSpec{
+			Table:   responsecache.Table,
+			Columns: responsecache.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: responsecache.FieldID,
+			},
+		},
+		Type: "ResponseCache",
+		Fields: map[string]*sqlgraph.FieldSpec{
+			responsecache.FieldKey:       {Type: field.TypeString, Column: responsecache.FieldKey},
+			responsecache.FieldValue:     {Type: field.TypeString, Column: responsecache.FieldValue},
+			responsecache.FieldUserID:    {Type: field.TypeString, Column: responsecache.FieldUserID},
+			responsecache.FieldCreatedAt: {Type: field.TypeTime, Column: responsecache.FieldCreatedAt},
+			responsecache.FieldUpdatedAt: {Type: field.TypeTime, Column: responsecache.FieldUpdatedAt},
+		},
+	}
+	graph.Nodes[4] = &sqlgraph.Node{
 		NodeSpec: sqlgraph.NodeSpec{
 			Table:   session.Table,
 			Columns: session.Columns,
@@ -46,11 +108,15 @@ var schemaGraph = func() *sqlgraph.Schema {
 		},
 		Type: "Session",
 		Fields: map[string]*sqlgraph.FieldSpec{
-			session.FieldUserID:    {Type: field.TypeString, Column: session.FieldUserID},
-			session.FieldStatus:    {Type: field.TypeBool, Column: session.FieldStatus},
-			session.FieldCreatedAt: {Type: field.TypeTime, Column: session.FieldCreatedAt},
-			session.FieldUpdatedAt: {Type: field.TypeTime, Column: session.FieldUpdatedAt},
-			session.FieldDeletedAt: {Type: field.TypeInt, Column: session.FieldDeletedAt},
+			session.FieldUserID:       {Type: field.TypeString, Column: session.FieldUserID},
+			session.FieldChannel:      {Type: field.TypeString, Column: session.FieldChannel},
+			session.FieldStatus:       {Type: field.TypeBool, Column: session.FieldStatus},
+			session.FieldTitle:        {Type: field.TypeString, Column: session.FieldTitle},
+			session.FieldGroupChat:    {Type: field.TypeBool, Column: session.FieldGroupChat},
+			session.FieldParticipants: {Type: field.TypeJSON, Column: session.FieldParticipants},
+			session.FieldCreatedAt:    {Type: field.TypeTime, Column: session.FieldCreatedAt},
+			session.FieldUpdatedAt:    {Type: field.TypeTime, Column: session.FieldUpdatedAt},
+			session.FieldDeletedAt:    {Type: field.TypeInt, Column: session.FieldDeletedAt},
 		},
 	}
 	graph.MustAddE(
@@ -98,6 +164,146 @@ type predicateAdder interface {
 	addPredicate(func(s *sql.Selector))
 }
 
+// addPredicate implements the predicateAdder interface.
+func (dkq *DataKeyQuery) addPredicate(pred func(s *sql.Selector)) {
+	dkq.predicates = append(dkq.predicates, pred)
+}
+
+// Filter returns a Filter implementation to apply filters on the DataKeyQuery builder.
+func (dkq *DataKeyQuery) Filter() *DataKeyFilter {
+	return &DataKeyFilter{config: dkq.config, predicateAdder: dkq}
+}
+
+// addPredicate implements the predicateAdder interface.
+func (m *DataKeyMutation) addPredicate(pred func(s *sql.Selector)) {
+	m.predicates = append(m.predicates, pred)
+}
+
+// Filter returns an entql.Where implementation to apply filters on the DataKeyMutation builder.
+func (m *DataKeyMutation) Filter() *DataKeyFilter {
+	return &DataKeyFilter{config: m.config, predicateAdder: m}
+}
+
+// DataKeyFilter provides a generic filtering capability at runtime for DataKeyQuery.
+type DataKeyFilter struct {
+	predicateAdder
+	config
+}
+
+// Where applies the entql predicate on the query filter.
+func (f *DataKeyFilter) Where(p entql.P) {
+	f.addPredicate(func(s *sql.Selector) {
+		if err := schemaGraph.EvalP(schemaGraph.Nodes[0].Type, p, s); err != nil {
+			s.AddError(err)
+		}
+	})
+}
+
+// WhereID applies the entql int predicate on the id field.
+func (f *DataKeyFilter) WhereID(p entql.IntP) {
+	f.Where(p.Field(datakey.FieldID))
+}
+
+// WhereUserID applies the entql string predicate on the user_id field.
+func (f *DataKeyFilter) WhereUserID(p entql.StringP) {
+	f.Where(p.Field(datakey.FieldUserID))
+}
+
+// WhereVersion applies the entql int predicate on the version field.
+func (f *DataKeyFilter) WhereVersion(p entql.IntP) {
+	f.Where(p.Field(datakey.FieldVersion))
+}
+
+// WhereWrappedKey applies the entql []byte predicate on the wrapped_key field.
+func (f *DataKeyFilter) WhereWrappedKey(p entql.BytesP) {
+	f.Where(p.Field(datakey.FieldWrappedKey))
+}
+
+// WhereCreatedAt applies the entql time.Time predicate on the created_at field.
+func (f *DataKeyFilter) WhereCreatedAt(p entql.TimeP) {
+	f.Where(p.Field(datakey.FieldCreatedAt))
+}
+
+// addPredicate implements the predicateAdder interface.
+func (eq *EmbeddingQuery) addPredicate(pred func(s *sql.Selector)) {
+	eq.predicates = append(eq.predicates, pred)
+}
+
+// Filter returns a Filter implementation to apply filters on the EmbeddingQuery builder.
+func (eq *EmbeddingQuery) Filter() *EmbeddingFilter {
+	return &EmbeddingFilter{config: eq.config, predicateAdder: eq}
+}
+
+// addPredicate implements the predicateAdder interface.
+func (m *EmbeddingMutation) addPredicate(pred func(s *sql.Selector)) {
+	m.predicates = append(m.predicates, pred)
+}
+
+// Filter returns an entql.Where implementation to apply filters on the EmbeddingMutation builder.
+func (m *EmbeddingMutation) Filter() *EmbeddingFilter {
+	return &EmbeddingFilter{config: m.config, predicateAdder: m}
+}
+
+// EmbeddingFilter provides a generic filtering capability at runtime for EmbeddingQuery.
+type EmbeddingFilter struct {
+	predicateAdder
+	config
+}
+
+// Where applies the entql predicate on the query filter.
+func (f *EmbeddingFilter) Where(p entql.P) {
+	f.addPredicate(func(s *sql.Selector) {
+		if err := schemaGraph.EvalP(schemaGraph.Nodes[1].Type, p, s); err != nil {
+			s.AddError(err)
+		}
+	})
+}
+
+// WhereID applies the entql int predicate on the id field.
+func (f *EmbeddingFilter) WhereID(p entql.IntP) {
+	f.Where(p.Field(embedding.FieldID))
+}
+
+// WhereNamespace applies the entql string predicate on the namespace field.
+func (f *EmbeddingFilter) WhereNamespace(p entql.StringP) {
+	f.Where(p.Field(embedding.FieldNamespace))
+}
+
+// WhereUserID applies the entql string predicate on the user_id field.
+func (f *EmbeddingFilter) WhereUserID(p entql.StringP) {
+	f.Where(p.Field(embedding.FieldUserID))
+}
+
+// WhereSessionID applies the entql int predicate on the session_id field.
+func (f *EmbeddingFilter) WhereSessionID(p entql.IntP) {
+	f.Where(p.Field(embedding.FieldSessionID))
+}
+
+// WhereMessageID applies the entql int predicate on the message_id field.
+func (f *EmbeddingFilter) WhereMessageID(p entql.IntP) {
+	f.Where(p.Field(embedding.FieldMessageID))
+}
+
+// WhereContent applies the entql string predicate on the content field.
+func (f *EmbeddingFilter) WhereContent(p entql.StringP) {
+	f.Where(p.Field(embedding.FieldContent))
+}
+
+// WhereVector applies the entql []byte predicate on the vector field.
+func (f *EmbeddingFilter) WhereVector(p entql.BytesP) {
+	f.Where(p.Field(embedding.FieldVector))
+}
+
+// WhereMetadata applies the entql json.RawMessage predicate on the metadata field.
+func (f *EmbeddingFilter) WhereMetadata(p entql.BytesP) {
+	f.Where(p.Field(embedding.FieldMetadata))
+}
+
+// WhereCreatedAt applies the entql time.Time predicate on the created_at field.
+func (f *EmbeddingFilter) WhereCreatedAt(p entql.TimeP) {
+	f.Where(p.Field(embedding.FieldCreatedAt))
+}
+
 // addPredicate implements the predicateAdder interface.
 func (mq *MessageQuery) addPredicate(pred func(s *sql.Selector)) {
 	mq.predicates = append(mq.predicates, pred)
@@ -127,7 +333,7 @@ type MessageFilter struct {
 // Where applies the entql predicate on the query filter.
 func (f *MessageFilter) Where(p entql.P) {
 	f.addPredicate(func(s *sql.Selector) {
-		if err := schemaGraph.EvalP(schemaGraph.Nodes[0].Type, p, s); err != nil {
+		if err := schemaGraph.EvalP(schemaGraph.Nodes[2].Type, p, s); err != nil {
 			s.AddError(err)
 		}
 	})
@@ -163,6 +369,21 @@ func (f *MessageFilter) WhereSpouseID(p entql.IntP) {
 	f.Where(p.Field(message.FieldSpouseID))
 }
 
+// WhereCitations applies the entql json.RawMessage predicate on the citations field.
+func (f *MessageFilter) WhereCitations(p entql.BytesP) {
+	f.Where(p.Field(message.FieldCitations))
+}
+
+// WhereParts applies the entql string predicate on the parts field.
+func (f *MessageFilter) WhereParts(p entql.StringP) {
+	f.Where(p.Field(message.FieldParts))
+}
+
+// WhereIdempotencyKey applies the entql string predicate on the idempotency_key field.
+func (f *MessageFilter) WhereIdempotencyKey(p entql.StringP) {
+	f.Where(p.Field(message.FieldIdempotencyKey))
+}
+
 // WhereCreatedAt applies the entql time.Time predicate on the created_at field.
 func (f *MessageFilter) WhereCreatedAt(p entql.TimeP) {
 	f.Where(p.Field(message.FieldCreatedAt))
@@ -196,6 +417,71 @@ func (f *MessageFilter) WhereHasSessionWith(preds ...predicate.Session) {
 	})))
 }
 
+// addPredicate implements the predicateAdder interface.
+func (rcq *ResponseCacheQuery) addPredicate(pred func(s *sql.Selector)) {
+	rcq.predicates = append(rcq.predicates, pred)
+}
+
+// Filter returns a Filter implementation to apply filters on the ResponseCacheQuery builder.
+func (rcq *ResponseCacheQuery) Filter() *ResponseCacheFilter {
+	return &ResponseCacheFilter{config: rcq.config, predicateAdder: rcq}
+}
+
+// addPredicate implements the predicateAdder interface.
+func (m *ResponseCacheMutation) addPredicate(pred func(s *sql.Selector)) {
+	m.predicates = append(m.predicates, pred)
+}
+
+// Filter returns an entql.Where implementation to apply filters on the ResponseCacheMutation builder.
+func (m *ResponseCacheMutation) Filter() *ResponseCacheFilter {
+	return &ResponseCacheFilter{config: m.config, predicateAdder: m}
+}
+
+// ResponseCacheFilter provides a generic filtering capability at runtime for ResponseCacheQuery.
+type ResponseCacheFilter struct {
+	predicateAdder
+	config
+}
+
+// Where applies the entql predicate on the query filter.
+func (f *ResponseCacheFilter) Where(p entql.P) {
+	f.addPredicate(func(s *sql.Selector) {
+		if err := schemaGraph.EvalP(schemaGraph.Nodes[3].Type, p, s); err != nil {
+			s.AddError(err)
+		}
+	})
+}
+
+// WhereID applies the entql int predicate on the id field.
+func (f *ResponseCacheFilter) WhereID(p entql.IntP) {
+	f.Where(p.Field(responsecache.FieldID))
+}
+
+// WhereKey applies the entql string predicate on the key field.
+func (f *ResponseCacheFilter) WhereKey(p entql.StringP) {
+	f.Where(p.Field(responsecache.FieldKey))
+}
+
+// WhereValue applies the entql string predicate on the value field.
+func (f *ResponseCacheFilter) WhereValue(p entql.StringP) {
+	f.Where(p.Field(responsecache.FieldValue))
+}
+
+// WhereUserID applies the entql string predicate on the user_id field.
+func (f *ResponseCacheFilter) WhereUserID(p entql.StringP) {
+	f.Where(p.Field(responsecache.FieldUserID))
+}
+
+// WhereCreatedAt applies the entql time.Time predicate on the created_at field.
+func (f *ResponseCacheFilter) WhereCreatedAt(p entql.TimeP) {
+	f.Where(p.Field(responsecache.FieldCreatedAt))
+}
+
+// WhereUpdatedAt applies the entql time.Time predicate on the updated_at field.
+func (f *ResponseCacheFilter) WhereUpdatedAt(p entql.TimeP) {
+	f.Where(p.Field(responsecache.FieldUpdatedAt))
+}
+
 // addPredicate implements the predicateAdder interface.
 func (sq *SessionQuery) addPredicate(pred func(s *sql.Selector)) {
 	sq.predicates = append(sq.predicates, pred)
@@ -225,7 +511,7 @@ type SessionFilter struct {
 // Where applies the entql predicate on the query filter.
 func (f *SessionFilter) Where(p entql.P) {
 	f.addPredicate(func(s *sql.Selector) {
-		if err := schemaGraph.EvalP(schemaGraph.Nodes[1].Type, p, s); err != nil {
+		if err := schemaGraph.EvalP(schemaGraph.Nodes[4].Type, p, s); err != nil {
 			s.AddError(err)
 		}
 	})
@@ -241,11 +527,31 @@ func (f *SessionFilter) WhereUserID(p entql.StringP) {
 	f.Where(p.Field(session.FieldUserID))
 }
 
+// WhereChannel applies the entql string predicate on the channel field.
+func (f *SessionFilter) WhereChannel(p entql.StringP) {
+	f.Where(p.Field(session.FieldChannel))
+}
+
 // WhereStatus applies the entql bool predicate on the status field.
 func (f *SessionFilter) WhereStatus(p entql.BoolP) {
 	f.Where(p.Field(session.FieldStatus))
 }
 
+// WhereTitle applies the entql string predicate on the title field.
+func (f *SessionFilter) WhereTitle(p entql.StringP) {
+	f.Where(p.Field(session.FieldTitle))
+}
+
+// WhereGroupChat applies the entql bool predicate on the group_chat field.
+func (f *SessionFilter) WhereGroupChat(p entql.BoolP) {
+	f.Where(p.Field(session.FieldGroupChat))
+}
+
+// WhereParticipants applies the entql json.RawMessage predicate on the participants field.
+func (f *SessionFilter) WhereParticipants(p entql.BytesP) {
+	f.Where(p.Field(session.FieldParticipants))
+}
+
 // WhereCreatedAt applies the entql time.Time predicate on the created_at field.
 func (f *SessionFilter) WhereCreatedAt(p entql.TimeP) {
 	f.Where(p.Field(session.FieldCreatedAt))
@@ -9,6 +9,30 @@ import (
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
 )
 
+// The DataKeyFunc type is an adapter to allow the use of ordinary
+// function as DataKey mutator.
+type DataKeyFunc func(context.Context, *chatent.DataKeyMutation) (chatent.Value, error)
+
+// Mutate calls f(ctx, m).
+func (f DataKeyFunc) Mutate(ctx context.Context, m chatent.Mutation) (chatent.Value, error) {
+	if mv, ok := m.(*chatent.DataKeyMutation); ok {
+		return f(ctx, mv)
+	}
+	return nil, fmt.Errorf("unexpected mutation type %T. expect *chatent.DataKeyMutation", m)
+}
+
+// The EmbeddingFunc type is an adapter to allow the use of ordinary
+// function as Embedding mutator.
+type EmbeddingFunc func(context.Context, *chatent.EmbeddingMutation) (chatent.Value, error)
+
+// Mutate calls f(ctx, m).
+func (f EmbeddingFunc) Mutate(ctx context.Context, m chatent.Mutation) (chatent.Value, error) {
+	if mv, ok := m.(*chatent.EmbeddingMutation); ok {
+		return f(ctx, mv)
+	}
+	return nil, fmt.Errorf("unexpected mutation type %T. expect *chatent.EmbeddingMutation", m)
+}
+
 // The MessageFunc type is an adapter to allow the use of ordinary
 // function as Message mutator.
 type MessageFunc func(context.Context, *chatent.MessageMutation) (chatent.Value, error)
@@ -21,6 +45,18 @@ func (f MessageFunc) Mutate(ctx context.Context, m chatent.Mutation) (chatent.Va
 	return nil, fmt.Errorf("unexpected mutation type %T. expect *chatent.MessageMutation", m)
 }
 
+// The ResponseCacheFunc type is an adapter to allow the use of ordinary
+// function as ResponseCache mutator.
+type ResponseCacheFunc func(context.Context, *chatent.ResponseCacheMutation) (chatent.Value, error)
+
+// Mutate calls f(ctx, m).
+func (f ResponseCacheFunc) Mutate(ctx context.Context, m chatent.Mutation) (chatent.Value, error) {
+	if mv, ok := m.(*chatent.ResponseCacheMutation); ok {
+		return f(ctx, mv)
+	}
+	return nil, fmt.Errorf("unexpected mutation type %T. expect *chatent.ResponseCacheMutation", m)
+}
+
 // The SessionFunc type is an adapter to allow the use of ordinary
 // function as Session mutator.
 type SessionFunc func(context.Context, *chatent.SessionMutation) (chatent.Value, error)
@@ -128,7 +164,6 @@ func HasFields(field string, fields ...string) Condition {
 // If executes the given hook under condition.
 //
 //	hook.If(ComputeAverage, And(HasFields(...), HasAddedFields(...)))
-//
 func If(hk chatent.Hook, cond Condition) chatent.Hook {
 	return func(next chatent.Mutator) chatent.Mutator {
 		return chatent.MutateFunc(func(ctx context.Context, m chatent.Mutation) (chatent.Value, error) {
@@ -143,7 +178,6 @@ func If(hk chatent.Hook, cond Condition) chatent.Hook {
 // On executes the given hook only for the given operation.
 //
 //	hook.On(Log, chatent.Delete|chatent.Create)
-//
 func On(hk chatent.Hook, op chatent.Op) chatent.Hook {
 	return If(hk, HasOp(op))
 }
@@ -151,7 +185,6 @@ func On(hk chatent.Hook, op chatent.Op) chatent.Hook {
 // Unless skips the given hook only for the given operation.
 //
 //	hook.Unless(Log, chatent.Update|chatent.UpdateOne)
-//
 func Unless(hk chatent.Hook, op chatent.Op) chatent.Hook {
 	return If(hk, Not(HasOp(op)))
 }
@@ -172,7 +205,6 @@ func FixedError(err error) chatent.Hook {
 //			Reject(chatent.Delete|chatent.Update),
 //		}
 //	}
-//
 func Reject(op chatent.Op) chatent.Hook {
 	hk := FixedError(fmt.Errorf("%s operation is not allowed", op))
 	return On(hk, op)
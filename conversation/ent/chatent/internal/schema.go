@@ -6,4 +6,4 @@
 // Package internal holds a loadable version of the latest schema.
 package internal
 
-const Schema = `{"Schema":"github.com/fanchunke/xgpt3/conversation/ent/schema","Package":"github.com/fanchunke/xgpt3/conversation/ent/chatent","Schemas":[{"name":"Message","config":{"Table":""},"edges":[{"name":"spouse","type":"Message","field":"spouse_id","unique":true},{"name":"session","type":"Session","field":"session_id","ref_name":"messages","unique":true,"inverse":true}],"fields":[{"name":"session_id","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"optional":true,"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"comment":"会话Id"},{"name":"from_user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"消息发送者Id"},{"name":"to_user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"消息接收者Id"},{"name":"content","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"size":2147483647,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"comment":"消息内容"},{"name":"spouse_id","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"optional":true,"position":{"Index":4,"MixedIn":false,"MixinIndex":0}},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":5,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}}],"indexes":[{"fields":["session_id","from_user_id","created_at"]},{"fields":["session_id","to_user_id","created_at"]}]},{"name":"Session","config":{"Table":""},"edges":[{"name":"messages","type":"Message"}],"fields":[{"name":"user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"用户Id"},{"name":"status","type":{"Type":1,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":false,"default_kind":1,"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"comment":"会话是否开启"},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}},{"name":"updated_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"update_default":true,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"schema_type":{"mysql":"timestamp","sqlite3":"timestamp"},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP","options":"ON UPDATE CURRENT_TIMESTAMP"}}},{"name":"deleted_at","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":0,"default_kind":2,"position":{"Index":4,"MixedIn":false,"MixinIndex":0}}],"indexes":[{"fields":["status","user_id"]}]}],"Features":["sql/lock","sql/upsert","privacy","entql","schema/snapshot","sql/modifier","sql/execquery"]}`
+const Schema = `{"Schema":"github.com/fanchunke/xgpt3/conversation/ent/schema","Package":"github.com/fanchunke/xgpt3/conversation/ent/chatent","Schemas":[{"name":"DataKey","config":{"Table":""},"fields":[{"name":"user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"用户Id"},{"name":"version","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"comment":"密钥版本"},{"name":"wrapped_key","type":{"Type":5,"Ident":"","PkgPath":"","PkgName":"","Nillable":true,"RType":null},"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"sensitive":true,"comment":"主密钥加密后的数据密钥"},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}}],"indexes":[{"unique":true,"fields":["user_id","version"]}]},{"name":"Embedding","config":{"Table":""},"fields":[{"name":"namespace","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":100}},"comment":"命名空间"},{"name":"user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":"","default_kind":24,"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"用户Id"},{"name":"session_id","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":0,"default_kind":2,"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"comment":"会话Id"},{"name":"message_id","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":0,"default_kind":2,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"comment":"消息Id"},{"name":"content","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"size":2147483647,"position":{"Index":4,"MixedIn":false,"MixinIndex":0},"comment":"向量对应的文本"},{"name":"vector","type":{"Type":5,"Ident":"","PkgPath":"","PkgName":"","Nillable":true,"RType":null},"position":{"Index":5,"MixedIn":false,"MixinIndex":0},"comment":"向量"},{"name":"metadata","type":{"Type":3,"Ident":"map[string]string","PkgPath":"","PkgName":"","Nillable":true,"RType":{"Name":"","Ident":"map[string]string","Kind":21,"PkgPath":"","Methods":{}}},"optional":true,"position":{"Index":6,"MixedIn":false,"MixinIndex":0},"comment":"附加信息"},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":7,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}}],"indexes":[{"fields":["namespace","created_at"]}]},{"name":"Message","config":{"Table":""},"edges":[{"name":"spouse","type":"Message","field":"spouse_id","unique":true},{"name":"session","type":"Session","field":"session_id","ref_name":"messages","unique":true,"inverse":true}],"fields":[{"name":"session_id","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"optional":true,"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"comment":"会话Id"},{"name":"from_user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"消息发送者Id"},{"name":"to_user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"消息接收者Id"},{"name":"content","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"size":2147483647,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"comment":"消息内容"},{"name":"spouse_id","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"optional":true,"position":{"Index":4,"MixedIn":false,"MixinIndex":0}},{"name":"citations","type":{"Type":3,"Ident":"[]conversation.Citation","PkgPath":"github.com/fanchunke/xgpt3/conversation","PkgName":"conversation","Nillable":true,"RType":{"Name":"","Ident":"[]conversation.Citation","Kind":23,"PkgPath":"","Methods":{}}},"optional":true,"position":{"Index":5,"MixedIn":false,"MixinIndex":0},"comment":"回复引用的资料"},{"name":"parts","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"size":2147483647,"optional":true,"position":{"Index":6,"MixedIn":false,"MixinIndex":0},"comment":"多模态消息的内容片段，JSON 编码，开启加密时与消息内容一样加密保存"},{"name":"idempotency_key","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"nillable":true,"optional":true,"position":{"Index":7,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":100}},"comment":"幂等键，通常是平台的消息Id"},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":8,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}}],"indexes":[{"fields":["session_id","from_user_id","created_at"]},{"fields":["session_id","to_user_id","created_at"]},{"unique":true,"fields":["from_user_id","idempotency_key"]},{"fields":["content"],"annotations":{"EntSQLIndexes":{"Desc":false,"DescColumns":null,"IncludeColumns":null,"OpClass":"","OpClassColumns":null,"Prefix":0,"PrefixColumns":null,"Type":"","Types":{"mysql":"FULLTEXT"},"Where":""}}}]},{"name":"ResponseCache","config":{"Table":""},"fields":[{"name":"key","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"unique":true,"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":64}},"comment":"缓存键"},{"name":"value","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"size":2147483647,"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"comment":"缓存内容"},{"name":"user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":"","default_kind":24,"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"缓存所属的用户Id，删除用户数据时一并删除"},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}},{"name":"updated_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"update_default":true,"position":{"Index":4,"MixedIn":false,"MixinIndex":0},"comment":"缓存更新时间，用于判断是否过期"}],"indexes":[{"fields":["user_id"]}]},{"name":"Session","config":{"Table":""},"edges":[{"name":"messages","type":"Message"}],"fields":[{"name":"user_id","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"position":{"Index":0,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"用户Id"},{"name":"channel","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":"default","default_kind":24,"position":{"Index":1,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":50}},"comment":"消息渠道"},{"name":"status","type":{"Type":1,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":false,"default_kind":1,"position":{"Index":2,"MixedIn":false,"MixinIndex":0},"comment":"会话是否开启"},{"name":"title","type":{"Type":7,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":"","default_kind":24,"position":{"Index":3,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"size":255}},"comment":"会话标题"},{"name":"group_chat","type":{"Type":1,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":false,"default_kind":1,"position":{"Index":4,"MixedIn":false,"MixinIndex":0},"comment":"是否为群聊会话，群聊会话的用户Id为群Id"},{"name":"participants","type":{"Type":3,"Ident":"[]string","PkgPath":"","PkgName":"","Nillable":true,"RType":{"Name":"","Ident":"[]string","Kind":23,"PkgPath":"","Methods":{}}},"optional":true,"position":{"Index":5,"MixedIn":false,"MixinIndex":0},"comment":"群聊会话的参与者"},{"name":"created_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"immutable":true,"position":{"Index":6,"MixedIn":false,"MixinIndex":0},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP"}}},{"name":"updated_at","type":{"Type":2,"Ident":"","PkgPath":"time","PkgName":"","Nillable":false,"RType":null},"default":true,"default_kind":19,"update_default":true,"position":{"Index":7,"MixedIn":false,"MixinIndex":0},"schema_type":{"mysql":"timestamp","sqlite3":"timestamp"},"annotations":{"EntSQL":{"default":"CURRENT_TIMESTAMP","options":"ON UPDATE CURRENT_TIMESTAMP"}}},{"name":"deleted_at","type":{"Type":12,"Ident":"","PkgPath":"","PkgName":"","Nillable":false,"RType":null},"default":true,"default_value":0,"default_kind":2,"position":{"Index":8,"MixedIn":false,"MixinIndex":0}}],"indexes":[{"fields":["status","user_id","channel"]},{"fields":["user_id","created_at"]}]}],"Features":["sql/lock","sql/upsert","privacy","entql","schema/snapshot","sql/modifier","sql/execquery"]}`
@@ -3,11 +3,13 @@
 package chatent
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"entgo.io/ent/dialect/sql"
+	"github.com/fanchunke/xgpt3/conversation"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
 )
@@ -27,6 +29,12 @@ type Message struct {
 	Content string `json:"content,omitempty"`
 	// SpouseID holds the value of the "spouse_id" field.
 	SpouseID int `json:"spouse_id,omitempty"`
+	// 回复引用的资料
+	Citations []conversation.Citation `json:"citations,omitempty"`
+	// 多模态消息的内容片段，JSON 编码，开启加密时与消息内容一样加密保存
+	Parts string `json:"parts,omitempty"`
+	// 幂等键，通常是平台的消息Id
+	IdempotencyKey *string `json:"idempotency_key,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -76,9 +84,11 @@ func (*Message) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case message.FieldCitations:
+			values[i] = new([]byte)
 		case message.FieldID, message.FieldSessionID, message.FieldSpouseID:
 			values[i] = new(sql.NullInt64)
-		case message.FieldFromUserID, message.FieldToUserID, message.FieldContent:
+		case message.FieldFromUserID, message.FieldToUserID, message.FieldContent, message.FieldParts, message.FieldIdempotencyKey:
 			values[i] = new(sql.NullString)
 		case message.FieldCreatedAt:
 			values[i] = new(sql.NullTime)
@@ -133,6 +143,27 @@ func (m *Message) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				m.SpouseID = int(value.Int64)
 			}
+		case message.FieldCitations:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field citations", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &m.Citations); err != nil {
+					return fmt.Errorf("unmarshal field citations: %w", err)
+				}
+			}
+		case message.FieldParts:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field parts", values[i])
+			} else if value.Valid {
+				m.Parts = value.String
+			}
+		case message.FieldIdempotencyKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field idempotency_key", values[i])
+			} else if value.Valid {
+				m.IdempotencyKey = new(string)
+				*m.IdempotencyKey = value.String
+			}
 		case message.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -192,6 +223,17 @@ func (m *Message) String() string {
 	builder.WriteString("spouse_id=")
 	builder.WriteString(fmt.Sprintf("%v", m.SpouseID))
 	builder.WriteString(", ")
+	builder.WriteString("citations=")
+	builder.WriteString(fmt.Sprintf("%v", m.Citations))
+	builder.WriteString(", ")
+	builder.WriteString("parts=")
+	builder.WriteString(m.Parts)
+	builder.WriteString(", ")
+	if v := m.IdempotencyKey; v != nil {
+		builder.WriteString("idempotency_key=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(m.CreatedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
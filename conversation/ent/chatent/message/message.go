@@ -21,6 +21,12 @@ const (
 	FieldContent = "content"
 	// FieldSpouseID holds the string denoting the spouse_id field in the database.
 	FieldSpouseID = "spouse_id"
+	// FieldCitations holds the string denoting the citations field in the database.
+	FieldCitations = "citations"
+	// FieldParts holds the string denoting the parts field in the database.
+	FieldParts = "parts"
+	// FieldIdempotencyKey holds the string denoting the idempotency_key field in the database.
+	FieldIdempotencyKey = "idempotency_key"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// EdgeSpouse holds the string denoting the spouse edge name in mutations.
@@ -50,6 +56,9 @@ var Columns = []string{
 	FieldToUserID,
 	FieldContent,
 	FieldSpouseID,
+	FieldCitations,
+	FieldParts,
+	FieldIdempotencyKey,
 	FieldCreatedAt,
 }
 
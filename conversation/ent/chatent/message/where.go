@@ -80,6 +80,16 @@ func SpouseID(v int) predicate.Message {
 	return predicate.Message(sql.FieldEQ(FieldSpouseID, v))
 }
 
+// Parts applies equality check predicate on the "parts" field. It's identical to PartsEQ.
+func Parts(v string) predicate.Message {
+	return predicate.Message(sql.FieldEQ(FieldParts, v))
+}
+
+// IdempotencyKey applies equality check predicate on the "idempotency_key" field. It's identical to IdempotencyKeyEQ.
+func IdempotencyKey(v string) predicate.Message {
+	return predicate.Message(sql.FieldEQ(FieldIdempotencyKey, v))
+}
+
 // CreatedAt applies equality check predicate on the "created_at" field. It's identical to CreatedAtEQ.
 func CreatedAt(v time.Time) predicate.Message {
 	return predicate.Message(sql.FieldEQ(FieldCreatedAt, v))
@@ -340,6 +350,166 @@ func SpouseIDNotNil() predicate.Message {
 	return predicate.Message(sql.FieldNotNull(FieldSpouseID))
 }
 
+// CitationsIsNil applies the IsNil predicate on the "citations" field.
+func CitationsIsNil() predicate.Message {
+	return predicate.Message(sql.FieldIsNull(FieldCitations))
+}
+
+// CitationsNotNil applies the NotNil predicate on the "citations" field.
+func CitationsNotNil() predicate.Message {
+	return predicate.Message(sql.FieldNotNull(FieldCitations))
+}
+
+// PartsEQ applies the EQ predicate on the "parts" field.
+func PartsEQ(v string) predicate.Message {
+	return predicate.Message(sql.FieldEQ(FieldParts, v))
+}
+
+// PartsNEQ applies the NEQ predicate on the "parts" field.
+func PartsNEQ(v string) predicate.Message {
+	return predicate.Message(sql.FieldNEQ(FieldParts, v))
+}
+
+// PartsIn applies the In predicate on the "parts" field.
+func PartsIn(vs ...string) predicate.Message {
+	return predicate.Message(sql.FieldIn(FieldParts, vs...))
+}
+
+// PartsNotIn applies the NotIn predicate on the "parts" field.
+func PartsNotIn(vs ...string) predicate.Message {
+	return predicate.Message(sql.FieldNotIn(FieldParts, vs...))
+}
+
+// PartsGT applies the GT predicate on the "parts" field.
+func PartsGT(v string) predicate.Message {
+	return predicate.Message(sql.FieldGT(FieldParts, v))
+}
+
+// PartsGTE applies the GTE predicate on the "parts" field.
+func PartsGTE(v string) predicate.Message {
+	return predicate.Message(sql.FieldGTE(FieldParts, v))
+}
+
+// PartsLT applies the LT predicate on the "parts" field.
+func PartsLT(v string) predicate.Message {
+	return predicate.Message(sql.FieldLT(FieldParts, v))
+}
+
+// PartsLTE applies the LTE predicate on the "parts" field.
+func PartsLTE(v string) predicate.Message {
+	return predicate.Message(sql.FieldLTE(FieldParts, v))
+}
+
+// PartsContains applies the Contains predicate on the "parts" field.
+func PartsContains(v string) predicate.Message {
+	return predicate.Message(sql.FieldContains(FieldParts, v))
+}
+
+// PartsHasPrefix applies the HasPrefix predicate on the "parts" field.
+func PartsHasPrefix(v string) predicate.Message {
+	return predicate.Message(sql.FieldHasPrefix(FieldParts, v))
+}
+
+// PartsHasSuffix applies the HasSuffix predicate on the "parts" field.
+func PartsHasSuffix(v string) predicate.Message {
+	return predicate.Message(sql.FieldHasSuffix(FieldParts, v))
+}
+
+// PartsIsNil applies the IsNil predicate on the "parts" field.
+func PartsIsNil() predicate.Message {
+	return predicate.Message(sql.FieldIsNull(FieldParts))
+}
+
+// PartsNotNil applies the NotNil predicate on the "parts" field.
+func PartsNotNil() predicate.Message {
+	return predicate.Message(sql.FieldNotNull(FieldParts))
+}
+
+// PartsEqualFold applies the EqualFold predicate on the "parts" field.
+func PartsEqualFold(v string) predicate.Message {
+	return predicate.Message(sql.FieldEqualFold(FieldParts, v))
+}
+
+// PartsContainsFold applies the ContainsFold predicate on the "parts" field.
+func PartsContainsFold(v string) predicate.Message {
+	return predicate.Message(sql.FieldContainsFold(FieldParts, v))
+}
+
+// IdempotencyKeyEQ applies the EQ predicate on the "idempotency_key" field.
+func IdempotencyKeyEQ(v string) predicate.Message {
+	return predicate.Message(sql.FieldEQ(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyNEQ applies the NEQ predicate on the "idempotency_key" field.
+func IdempotencyKeyNEQ(v string) predicate.Message {
+	return predicate.Message(sql.FieldNEQ(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyIn applies the In predicate on the "idempotency_key" field.
+func IdempotencyKeyIn(vs ...string) predicate.Message {
+	return predicate.Message(sql.FieldIn(FieldIdempotencyKey, vs...))
+}
+
+// IdempotencyKeyNotIn applies the NotIn predicate on the "idempotency_key" field.
+func IdempotencyKeyNotIn(vs ...string) predicate.Message {
+	return predicate.Message(sql.FieldNotIn(FieldIdempotencyKey, vs...))
+}
+
+// IdempotencyKeyGT applies the GT predicate on the "idempotency_key" field.
+func IdempotencyKeyGT(v string) predicate.Message {
+	return predicate.Message(sql.FieldGT(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyGTE applies the GTE predicate on the "idempotency_key" field.
+func IdempotencyKeyGTE(v string) predicate.Message {
+	return predicate.Message(sql.FieldGTE(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyLT applies the LT predicate on the "idempotency_key" field.
+func IdempotencyKeyLT(v string) predicate.Message {
+	return predicate.Message(sql.FieldLT(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyLTE applies the LTE predicate on the "idempotency_key" field.
+func IdempotencyKeyLTE(v string) predicate.Message {
+	return predicate.Message(sql.FieldLTE(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyContains applies the Contains predicate on the "idempotency_key" field.
+func IdempotencyKeyContains(v string) predicate.Message {
+	return predicate.Message(sql.FieldContains(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyHasPrefix applies the HasPrefix predicate on the "idempotency_key" field.
+func IdempotencyKeyHasPrefix(v string) predicate.Message {
+	return predicate.Message(sql.FieldHasPrefix(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyHasSuffix applies the HasSuffix predicate on the "idempotency_key" field.
+func IdempotencyKeyHasSuffix(v string) predicate.Message {
+	return predicate.Message(sql.FieldHasSuffix(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyIsNil applies the IsNil predicate on the "idempotency_key" field.
+func IdempotencyKeyIsNil() predicate.Message {
+	return predicate.Message(sql.FieldIsNull(FieldIdempotencyKey))
+}
+
+// IdempotencyKeyNotNil applies the NotNil predicate on the "idempotency_key" field.
+func IdempotencyKeyNotNil() predicate.Message {
+	return predicate.Message(sql.FieldNotNull(FieldIdempotencyKey))
+}
+
+// IdempotencyKeyEqualFold applies the EqualFold predicate on the "idempotency_key" field.
+func IdempotencyKeyEqualFold(v string) predicate.Message {
+	return predicate.Message(sql.FieldEqualFold(FieldIdempotencyKey, v))
+}
+
+// IdempotencyKeyContainsFold applies the ContainsFold predicate on the "idempotency_key" field.
+func IdempotencyKeyContainsFold(v string) predicate.Message {
+	return predicate.Message(sql.FieldContainsFold(FieldIdempotencyKey, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Message {
 	return predicate.Message(sql.FieldEQ(FieldCreatedAt, v))
@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
 )
@@ -69,6 +70,40 @@ func (mc *MessageCreate) SetNillableSpouseID(i *int) *MessageCreate {
 	return mc
 }
 
+// SetCitations sets the "citations" field.
+func (mc *MessageCreate) SetCitations(c []conversation.Citation) *MessageCreate {
+	mc.mutation.SetCitations(c)
+	return mc
+}
+
+// SetParts sets the "parts" field.
+func (mc *MessageCreate) SetParts(s string) *MessageCreate {
+	mc.mutation.SetParts(s)
+	return mc
+}
+
+// SetNillableParts sets the "parts" field if the given value is not nil.
+func (mc *MessageCreate) SetNillableParts(s *string) *MessageCreate {
+	if s != nil {
+		mc.SetParts(*s)
+	}
+	return mc
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (mc *MessageCreate) SetIdempotencyKey(s string) *MessageCreate {
+	mc.mutation.SetIdempotencyKey(s)
+	return mc
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (mc *MessageCreate) SetNillableIdempotencyKey(s *string) *MessageCreate {
+	if s != nil {
+		mc.SetIdempotencyKey(*s)
+	}
+	return mc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (mc *MessageCreate) SetCreatedAt(t time.Time) *MessageCreate {
 	mc.mutation.SetCreatedAt(t)
@@ -193,6 +228,18 @@ func (mc *MessageCreate) createSpec() (*Message, *sqlgraph.CreateSpec) {
 		_spec.SetField(message.FieldContent, field.TypeString, value)
 		_node.Content = value
 	}
+	if value, ok := mc.mutation.Citations(); ok {
+		_spec.SetField(message.FieldCitations, field.TypeJSON, value)
+		_node.Citations = value
+	}
+	if value, ok := mc.mutation.Parts(); ok {
+		_spec.SetField(message.FieldParts, field.TypeString, value)
+		_node.Parts = value
+	}
+	if value, ok := mc.mutation.IdempotencyKey(); ok {
+		_spec.SetField(message.FieldIdempotencyKey, field.TypeString, value)
+		_node.IdempotencyKey = &value
+	}
 	if value, ok := mc.mutation.CreatedAt(); ok {
 		_spec.SetField(message.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -256,7 +303,6 @@ func (mc *MessageCreate) createSpec() (*Message, *sqlgraph.CreateSpec) {
 //			SetSessionID(v+v).
 //		}).
 //		Exec(ctx)
-//
 func (mc *MessageCreate) OnConflict(opts ...sql.ConflictOption) *MessageUpsertOne {
 	mc.conflict = opts
 	return &MessageUpsertOne{
@@ -270,7 +316,6 @@ func (mc *MessageCreate) OnConflict(opts ...sql.ConflictOption) *MessageUpsertOn
 //	client.Message.Create().
 //		OnConflict(sql.ConflictColumns(columns...)).
 //		Exec(ctx)
-//
 func (mc *MessageCreate) OnConflictColumns(columns ...string) *MessageUpsertOne {
 	mc.conflict = append(mc.conflict, sql.ConflictColumns(columns...))
 	return &MessageUpsertOne{
@@ -363,6 +408,60 @@ func (u *MessageUpsert) ClearSpouseID() *MessageUpsert {
 	return u
 }
 
+// SetCitations sets the "citations" field.
+func (u *MessageUpsert) SetCitations(v []conversation.Citation) *MessageUpsert {
+	u.Set(message.FieldCitations, v)
+	return u
+}
+
+// UpdateCitations sets the "citations" field to the value that was provided on create.
+func (u *MessageUpsert) UpdateCitations() *MessageUpsert {
+	u.SetExcluded(message.FieldCitations)
+	return u
+}
+
+// ClearCitations clears the value of the "citations" field.
+func (u *MessageUpsert) ClearCitations() *MessageUpsert {
+	u.SetNull(message.FieldCitations)
+	return u
+}
+
+// SetParts sets the "parts" field.
+func (u *MessageUpsert) SetParts(v string) *MessageUpsert {
+	u.Set(message.FieldParts, v)
+	return u
+}
+
+// UpdateParts sets the "parts" field to the value that was provided on create.
+func (u *MessageUpsert) UpdateParts() *MessageUpsert {
+	u.SetExcluded(message.FieldParts)
+	return u
+}
+
+// ClearParts clears the value of the "parts" field.
+func (u *MessageUpsert) ClearParts() *MessageUpsert {
+	u.SetNull(message.FieldParts)
+	return u
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (u *MessageUpsert) SetIdempotencyKey(v string) *MessageUpsert {
+	u.Set(message.FieldIdempotencyKey, v)
+	return u
+}
+
+// UpdateIdempotencyKey sets the "idempotency_key" field to the value that was provided on create.
+func (u *MessageUpsert) UpdateIdempotencyKey() *MessageUpsert {
+	u.SetExcluded(message.FieldIdempotencyKey)
+	return u
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (u *MessageUpsert) ClearIdempotencyKey() *MessageUpsert {
+	u.SetNull(message.FieldIdempotencyKey)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create.
 // Using this option is equivalent to using:
 //
@@ -371,7 +470,6 @@ func (u *MessageUpsert) ClearSpouseID() *MessageUpsert {
 //			sql.ResolveWithNewValues(),
 //		).
 //		Exec(ctx)
-//
 func (u *MessageUpsertOne) UpdateNewValues() *MessageUpsertOne {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
 	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
@@ -385,10 +483,9 @@ func (u *MessageUpsertOne) UpdateNewValues() *MessageUpsertOne {
 // Ignore sets each column to itself in case of conflict.
 // Using this option is equivalent to using:
 //
-//  client.Message.Create().
-//      OnConflict(sql.ResolveWithIgnore()).
-//      Exec(ctx)
-//
+//	client.Message.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
 func (u *MessageUpsertOne) Ignore() *MessageUpsertOne {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
 	return u
@@ -494,6 +591,69 @@ func (u *MessageUpsertOne) ClearSpouseID() *MessageUpsertOne {
 	})
 }
 
+// SetCitations sets the "citations" field.
+func (u *MessageUpsertOne) SetCitations(v []conversation.Citation) *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.SetCitations(v)
+	})
+}
+
+// UpdateCitations sets the "citations" field to the value that was provided on create.
+func (u *MessageUpsertOne) UpdateCitations() *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.UpdateCitations()
+	})
+}
+
+// ClearCitations clears the value of the "citations" field.
+func (u *MessageUpsertOne) ClearCitations() *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.ClearCitations()
+	})
+}
+
+// SetParts sets the "parts" field.
+func (u *MessageUpsertOne) SetParts(v string) *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.SetParts(v)
+	})
+}
+
+// UpdateParts sets the "parts" field to the value that was provided on create.
+func (u *MessageUpsertOne) UpdateParts() *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.UpdateParts()
+	})
+}
+
+// ClearParts clears the value of the "parts" field.
+func (u *MessageUpsertOne) ClearParts() *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.ClearParts()
+	})
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (u *MessageUpsertOne) SetIdempotencyKey(v string) *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.SetIdempotencyKey(v)
+	})
+}
+
+// UpdateIdempotencyKey sets the "idempotency_key" field to the value that was provided on create.
+func (u *MessageUpsertOne) UpdateIdempotencyKey() *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.UpdateIdempotencyKey()
+	})
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (u *MessageUpsertOne) ClearIdempotencyKey() *MessageUpsertOne {
+	return u.Update(func(s *MessageUpsert) {
+		s.ClearIdempotencyKey()
+	})
+}
+
 // Exec executes the query.
 func (u *MessageUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -628,7 +788,6 @@ func (mcb *MessageCreateBulk) ExecX(ctx context.Context) {
 //			SetSessionID(v+v).
 //		}).
 //		Exec(ctx)
-//
 func (mcb *MessageCreateBulk) OnConflict(opts ...sql.ConflictOption) *MessageUpsertBulk {
 	mcb.conflict = opts
 	return &MessageUpsertBulk{
@@ -642,7 +801,6 @@ func (mcb *MessageCreateBulk) OnConflict(opts ...sql.ConflictOption) *MessageUps
 //	client.Message.Create().
 //		OnConflict(sql.ConflictColumns(columns...)).
 //		Exec(ctx)
-//
 func (mcb *MessageCreateBulk) OnConflictColumns(columns ...string) *MessageUpsertBulk {
 	mcb.conflict = append(mcb.conflict, sql.ConflictColumns(columns...))
 	return &MessageUpsertBulk{
@@ -664,7 +822,6 @@ type MessageUpsertBulk struct {
 //			sql.ResolveWithNewValues(),
 //		).
 //		Exec(ctx)
-//
 func (u *MessageUpsertBulk) UpdateNewValues() *MessageUpsertBulk {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
 	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
@@ -683,7 +840,6 @@ func (u *MessageUpsertBulk) UpdateNewValues() *MessageUpsertBulk {
 //	client.Message.Create().
 //		OnConflict(sql.ResolveWithIgnore()).
 //		Exec(ctx)
-//
 func (u *MessageUpsertBulk) Ignore() *MessageUpsertBulk {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
 	return u
@@ -789,6 +945,69 @@ func (u *MessageUpsertBulk) ClearSpouseID() *MessageUpsertBulk {
 	})
 }
 
+// SetCitations sets the "citations" field.
+func (u *MessageUpsertBulk) SetCitations(v []conversation.Citation) *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.SetCitations(v)
+	})
+}
+
+// UpdateCitations sets the "citations" field to the value that was provided on create.
+func (u *MessageUpsertBulk) UpdateCitations() *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.UpdateCitations()
+	})
+}
+
+// ClearCitations clears the value of the "citations" field.
+func (u *MessageUpsertBulk) ClearCitations() *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.ClearCitations()
+	})
+}
+
+// SetParts sets the "parts" field.
+func (u *MessageUpsertBulk) SetParts(v string) *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.SetParts(v)
+	})
+}
+
+// UpdateParts sets the "parts" field to the value that was provided on create.
+func (u *MessageUpsertBulk) UpdateParts() *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.UpdateParts()
+	})
+}
+
+// ClearParts clears the value of the "parts" field.
+func (u *MessageUpsertBulk) ClearParts() *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.ClearParts()
+	})
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (u *MessageUpsertBulk) SetIdempotencyKey(v string) *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.SetIdempotencyKey(v)
+	})
+}
+
+// UpdateIdempotencyKey sets the "idempotency_key" field to the value that was provided on create.
+func (u *MessageUpsertBulk) UpdateIdempotencyKey() *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.UpdateIdempotencyKey()
+	})
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (u *MessageUpsertBulk) ClearIdempotencyKey() *MessageUpsertBulk {
+	return u.Update(func(s *MessageUpsert) {
+		s.ClearIdempotencyKey()
+	})
+}
+
 // Exec executes the query.
 func (u *MessageUpsertBulk) Exec(ctx context.Context) error {
 	for i, b := range u.create.builders {
@@ -340,7 +340,6 @@ func (mq *MessageQuery) WithSession(opts ...func(*SessionQuery)) *MessageQuery {
 //		GroupBy(message.FieldSessionID).
 //		Aggregate(chatent.Count()).
 //		Scan(ctx, &v)
-//
 func (mq *MessageQuery) GroupBy(field string, fields ...string) *MessageGroupBy {
 	mq.ctx.Fields = append([]string{field}, fields...)
 	grbuild := &MessageGroupBy{build: mq}
@@ -362,7 +361,6 @@ func (mq *MessageQuery) GroupBy(field string, fields ...string) *MessageGroupBy
 //	client.Message.Query().
 //		Select(message.FieldSessionID).
 //		Scan(ctx, &v)
-//
 func (mq *MessageQuery) Select(fields ...string) *MessageSelect {
 	mq.ctx.Fields = append(mq.ctx.Fields, fields...)
 	sbuild := &MessageSelect{MessageQuery: mq}
@@ -9,7 +9,9 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
 	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
@@ -87,6 +89,64 @@ func (mu *MessageUpdate) ClearSpouseID() *MessageUpdate {
 	return mu
 }
 
+// SetCitations sets the "citations" field.
+func (mu *MessageUpdate) SetCitations(c []conversation.Citation) *MessageUpdate {
+	mu.mutation.SetCitations(c)
+	return mu
+}
+
+// AppendCitations appends c to the "citations" field.
+func (mu *MessageUpdate) AppendCitations(c []conversation.Citation) *MessageUpdate {
+	mu.mutation.AppendCitations(c)
+	return mu
+}
+
+// ClearCitations clears the value of the "citations" field.
+func (mu *MessageUpdate) ClearCitations() *MessageUpdate {
+	mu.mutation.ClearCitations()
+	return mu
+}
+
+// SetParts sets the "parts" field.
+func (mu *MessageUpdate) SetParts(s string) *MessageUpdate {
+	mu.mutation.SetParts(s)
+	return mu
+}
+
+// SetNillableParts sets the "parts" field if the given value is not nil.
+func (mu *MessageUpdate) SetNillableParts(s *string) *MessageUpdate {
+	if s != nil {
+		mu.SetParts(*s)
+	}
+	return mu
+}
+
+// ClearParts clears the value of the "parts" field.
+func (mu *MessageUpdate) ClearParts() *MessageUpdate {
+	mu.mutation.ClearParts()
+	return mu
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (mu *MessageUpdate) SetIdempotencyKey(s string) *MessageUpdate {
+	mu.mutation.SetIdempotencyKey(s)
+	return mu
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (mu *MessageUpdate) SetNillableIdempotencyKey(s *string) *MessageUpdate {
+	if s != nil {
+		mu.SetIdempotencyKey(*s)
+	}
+	return mu
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (mu *MessageUpdate) ClearIdempotencyKey() *MessageUpdate {
+	mu.mutation.ClearIdempotencyKey()
+	return mu
+}
+
 // SetSpouse sets the "spouse" edge to the Message entity.
 func (mu *MessageUpdate) SetSpouse(m *Message) *MessageUpdate {
 	return mu.SetSpouseID(m.ID)
@@ -174,6 +234,29 @@ func (mu *MessageUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := mu.mutation.Content(); ok {
 		_spec.SetField(message.FieldContent, field.TypeString, value)
 	}
+	if value, ok := mu.mutation.Citations(); ok {
+		_spec.SetField(message.FieldCitations, field.TypeJSON, value)
+	}
+	if value, ok := mu.mutation.AppendedCitations(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, message.FieldCitations, value)
+		})
+	}
+	if mu.mutation.CitationsCleared() {
+		_spec.ClearField(message.FieldCitations, field.TypeJSON)
+	}
+	if value, ok := mu.mutation.Parts(); ok {
+		_spec.SetField(message.FieldParts, field.TypeString, value)
+	}
+	if mu.mutation.PartsCleared() {
+		_spec.ClearField(message.FieldParts, field.TypeString)
+	}
+	if value, ok := mu.mutation.IdempotencyKey(); ok {
+		_spec.SetField(message.FieldIdempotencyKey, field.TypeString, value)
+	}
+	if mu.mutation.IdempotencyKeyCleared() {
+		_spec.ClearField(message.FieldIdempotencyKey, field.TypeString)
+	}
 	if mu.mutation.SpouseCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2O,
@@ -324,6 +407,64 @@ func (muo *MessageUpdateOne) ClearSpouseID() *MessageUpdateOne {
 	return muo
 }
 
+// SetCitations sets the "citations" field.
+func (muo *MessageUpdateOne) SetCitations(c []conversation.Citation) *MessageUpdateOne {
+	muo.mutation.SetCitations(c)
+	return muo
+}
+
+// AppendCitations appends c to the "citations" field.
+func (muo *MessageUpdateOne) AppendCitations(c []conversation.Citation) *MessageUpdateOne {
+	muo.mutation.AppendCitations(c)
+	return muo
+}
+
+// ClearCitations clears the value of the "citations" field.
+func (muo *MessageUpdateOne) ClearCitations() *MessageUpdateOne {
+	muo.mutation.ClearCitations()
+	return muo
+}
+
+// SetParts sets the "parts" field.
+func (muo *MessageUpdateOne) SetParts(s string) *MessageUpdateOne {
+	muo.mutation.SetParts(s)
+	return muo
+}
+
+// SetNillableParts sets the "parts" field if the given value is not nil.
+func (muo *MessageUpdateOne) SetNillableParts(s *string) *MessageUpdateOne {
+	if s != nil {
+		muo.SetParts(*s)
+	}
+	return muo
+}
+
+// ClearParts clears the value of the "parts" field.
+func (muo *MessageUpdateOne) ClearParts() *MessageUpdateOne {
+	muo.mutation.ClearParts()
+	return muo
+}
+
+// SetIdempotencyKey sets the "idempotency_key" field.
+func (muo *MessageUpdateOne) SetIdempotencyKey(s string) *MessageUpdateOne {
+	muo.mutation.SetIdempotencyKey(s)
+	return muo
+}
+
+// SetNillableIdempotencyKey sets the "idempotency_key" field if the given value is not nil.
+func (muo *MessageUpdateOne) SetNillableIdempotencyKey(s *string) *MessageUpdateOne {
+	if s != nil {
+		muo.SetIdempotencyKey(*s)
+	}
+	return muo
+}
+
+// ClearIdempotencyKey clears the value of the "idempotency_key" field.
+func (muo *MessageUpdateOne) ClearIdempotencyKey() *MessageUpdateOne {
+	muo.mutation.ClearIdempotencyKey()
+	return muo
+}
+
 // SetSpouse sets the "spouse" edge to the Message entity.
 func (muo *MessageUpdateOne) SetSpouse(m *Message) *MessageUpdateOne {
 	return muo.SetSpouseID(m.ID)
@@ -435,6 +576,29 @@ func (muo *MessageUpdateOne) sqlSave(ctx context.Context) (_node *Message, err e
 	if value, ok := muo.mutation.Content(); ok {
 		_spec.SetField(message.FieldContent, field.TypeString, value)
 	}
+	if value, ok := muo.mutation.Citations(); ok {
+		_spec.SetField(message.FieldCitations, field.TypeJSON, value)
+	}
+	if value, ok := muo.mutation.AppendedCitations(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, message.FieldCitations, value)
+		})
+	}
+	if muo.mutation.CitationsCleared() {
+		_spec.ClearField(message.FieldCitations, field.TypeJSON)
+	}
+	if value, ok := muo.mutation.Parts(); ok {
+		_spec.SetField(message.FieldParts, field.TypeString, value)
+	}
+	if muo.mutation.PartsCleared() {
+		_spec.ClearField(message.FieldParts, field.TypeString)
+	}
+	if value, ok := muo.mutation.IdempotencyKey(); ok {
+		_spec.SetField(message.FieldIdempotencyKey, field.TypeString, value)
+	}
+	if muo.mutation.IdempotencyKeyCleared() {
+		_spec.ClearField(message.FieldIdempotencyKey, field.TypeString)
+	}
 	if muo.mutation.SpouseCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2O,
@@ -3,17 +3,67 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
+	// DataKeysColumns holds the columns for the "data_keys" table.
+	DataKeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "user_id", Type: field.TypeString, Size: 50},
+		{Name: "version", Type: field.TypeInt},
+		{Name: "wrapped_key", Type: field.TypeBytes},
+		{Name: "created_at", Type: field.TypeTime, Default: "CURRENT_TIMESTAMP"},
+	}
+	// DataKeysTable holds the schema information for the "data_keys" table.
+	DataKeysTable = &schema.Table{
+		Name:       "data_keys",
+		Columns:    DataKeysColumns,
+		PrimaryKey: []*schema.Column{DataKeysColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "datakey_user_id_version",
+				Unique:  true,
+				Columns: []*schema.Column{DataKeysColumns[1], DataKeysColumns[2]},
+			},
+		},
+	}
+	// EmbeddingsColumns holds the columns for the "embeddings" table.
+	EmbeddingsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "namespace", Type: field.TypeString, Size: 100},
+		{Name: "user_id", Type: field.TypeString, Size: 50, Default: ""},
+		{Name: "session_id", Type: field.TypeInt, Default: 0},
+		{Name: "message_id", Type: field.TypeInt, Default: 0},
+		{Name: "content", Type: field.TypeString, Size: 2147483647},
+		{Name: "vector", Type: field.TypeBytes},
+		{Name: "metadata", Type: field.TypeJSON, Nullable: true},
+		{Name: "created_at", Type: field.TypeTime, Default: "CURRENT_TIMESTAMP"},
+	}
+	// EmbeddingsTable holds the schema information for the "embeddings" table.
+	EmbeddingsTable = &schema.Table{
+		Name:       "embeddings",
+		Columns:    EmbeddingsColumns,
+		PrimaryKey: []*schema.Column{EmbeddingsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "embedding_namespace_created_at",
+				Unique:  false,
+				Columns: []*schema.Column{EmbeddingsColumns[1], EmbeddingsColumns[8]},
+			},
+		},
+	}
 	// MessagesColumns holds the columns for the "messages" table.
 	MessagesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "from_user_id", Type: field.TypeString, Size: 50},
 		{Name: "to_user_id", Type: field.TypeString, Size: 50},
 		{Name: "content", Type: field.TypeString, Size: 2147483647},
+		{Name: "citations", Type: field.TypeJSON, Nullable: true},
+		{Name: "parts", Type: field.TypeString, Nullable: true, Size: 2147483647},
+		{Name: "idempotency_key", Type: field.TypeString, Nullable: true, Size: 100},
 		{Name: "created_at", Type: field.TypeTime, Default: "CURRENT_TIMESTAMP"},
 		{Name: "spouse_id", Type: field.TypeInt, Unique: true, Nullable: true},
 		{Name: "session_id", Type: field.TypeInt, Nullable: true},
@@ -26,13 +76,13 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "messages_messages_spouse",
-				Columns:    []*schema.Column{MessagesColumns[5]},
+				Columns:    []*schema.Column{MessagesColumns[8]},
 				RefColumns: []*schema.Column{MessagesColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
 			{
 				Symbol:     "messages_sessions_messages",
-				Columns:    []*schema.Column{MessagesColumns[6]},
+				Columns:    []*schema.Column{MessagesColumns[9]},
 				RefColumns: []*schema.Column{SessionsColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
@@ -41,12 +91,49 @@ var (
 			{
 				Name:    "message_session_id_from_user_id_created_at",
 				Unique:  false,
-				Columns: []*schema.Column{MessagesColumns[6], MessagesColumns[1], MessagesColumns[4]},
+				Columns: []*schema.Column{MessagesColumns[9], MessagesColumns[1], MessagesColumns[7]},
 			},
 			{
 				Name:    "message_session_id_to_user_id_created_at",
 				Unique:  false,
-				Columns: []*schema.Column{MessagesColumns[6], MessagesColumns[2], MessagesColumns[4]},
+				Columns: []*schema.Column{MessagesColumns[9], MessagesColumns[2], MessagesColumns[7]},
+			},
+			{
+				Name:    "message_from_user_id_idempotency_key",
+				Unique:  true,
+				Columns: []*schema.Column{MessagesColumns[1], MessagesColumns[6]},
+			},
+			{
+				Name:    "message_content",
+				Unique:  false,
+				Columns: []*schema.Column{MessagesColumns[3]},
+				Annotation: &entsql.IndexAnnotation{
+					Types: map[string]string{
+						"mysql": "FULLTEXT",
+					},
+				},
+			},
+		},
+	}
+	// ResponseCachesColumns holds the columns for the "response_caches" table.
+	ResponseCachesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "key", Type: field.TypeString, Unique: true, Size: 64},
+		{Name: "value", Type: field.TypeString, Size: 2147483647},
+		{Name: "user_id", Type: field.TypeString, Size: 50, Default: ""},
+		{Name: "created_at", Type: field.TypeTime, Default: "CURRENT_TIMESTAMP"},
+		{Name: "updated_at", Type: field.TypeTime},
+	}
+	// ResponseCachesTable holds the schema information for the "response_caches" table.
+	ResponseCachesTable = &schema.Table{
+		Name:       "response_caches",
+		Columns:    ResponseCachesColumns,
+		PrimaryKey: []*schema.Column{ResponseCachesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "responsecache_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{ResponseCachesColumns[3]},
 			},
 		},
 	}
@@ -54,7 +141,11 @@ var (
 	SessionsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
 		{Name: "user_id", Type: field.TypeString, Size: 50},
+		{Name: "channel", Type: field.TypeString, Size: 50, Default: "default"},
 		{Name: "status", Type: field.TypeBool, Default: false},
+		{Name: "title", Type: field.TypeString, Size: 255, Default: ""},
+		{Name: "group_chat", Type: field.TypeBool, Default: false},
+		{Name: "participants", Type: field.TypeJSON, Nullable: true},
 		{Name: "created_at", Type: field.TypeTime, Default: "CURRENT_TIMESTAMP"},
 		{Name: "updated_at", Type: field.TypeTime, Default: "CURRENT_TIMESTAMP", SchemaType: map[string]string{"mysql": "timestamp", "sqlite3": "timestamp"}},
 		{Name: "deleted_at", Type: field.TypeInt, Default: 0},
@@ -66,15 +157,23 @@ var (
 		PrimaryKey: []*schema.Column{SessionsColumns[0]},
 		Indexes: []*schema.Index{
 			{
-				Name:    "session_status_user_id",
+				Name:    "session_status_user_id_channel",
+				Unique:  false,
+				Columns: []*schema.Column{SessionsColumns[3], SessionsColumns[1], SessionsColumns[2]},
+			},
+			{
+				Name:    "session_user_id_created_at",
 				Unique:  false,
-				Columns: []*schema.Column{SessionsColumns[2], SessionsColumns[1]},
+				Columns: []*schema.Column{SessionsColumns[1], SessionsColumns[7]},
 			},
 		},
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		DataKeysTable,
+		EmbeddingsTable,
 		MessagesTable,
+		ResponseCachesTable,
 		SessionsTable,
 	}
 )
@@ -6,8 +6,17 @@ import (
 	"entgo.io/ent/dialect/sql"
 )
 
+// DataKey is the predicate function for datakey builders.
+type DataKey func(*sql.Selector)
+
+// Embedding is the predicate function for embedding builders.
+type Embedding func(*sql.Selector)
+
 // Message is the predicate function for message builders.
 type Message func(*sql.Selector)
 
+// ResponseCache is the predicate function for responsecache builders.
+type ResponseCache func(*sql.Selector)
+
 // Session is the predicate function for session builders.
 type Session func(*sql.Selector)
@@ -150,6 +150,54 @@ func DenyMutationOperationRule(op chatent.Op) MutationRule {
 	return OnMutationOperation(rule, op)
 }
 
+// The DataKeyQueryRuleFunc type is an adapter to allow the use of ordinary
+// functions as a query rule.
+type DataKeyQueryRuleFunc func(context.Context, *chatent.DataKeyQuery) error
+
+// EvalQuery return f(ctx, q).
+func (f DataKeyQueryRuleFunc) EvalQuery(ctx context.Context, q chatent.Query) error {
+	if q, ok := q.(*chatent.DataKeyQuery); ok {
+		return f(ctx, q)
+	}
+	return Denyf("chatent/privacy: unexpected query type %T, expect *chatent.DataKeyQuery", q)
+}
+
+// The DataKeyMutationRuleFunc type is an adapter to allow the use of ordinary
+// functions as a mutation rule.
+type DataKeyMutationRuleFunc func(context.Context, *chatent.DataKeyMutation) error
+
+// EvalMutation calls f(ctx, m).
+func (f DataKeyMutationRuleFunc) EvalMutation(ctx context.Context, m chatent.Mutation) error {
+	if m, ok := m.(*chatent.DataKeyMutation); ok {
+		return f(ctx, m)
+	}
+	return Denyf("chatent/privacy: unexpected mutation type %T, expect *chatent.DataKeyMutation", m)
+}
+
+// The EmbeddingQueryRuleFunc type is an adapter to allow the use of ordinary
+// functions as a query rule.
+type EmbeddingQueryRuleFunc func(context.Context, *chatent.EmbeddingQuery) error
+
+// EvalQuery return f(ctx, q).
+func (f EmbeddingQueryRuleFunc) EvalQuery(ctx context.Context, q chatent.Query) error {
+	if q, ok := q.(*chatent.EmbeddingQuery); ok {
+		return f(ctx, q)
+	}
+	return Denyf("chatent/privacy: unexpected query type %T, expect *chatent.EmbeddingQuery", q)
+}
+
+// The EmbeddingMutationRuleFunc type is an adapter to allow the use of ordinary
+// functions as a mutation rule.
+type EmbeddingMutationRuleFunc func(context.Context, *chatent.EmbeddingMutation) error
+
+// EvalMutation calls f(ctx, m).
+func (f EmbeddingMutationRuleFunc) EvalMutation(ctx context.Context, m chatent.Mutation) error {
+	if m, ok := m.(*chatent.EmbeddingMutation); ok {
+		return f(ctx, m)
+	}
+	return Denyf("chatent/privacy: unexpected mutation type %T, expect *chatent.EmbeddingMutation", m)
+}
+
 // The MessageQueryRuleFunc type is an adapter to allow the use of ordinary
 // functions as a query rule.
 type MessageQueryRuleFunc func(context.Context, *chatent.MessageQuery) error
@@ -174,6 +222,30 @@ func (f MessageMutationRuleFunc) EvalMutation(ctx context.Context, m chatent.Mut
 	return Denyf("chatent/privacy: unexpected mutation type %T, expect *chatent.MessageMutation", m)
 }
 
+// The ResponseCacheQueryRuleFunc type is an adapter to allow the use of ordinary
+// functions as a query rule.
+type ResponseCacheQueryRuleFunc func(context.Context, *chatent.ResponseCacheQuery) error
+
+// EvalQuery return f(ctx, q).
+func (f ResponseCacheQueryRuleFunc) EvalQuery(ctx context.Context, q chatent.Query) error {
+	if q, ok := q.(*chatent.ResponseCacheQuery); ok {
+		return f(ctx, q)
+	}
+	return Denyf("chatent/privacy: unexpected query type %T, expect *chatent.ResponseCacheQuery", q)
+}
+
+// The ResponseCacheMutationRuleFunc type is an adapter to allow the use of ordinary
+// functions as a mutation rule.
+type ResponseCacheMutationRuleFunc func(context.Context, *chatent.ResponseCacheMutation) error
+
+// EvalMutation calls f(ctx, m).
+func (f ResponseCacheMutationRuleFunc) EvalMutation(ctx context.Context, m chatent.Mutation) error {
+	if m, ok := m.(*chatent.ResponseCacheMutation); ok {
+		return f(ctx, m)
+	}
+	return Denyf("chatent/privacy: unexpected mutation type %T, expect *chatent.ResponseCacheMutation", m)
+}
+
 // The SessionQueryRuleFunc type is an adapter to allow the use of ordinary
 // functions as a query rule.
 type SessionQueryRuleFunc func(context.Context, *chatent.SessionQuery) error
@@ -233,8 +305,14 @@ var _ QueryMutationRule = FilterFunc(nil)
 
 func queryFilter(q chatent.Query) (Filter, error) {
 	switch q := q.(type) {
+	case *chatent.DataKeyQuery:
+		return q.Filter(), nil
+	case *chatent.EmbeddingQuery:
+		return q.Filter(), nil
 	case *chatent.MessageQuery:
 		return q.Filter(), nil
+	case *chatent.ResponseCacheQuery:
+		return q.Filter(), nil
 	case *chatent.SessionQuery:
 		return q.Filter(), nil
 	default:
@@ -244,8 +322,14 @@ func queryFilter(q chatent.Query) (Filter, error) {
 
 func mutationFilter(m chatent.Mutation) (Filter, error) {
 	switch m := m.(type) {
+	case *chatent.DataKeyMutation:
+		return m.Filter(), nil
+	case *chatent.EmbeddingMutation:
+		return m.Filter(), nil
 	case *chatent.MessageMutation:
 		return m.Filter(), nil
+	case *chatent.ResponseCacheMutation:
+		return m.Filter(), nil
 	case *chatent.SessionMutation:
 		return m.Filter(), nil
 	default:
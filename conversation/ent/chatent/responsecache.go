@@ -0,0 +1,146 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+// ResponseCache is the model entity for the ResponseCache schema.
+type ResponseCache struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id,omitempty"`
+	// 缓存键
+	Key string `json:"key,omitempty"`
+	// 缓存内容
+	Value string `json:"value,omitempty"`
+	// 缓存所属的用户Id，删除用户数据时一并删除
+	UserID string `json:"user_id,omitempty"`
+	// CreatedAt holds the value of the "created_at" field.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	// 缓存更新时间，用于判断是否过期
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*ResponseCache) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case responsecache.FieldID:
+			values[i] = new(sql.NullInt64)
+		case responsecache.FieldKey, responsecache.FieldValue, responsecache.FieldUserID:
+			values[i] = new(sql.NullString)
+		case responsecache.FieldCreatedAt, responsecache.FieldUpdatedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			return nil, fmt.Errorf("unexpected column %q for type ResponseCache", columns[i])
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the ResponseCache fields.
+func (rc *ResponseCache) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case responsecache.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			rc.ID = int(value.Int64)
+		case responsecache.FieldKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field key", values[i])
+			} else if value.Valid {
+				rc.Key = value.String
+			}
+		case responsecache.FieldValue:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field value", values[i])
+			} else if value.Valid {
+				rc.Value = value.String
+			}
+		case responsecache.FieldUserID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field user_id", values[i])
+			} else if value.Valid {
+				rc.UserID = value.String
+			}
+		case responsecache.FieldCreatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field created_at", values[i])
+			} else if value.Valid {
+				rc.CreatedAt = value.Time
+			}
+		case responsecache.FieldUpdatedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field updated_at", values[i])
+			} else if value.Valid {
+				rc.UpdatedAt = value.Time
+			}
+		}
+	}
+	return nil
+}
+
+// Update returns a builder for updating this ResponseCache.
+// Note that you need to call ResponseCache.Unwrap() before calling this method if this ResponseCache
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (rc *ResponseCache) Update() *ResponseCacheUpdateOne {
+	return NewResponseCacheClient(rc.config).UpdateOne(rc)
+}
+
+// Unwrap unwraps the ResponseCache entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (rc *ResponseCache) Unwrap() *ResponseCache {
+	_tx, ok := rc.config.driver.(*txDriver)
+	if !ok {
+		panic("chatent: ResponseCache is not a transactional entity")
+	}
+	rc.config.driver = _tx.drv
+	return rc
+}
+
+// String implements the fmt.Stringer.
+func (rc *ResponseCache) String() string {
+	var builder strings.Builder
+	builder.WriteString("ResponseCache(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", rc.ID))
+	builder.WriteString("key=")
+	builder.WriteString(rc.Key)
+	builder.WriteString(", ")
+	builder.WriteString("value=")
+	builder.WriteString(rc.Value)
+	builder.WriteString(", ")
+	builder.WriteString("user_id=")
+	builder.WriteString(rc.UserID)
+	builder.WriteString(", ")
+	builder.WriteString("created_at=")
+	builder.WriteString(rc.CreatedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("updated_at=")
+	builder.WriteString(rc.UpdatedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// ResponseCaches is a parsable slice of ResponseCache.
+type ResponseCaches []*ResponseCache
+
+func (rc ResponseCaches) config(cfg config) {
+	for _i := range rc {
+		rc[_i].config = cfg
+	}
+}
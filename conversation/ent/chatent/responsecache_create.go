@@ -0,0 +1,680 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+// ResponseCacheCreate is the builder for creating a ResponseCache entity.
+type ResponseCacheCreate struct {
+	config
+	mutation *ResponseCacheMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetKey sets the "key" field.
+func (rcc *ResponseCacheCreate) SetKey(s string) *ResponseCacheCreate {
+	rcc.mutation.SetKey(s)
+	return rcc
+}
+
+// SetValue sets the "value" field.
+func (rcc *ResponseCacheCreate) SetValue(s string) *ResponseCacheCreate {
+	rcc.mutation.SetValue(s)
+	return rcc
+}
+
+// SetUserID sets the "user_id" field.
+func (rcc *ResponseCacheCreate) SetUserID(s string) *ResponseCacheCreate {
+	rcc.mutation.SetUserID(s)
+	return rcc
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (rcc *ResponseCacheCreate) SetNillableUserID(s *string) *ResponseCacheCreate {
+	if s != nil {
+		rcc.SetUserID(*s)
+	}
+	return rcc
+}
+
+// SetCreatedAt sets the "created_at" field.
+func (rcc *ResponseCacheCreate) SetCreatedAt(t time.Time) *ResponseCacheCreate {
+	rcc.mutation.SetCreatedAt(t)
+	return rcc
+}
+
+// SetNillableCreatedAt sets the "created_at" field if the given value is not nil.
+func (rcc *ResponseCacheCreate) SetNillableCreatedAt(t *time.Time) *ResponseCacheCreate {
+	if t != nil {
+		rcc.SetCreatedAt(*t)
+	}
+	return rcc
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rcc *ResponseCacheCreate) SetUpdatedAt(t time.Time) *ResponseCacheCreate {
+	rcc.mutation.SetUpdatedAt(t)
+	return rcc
+}
+
+// SetNillableUpdatedAt sets the "updated_at" field if the given value is not nil.
+func (rcc *ResponseCacheCreate) SetNillableUpdatedAt(t *time.Time) *ResponseCacheCreate {
+	if t != nil {
+		rcc.SetUpdatedAt(*t)
+	}
+	return rcc
+}
+
+// Mutation returns the ResponseCacheMutation object of the builder.
+func (rcc *ResponseCacheCreate) Mutation() *ResponseCacheMutation {
+	return rcc.mutation
+}
+
+// Save creates the ResponseCache in the database.
+func (rcc *ResponseCacheCreate) Save(ctx context.Context) (*ResponseCache, error) {
+	rcc.defaults()
+	return withHooks[*ResponseCache, ResponseCacheMutation](ctx, rcc.sqlSave, rcc.mutation, rcc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (rcc *ResponseCacheCreate) SaveX(ctx context.Context) *ResponseCache {
+	v, err := rcc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rcc *ResponseCacheCreate) Exec(ctx context.Context) error {
+	_, err := rcc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcc *ResponseCacheCreate) ExecX(ctx context.Context) {
+	if err := rcc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (rcc *ResponseCacheCreate) defaults() {
+	if _, ok := rcc.mutation.UserID(); !ok {
+		v := responsecache.DefaultUserID
+		rcc.mutation.SetUserID(v)
+	}
+	if _, ok := rcc.mutation.CreatedAt(); !ok {
+		v := responsecache.DefaultCreatedAt()
+		rcc.mutation.SetCreatedAt(v)
+	}
+	if _, ok := rcc.mutation.UpdatedAt(); !ok {
+		v := responsecache.DefaultUpdatedAt()
+		rcc.mutation.SetUpdatedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (rcc *ResponseCacheCreate) check() error {
+	if _, ok := rcc.mutation.Key(); !ok {
+		return &ValidationError{Name: "key", err: errors.New(`chatent: missing required field "ResponseCache.key"`)}
+	}
+	if _, ok := rcc.mutation.Value(); !ok {
+		return &ValidationError{Name: "value", err: errors.New(`chatent: missing required field "ResponseCache.value"`)}
+	}
+	if _, ok := rcc.mutation.UserID(); !ok {
+		return &ValidationError{Name: "user_id", err: errors.New(`chatent: missing required field "ResponseCache.user_id"`)}
+	}
+	if _, ok := rcc.mutation.CreatedAt(); !ok {
+		return &ValidationError{Name: "created_at", err: errors.New(`chatent: missing required field "ResponseCache.created_at"`)}
+	}
+	if _, ok := rcc.mutation.UpdatedAt(); !ok {
+		return &ValidationError{Name: "updated_at", err: errors.New(`chatent: missing required field "ResponseCache.updated_at"`)}
+	}
+	return nil
+}
+
+func (rcc *ResponseCacheCreate) sqlSave(ctx context.Context) (*ResponseCache, error) {
+	if err := rcc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := rcc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, rcc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	id := _spec.ID.Value.(int64)
+	_node.ID = int(id)
+	rcc.mutation.id = &_node.ID
+	rcc.mutation.done = true
+	return _node, nil
+}
+
+func (rcc *ResponseCacheCreate) createSpec() (*ResponseCache, *sqlgraph.CreateSpec) {
+	var (
+		_node = &ResponseCache{config: rcc.config}
+		_spec = &sqlgraph.CreateSpec{
+			Table: responsecache.Table,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: responsecache.FieldID,
+			},
+		}
+	)
+	_spec.OnConflict = rcc.conflict
+	if value, ok := rcc.mutation.Key(); ok {
+		_spec.SetField(responsecache.FieldKey, field.TypeString, value)
+		_node.Key = value
+	}
+	if value, ok := rcc.mutation.Value(); ok {
+		_spec.SetField(responsecache.FieldValue, field.TypeString, value)
+		_node.Value = value
+	}
+	if value, ok := rcc.mutation.UserID(); ok {
+		_spec.SetField(responsecache.FieldUserID, field.TypeString, value)
+		_node.UserID = value
+	}
+	if value, ok := rcc.mutation.CreatedAt(); ok {
+		_spec.SetField(responsecache.FieldCreatedAt, field.TypeTime, value)
+		_node.CreatedAt = value
+	}
+	if value, ok := rcc.mutation.UpdatedAt(); ok {
+		_spec.SetField(responsecache.FieldUpdatedAt, field.TypeTime, value)
+		_node.UpdatedAt = value
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.ResponseCache.Create().
+//		SetKey(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ResponseCacheUpsert) {
+//			SetKey(v+v).
+//		}).
+//		Exec(ctx)
+func (rcc *ResponseCacheCreate) OnConflict(opts ...sql.ConflictOption) *ResponseCacheUpsertOne {
+	rcc.conflict = opts
+	return &ResponseCacheUpsertOne{
+		create: rcc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.ResponseCache.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (rcc *ResponseCacheCreate) OnConflictColumns(columns ...string) *ResponseCacheUpsertOne {
+	rcc.conflict = append(rcc.conflict, sql.ConflictColumns(columns...))
+	return &ResponseCacheUpsertOne{
+		create: rcc,
+	}
+}
+
+type (
+	// ResponseCacheUpsertOne is the builder for "upsert"-ing
+	//  one ResponseCache node.
+	ResponseCacheUpsertOne struct {
+		create *ResponseCacheCreate
+	}
+
+	// ResponseCacheUpsert is the "OnConflict" setter.
+	ResponseCacheUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetKey sets the "key" field.
+func (u *ResponseCacheUpsert) SetKey(v string) *ResponseCacheUpsert {
+	u.Set(responsecache.FieldKey, v)
+	return u
+}
+
+// UpdateKey sets the "key" field to the value that was provided on create.
+func (u *ResponseCacheUpsert) UpdateKey() *ResponseCacheUpsert {
+	u.SetExcluded(responsecache.FieldKey)
+	return u
+}
+
+// SetValue sets the "value" field.
+func (u *ResponseCacheUpsert) SetValue(v string) *ResponseCacheUpsert {
+	u.Set(responsecache.FieldValue, v)
+	return u
+}
+
+// UpdateValue sets the "value" field to the value that was provided on create.
+func (u *ResponseCacheUpsert) UpdateValue() *ResponseCacheUpsert {
+	u.SetExcluded(responsecache.FieldValue)
+	return u
+}
+
+// SetUserID sets the "user_id" field.
+func (u *ResponseCacheUpsert) SetUserID(v string) *ResponseCacheUpsert {
+	u.Set(responsecache.FieldUserID, v)
+	return u
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *ResponseCacheUpsert) UpdateUserID() *ResponseCacheUpsert {
+	u.SetExcluded(responsecache.FieldUserID)
+	return u
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ResponseCacheUpsert) SetUpdatedAt(v time.Time) *ResponseCacheUpsert {
+	u.Set(responsecache.FieldUpdatedAt, v)
+	return u
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ResponseCacheUpsert) UpdateUpdatedAt() *ResponseCacheUpsert {
+	u.SetExcluded(responsecache.FieldUpdatedAt)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.ResponseCache.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ResponseCacheUpsertOne) UpdateNewValues() *ResponseCacheUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.CreatedAt(); exists {
+			s.SetIgnore(responsecache.FieldCreatedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.ResponseCache.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *ResponseCacheUpsertOne) Ignore() *ResponseCacheUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ResponseCacheUpsertOne) DoNothing() *ResponseCacheUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ResponseCacheCreate.OnConflict
+// documentation for more info.
+func (u *ResponseCacheUpsertOne) Update(set func(*ResponseCacheUpsert)) *ResponseCacheUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ResponseCacheUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetKey sets the "key" field.
+func (u *ResponseCacheUpsertOne) SetKey(v string) *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetKey(v)
+	})
+}
+
+// UpdateKey sets the "key" field to the value that was provided on create.
+func (u *ResponseCacheUpsertOne) UpdateKey() *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateKey()
+	})
+}
+
+// SetValue sets the "value" field.
+func (u *ResponseCacheUpsertOne) SetValue(v string) *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetValue(v)
+	})
+}
+
+// UpdateValue sets the "value" field to the value that was provided on create.
+func (u *ResponseCacheUpsertOne) UpdateValue() *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateValue()
+	})
+}
+
+// SetUserID sets the "user_id" field.
+func (u *ResponseCacheUpsertOne) SetUserID(v string) *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetUserID(v)
+	})
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *ResponseCacheUpsertOne) UpdateUserID() *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateUserID()
+	})
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ResponseCacheUpsertOne) SetUpdatedAt(v time.Time) *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetUpdatedAt(v)
+	})
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ResponseCacheUpsertOne) UpdateUpdatedAt() *ResponseCacheUpsertOne {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateUpdatedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *ResponseCacheUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("chatent: missing options for ResponseCacheCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ResponseCacheUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *ResponseCacheUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *ResponseCacheUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// ResponseCacheCreateBulk is the builder for creating many ResponseCache entities in bulk.
+type ResponseCacheCreateBulk struct {
+	config
+	builders []*ResponseCacheCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the ResponseCache entities in the database.
+func (rccb *ResponseCacheCreateBulk) Save(ctx context.Context) ([]*ResponseCache, error) {
+	specs := make([]*sqlgraph.CreateSpec, len(rccb.builders))
+	nodes := make([]*ResponseCache, len(rccb.builders))
+	mutators := make([]Mutator, len(rccb.builders))
+	for i := range rccb.builders {
+		func(i int, root context.Context) {
+			builder := rccb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*ResponseCacheMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				nodes[i], specs[i] = builder.createSpec()
+				var err error
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, rccb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = rccb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, rccb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, rccb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rccb *ResponseCacheCreateBulk) SaveX(ctx context.Context) []*ResponseCache {
+	v, err := rccb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (rccb *ResponseCacheCreateBulk) Exec(ctx context.Context) error {
+	_, err := rccb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rccb *ResponseCacheCreateBulk) ExecX(ctx context.Context) {
+	if err := rccb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.ResponseCache.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.ResponseCacheUpsert) {
+//			SetKey(v+v).
+//		}).
+//		Exec(ctx)
+func (rccb *ResponseCacheCreateBulk) OnConflict(opts ...sql.ConflictOption) *ResponseCacheUpsertBulk {
+	rccb.conflict = opts
+	return &ResponseCacheUpsertBulk{
+		create: rccb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.ResponseCache.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (rccb *ResponseCacheCreateBulk) OnConflictColumns(columns ...string) *ResponseCacheUpsertBulk {
+	rccb.conflict = append(rccb.conflict, sql.ConflictColumns(columns...))
+	return &ResponseCacheUpsertBulk{
+		create: rccb,
+	}
+}
+
+// ResponseCacheUpsertBulk is the builder for "upsert"-ing
+// a bulk of ResponseCache nodes.
+type ResponseCacheUpsertBulk struct {
+	create *ResponseCacheCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.ResponseCache.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *ResponseCacheUpsertBulk) UpdateNewValues() *ResponseCacheUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.CreatedAt(); exists {
+				s.SetIgnore(responsecache.FieldCreatedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.ResponseCache.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *ResponseCacheUpsertBulk) Ignore() *ResponseCacheUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *ResponseCacheUpsertBulk) DoNothing() *ResponseCacheUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the ResponseCacheCreateBulk.OnConflict
+// documentation for more info.
+func (u *ResponseCacheUpsertBulk) Update(set func(*ResponseCacheUpsert)) *ResponseCacheUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&ResponseCacheUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetKey sets the "key" field.
+func (u *ResponseCacheUpsertBulk) SetKey(v string) *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetKey(v)
+	})
+}
+
+// UpdateKey sets the "key" field to the value that was provided on create.
+func (u *ResponseCacheUpsertBulk) UpdateKey() *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateKey()
+	})
+}
+
+// SetValue sets the "value" field.
+func (u *ResponseCacheUpsertBulk) SetValue(v string) *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetValue(v)
+	})
+}
+
+// UpdateValue sets the "value" field to the value that was provided on create.
+func (u *ResponseCacheUpsertBulk) UpdateValue() *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateValue()
+	})
+}
+
+// SetUserID sets the "user_id" field.
+func (u *ResponseCacheUpsertBulk) SetUserID(v string) *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetUserID(v)
+	})
+}
+
+// UpdateUserID sets the "user_id" field to the value that was provided on create.
+func (u *ResponseCacheUpsertBulk) UpdateUserID() *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateUserID()
+	})
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (u *ResponseCacheUpsertBulk) SetUpdatedAt(v time.Time) *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.SetUpdatedAt(v)
+	})
+}
+
+// UpdateUpdatedAt sets the "updated_at" field to the value that was provided on create.
+func (u *ResponseCacheUpsertBulk) UpdateUpdatedAt() *ResponseCacheUpsertBulk {
+	return u.Update(func(s *ResponseCacheUpsert) {
+		s.UpdateUpdatedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *ResponseCacheUpsertBulk) Exec(ctx context.Context) error {
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("chatent: OnConflict was set for builder %d. Set it on the ResponseCacheCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("chatent: missing options for ResponseCacheCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *ResponseCacheUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
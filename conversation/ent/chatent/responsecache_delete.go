@@ -0,0 +1,96 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+// ResponseCacheDelete is the builder for deleting a ResponseCache entity.
+type ResponseCacheDelete struct {
+	config
+	hooks    []Hook
+	mutation *ResponseCacheMutation
+}
+
+// Where appends a list predicates to the ResponseCacheDelete builder.
+func (rcd *ResponseCacheDelete) Where(ps ...predicate.ResponseCache) *ResponseCacheDelete {
+	rcd.mutation.Where(ps...)
+	return rcd
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (rcd *ResponseCacheDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks[int, ResponseCacheMutation](ctx, rcd.sqlExec, rcd.mutation, rcd.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcd *ResponseCacheDelete) ExecX(ctx context.Context) int {
+	n, err := rcd.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (rcd *ResponseCacheDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := &sqlgraph.DeleteSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table: responsecache.Table,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: responsecache.FieldID,
+			},
+		},
+	}
+	if ps := rcd.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, rcd.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	rcd.mutation.done = true
+	return affected, err
+}
+
+// ResponseCacheDeleteOne is the builder for deleting a single ResponseCache entity.
+type ResponseCacheDeleteOne struct {
+	rcd *ResponseCacheDelete
+}
+
+// Where appends a list predicates to the ResponseCacheDelete builder.
+func (rcdo *ResponseCacheDeleteOne) Where(ps ...predicate.ResponseCache) *ResponseCacheDeleteOne {
+	rcdo.rcd.mutation.Where(ps...)
+	return rcdo
+}
+
+// Exec executes the deletion query.
+func (rcdo *ResponseCacheDeleteOne) Exec(ctx context.Context) error {
+	n, err := rcdo.rcd.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{responsecache.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcdo *ResponseCacheDeleteOne) ExecX(ctx context.Context) {
+	if err := rcdo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,581 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+// ResponseCacheQuery is the builder for querying ResponseCache entities.
+type ResponseCacheQuery struct {
+	config
+	ctx        *QueryContext
+	order      []OrderFunc
+	inters     []Interceptor
+	predicates []predicate.ResponseCache
+	modifiers  []func(*sql.Selector)
+	// intermediate query (i.e. traversal path).
+	sql  *sql.Selector
+	path func(context.Context) (*sql.Selector, error)
+}
+
+// Where adds a new predicate for the ResponseCacheQuery builder.
+func (rcq *ResponseCacheQuery) Where(ps ...predicate.ResponseCache) *ResponseCacheQuery {
+	rcq.predicates = append(rcq.predicates, ps...)
+	return rcq
+}
+
+// Limit the number of records to be returned by this query.
+func (rcq *ResponseCacheQuery) Limit(limit int) *ResponseCacheQuery {
+	rcq.ctx.Limit = &limit
+	return rcq
+}
+
+// Offset to start from.
+func (rcq *ResponseCacheQuery) Offset(offset int) *ResponseCacheQuery {
+	rcq.ctx.Offset = &offset
+	return rcq
+}
+
+// Unique configures the query builder to filter duplicate records on query.
+// By default, unique is set to true, and can be disabled using this method.
+func (rcq *ResponseCacheQuery) Unique(unique bool) *ResponseCacheQuery {
+	rcq.ctx.Unique = &unique
+	return rcq
+}
+
+// Order specifies how the records should be ordered.
+func (rcq *ResponseCacheQuery) Order(o ...OrderFunc) *ResponseCacheQuery {
+	rcq.order = append(rcq.order, o...)
+	return rcq
+}
+
+// First returns the first ResponseCache entity from the query.
+// Returns a *NotFoundError when no ResponseCache was found.
+func (rcq *ResponseCacheQuery) First(ctx context.Context) (*ResponseCache, error) {
+	nodes, err := rcq.Limit(1).All(setContextOp(ctx, rcq.ctx, "First"))
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, &NotFoundError{responsecache.Label}
+	}
+	return nodes[0], nil
+}
+
+// FirstX is like First, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) FirstX(ctx context.Context) *ResponseCache {
+	node, err := rcq.First(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return node
+}
+
+// FirstID returns the first ResponseCache ID from the query.
+// Returns a *NotFoundError when no ResponseCache ID was found.
+func (rcq *ResponseCacheQuery) FirstID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = rcq.Limit(1).IDs(setContextOp(ctx, rcq.ctx, "FirstID")); err != nil {
+		return
+	}
+	if len(ids) == 0 {
+		err = &NotFoundError{responsecache.Label}
+		return
+	}
+	return ids[0], nil
+}
+
+// FirstIDX is like FirstID, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) FirstIDX(ctx context.Context) int {
+	id, err := rcq.FirstID(ctx)
+	if err != nil && !IsNotFound(err) {
+		panic(err)
+	}
+	return id
+}
+
+// Only returns a single ResponseCache entity found by the query, ensuring it only returns one.
+// Returns a *NotSingularError when more than one ResponseCache entity is found.
+// Returns a *NotFoundError when no ResponseCache entities are found.
+func (rcq *ResponseCacheQuery) Only(ctx context.Context) (*ResponseCache, error) {
+	nodes, err := rcq.Limit(2).All(setContextOp(ctx, rcq.ctx, "Only"))
+	if err != nil {
+		return nil, err
+	}
+	switch len(nodes) {
+	case 1:
+		return nodes[0], nil
+	case 0:
+		return nil, &NotFoundError{responsecache.Label}
+	default:
+		return nil, &NotSingularError{responsecache.Label}
+	}
+}
+
+// OnlyX is like Only, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) OnlyX(ctx context.Context) *ResponseCache {
+	node, err := rcq.Only(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// OnlyID is like Only, but returns the only ResponseCache ID in the query.
+// Returns a *NotSingularError when more than one ResponseCache ID is found.
+// Returns a *NotFoundError when no entities are found.
+func (rcq *ResponseCacheQuery) OnlyID(ctx context.Context) (id int, err error) {
+	var ids []int
+	if ids, err = rcq.Limit(2).IDs(setContextOp(ctx, rcq.ctx, "OnlyID")); err != nil {
+		return
+	}
+	switch len(ids) {
+	case 1:
+		id = ids[0]
+	case 0:
+		err = &NotFoundError{responsecache.Label}
+	default:
+		err = &NotSingularError{responsecache.Label}
+	}
+	return
+}
+
+// OnlyIDX is like OnlyID, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) OnlyIDX(ctx context.Context) int {
+	id, err := rcq.OnlyID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// All executes the query and returns a list of ResponseCaches.
+func (rcq *ResponseCacheQuery) All(ctx context.Context) ([]*ResponseCache, error) {
+	ctx = setContextOp(ctx, rcq.ctx, "All")
+	if err := rcq.prepareQuery(ctx); err != nil {
+		return nil, err
+	}
+	qr := querierAll[[]*ResponseCache, *ResponseCacheQuery]()
+	return withInterceptors[[]*ResponseCache](ctx, rcq, qr, rcq.inters)
+}
+
+// AllX is like All, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) AllX(ctx context.Context) []*ResponseCache {
+	nodes, err := rcq.All(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return nodes
+}
+
+// IDs executes the query and returns a list of ResponseCache IDs.
+func (rcq *ResponseCacheQuery) IDs(ctx context.Context) ([]int, error) {
+	var ids []int
+	ctx = setContextOp(ctx, rcq.ctx, "IDs")
+	if err := rcq.Select(responsecache.FieldID).Scan(ctx, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// IDsX is like IDs, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) IDsX(ctx context.Context) []int {
+	ids, err := rcq.IDs(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return ids
+}
+
+// Count returns the count of the given query.
+func (rcq *ResponseCacheQuery) Count(ctx context.Context) (int, error) {
+	ctx = setContextOp(ctx, rcq.ctx, "Count")
+	if err := rcq.prepareQuery(ctx); err != nil {
+		return 0, err
+	}
+	return withInterceptors[int](ctx, rcq, querierCount[*ResponseCacheQuery](), rcq.inters)
+}
+
+// CountX is like Count, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) CountX(ctx context.Context) int {
+	count, err := rcq.Count(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return count
+}
+
+// Exist returns true if the query has elements in the graph.
+func (rcq *ResponseCacheQuery) Exist(ctx context.Context) (bool, error) {
+	ctx = setContextOp(ctx, rcq.ctx, "Exist")
+	switch _, err := rcq.FirstID(ctx); {
+	case IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("chatent: check existence: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// ExistX is like Exist, but panics if an error occurs.
+func (rcq *ResponseCacheQuery) ExistX(ctx context.Context) bool {
+	exist, err := rcq.Exist(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return exist
+}
+
+// Clone returns a duplicate of the ResponseCacheQuery builder, including all associated steps. It can be
+// used to prepare common query builders and use them differently after the clone is made.
+func (rcq *ResponseCacheQuery) Clone() *ResponseCacheQuery {
+	if rcq == nil {
+		return nil
+	}
+	return &ResponseCacheQuery{
+		config:     rcq.config,
+		ctx:        rcq.ctx.Clone(),
+		order:      append([]OrderFunc{}, rcq.order...),
+		inters:     append([]Interceptor{}, rcq.inters...),
+		predicates: append([]predicate.ResponseCache{}, rcq.predicates...),
+		// clone intermediate query.
+		sql:  rcq.sql.Clone(),
+		path: rcq.path,
+	}
+}
+
+// GroupBy is used to group vertices by one or more fields/columns.
+// It is often used with aggregate functions, like: count, max, mean, min, sum.
+//
+// Example:
+//
+//	var v []struct {
+//		Key string `json:"key,omitempty"`
+//		Count int `json:"count,omitempty"`
+//	}
+//
+//	client.ResponseCache.Query().
+//		GroupBy(responsecache.FieldKey).
+//		Aggregate(chatent.Count()).
+//		Scan(ctx, &v)
+func (rcq *ResponseCacheQuery) GroupBy(field string, fields ...string) *ResponseCacheGroupBy {
+	rcq.ctx.Fields = append([]string{field}, fields...)
+	grbuild := &ResponseCacheGroupBy{build: rcq}
+	grbuild.flds = &rcq.ctx.Fields
+	grbuild.label = responsecache.Label
+	grbuild.scan = grbuild.Scan
+	return grbuild
+}
+
+// Select allows the selection one or more fields/columns for the given query,
+// instead of selecting all fields in the entity.
+//
+// Example:
+//
+//	var v []struct {
+//		Key string `json:"key,omitempty"`
+//	}
+//
+//	client.ResponseCache.Query().
+//		Select(responsecache.FieldKey).
+//		Scan(ctx, &v)
+func (rcq *ResponseCacheQuery) Select(fields ...string) *ResponseCacheSelect {
+	rcq.ctx.Fields = append(rcq.ctx.Fields, fields...)
+	sbuild := &ResponseCacheSelect{ResponseCacheQuery: rcq}
+	sbuild.label = responsecache.Label
+	sbuild.flds, sbuild.scan = &rcq.ctx.Fields, sbuild.Scan
+	return sbuild
+}
+
+// Aggregate returns a ResponseCacheSelect configured with the given aggregations.
+func (rcq *ResponseCacheQuery) Aggregate(fns ...AggregateFunc) *ResponseCacheSelect {
+	return rcq.Select().Aggregate(fns...)
+}
+
+func (rcq *ResponseCacheQuery) prepareQuery(ctx context.Context) error {
+	for _, inter := range rcq.inters {
+		if inter == nil {
+			return fmt.Errorf("chatent: uninitialized interceptor (forgotten import chatent/runtime?)")
+		}
+		if trv, ok := inter.(Traverser); ok {
+			if err := trv.Traverse(ctx, rcq); err != nil {
+				return err
+			}
+		}
+	}
+	for _, f := range rcq.ctx.Fields {
+		if !responsecache.ValidColumn(f) {
+			return &ValidationError{Name: f, err: fmt.Errorf("chatent: invalid field %q for query", f)}
+		}
+	}
+	if rcq.path != nil {
+		prev, err := rcq.path(ctx)
+		if err != nil {
+			return err
+		}
+		rcq.sql = prev
+	}
+	return nil
+}
+
+func (rcq *ResponseCacheQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*ResponseCache, error) {
+	var (
+		nodes = []*ResponseCache{}
+		_spec = rcq.querySpec()
+	)
+	_spec.ScanValues = func(columns []string) ([]any, error) {
+		return (*ResponseCache).scanValues(nil, columns)
+	}
+	_spec.Assign = func(columns []string, values []any) error {
+		node := &ResponseCache{config: rcq.config}
+		nodes = append(nodes, node)
+		return node.assignValues(columns, values)
+	}
+	if len(rcq.modifiers) > 0 {
+		_spec.Modifiers = rcq.modifiers
+	}
+	for i := range hooks {
+		hooks[i](ctx, _spec)
+	}
+	if err := sqlgraph.QueryNodes(ctx, rcq.driver, _spec); err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nodes, nil
+	}
+	return nodes, nil
+}
+
+func (rcq *ResponseCacheQuery) sqlCount(ctx context.Context) (int, error) {
+	_spec := rcq.querySpec()
+	if len(rcq.modifiers) > 0 {
+		_spec.Modifiers = rcq.modifiers
+	}
+	_spec.Node.Columns = rcq.ctx.Fields
+	if len(rcq.ctx.Fields) > 0 {
+		_spec.Unique = rcq.ctx.Unique != nil && *rcq.ctx.Unique
+	}
+	return sqlgraph.CountNodes(ctx, rcq.driver, _spec)
+}
+
+func (rcq *ResponseCacheQuery) querySpec() *sqlgraph.QuerySpec {
+	_spec := &sqlgraph.QuerySpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   responsecache.Table,
+			Columns: responsecache.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: responsecache.FieldID,
+			},
+		},
+		From:   rcq.sql,
+		Unique: true,
+	}
+	if unique := rcq.ctx.Unique; unique != nil {
+		_spec.Unique = *unique
+	}
+	if fields := rcq.ctx.Fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, responsecache.FieldID)
+		for i := range fields {
+			if fields[i] != responsecache.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, fields[i])
+			}
+		}
+	}
+	if ps := rcq.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if limit := rcq.ctx.Limit; limit != nil {
+		_spec.Limit = *limit
+	}
+	if offset := rcq.ctx.Offset; offset != nil {
+		_spec.Offset = *offset
+	}
+	if ps := rcq.order; len(ps) > 0 {
+		_spec.Order = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	return _spec
+}
+
+func (rcq *ResponseCacheQuery) sqlQuery(ctx context.Context) *sql.Selector {
+	builder := sql.Dialect(rcq.driver.Dialect())
+	t1 := builder.Table(responsecache.Table)
+	columns := rcq.ctx.Fields
+	if len(columns) == 0 {
+		columns = responsecache.Columns
+	}
+	selector := builder.Select(t1.Columns(columns...)...).From(t1)
+	if rcq.sql != nil {
+		selector = rcq.sql
+		selector.Select(selector.Columns(columns...)...)
+	}
+	if rcq.ctx.Unique != nil && *rcq.ctx.Unique {
+		selector.Distinct()
+	}
+	for _, m := range rcq.modifiers {
+		m(selector)
+	}
+	for _, p := range rcq.predicates {
+		p(selector)
+	}
+	for _, p := range rcq.order {
+		p(selector)
+	}
+	if offset := rcq.ctx.Offset; offset != nil {
+		// limit is mandatory for offset clause. We start
+		// with default value, and override it below if needed.
+		selector.Offset(*offset).Limit(math.MaxInt32)
+	}
+	if limit := rcq.ctx.Limit; limit != nil {
+		selector.Limit(*limit)
+	}
+	return selector
+}
+
+// ForUpdate locks the selected rows against concurrent updates, and prevent them from being
+// updated, deleted or "selected ... for update" by other sessions, until the transaction is
+// either committed or rolled-back.
+func (rcq *ResponseCacheQuery) ForUpdate(opts ...sql.LockOption) *ResponseCacheQuery {
+	if rcq.driver.Dialect() == dialect.Postgres {
+		rcq.Unique(false)
+	}
+	rcq.modifiers = append(rcq.modifiers, func(s *sql.Selector) {
+		s.ForUpdate(opts...)
+	})
+	return rcq
+}
+
+// ForShare behaves similarly to ForUpdate, except that it acquires a shared mode lock
+// on any rows that are read. Other sessions can read the rows, but cannot modify them
+// until your transaction commits.
+func (rcq *ResponseCacheQuery) ForShare(opts ...sql.LockOption) *ResponseCacheQuery {
+	if rcq.driver.Dialect() == dialect.Postgres {
+		rcq.Unique(false)
+	}
+	rcq.modifiers = append(rcq.modifiers, func(s *sql.Selector) {
+		s.ForShare(opts...)
+	})
+	return rcq
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (rcq *ResponseCacheQuery) Modify(modifiers ...func(s *sql.Selector)) *ResponseCacheSelect {
+	rcq.modifiers = append(rcq.modifiers, modifiers...)
+	return rcq.Select()
+}
+
+// ResponseCacheGroupBy is the group-by builder for ResponseCache entities.
+type ResponseCacheGroupBy struct {
+	selector
+	build *ResponseCacheQuery
+}
+
+// Aggregate adds the given aggregation functions to the group-by query.
+func (rcgb *ResponseCacheGroupBy) Aggregate(fns ...AggregateFunc) *ResponseCacheGroupBy {
+	rcgb.fns = append(rcgb.fns, fns...)
+	return rcgb
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (rcgb *ResponseCacheGroupBy) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, rcgb.build.ctx, "GroupBy")
+	if err := rcgb.build.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*ResponseCacheQuery, *ResponseCacheGroupBy](ctx, rcgb.build, rcgb, rcgb.build.inters, v)
+}
+
+func (rcgb *ResponseCacheGroupBy) sqlScan(ctx context.Context, root *ResponseCacheQuery, v any) error {
+	selector := root.sqlQuery(ctx).Select()
+	aggregation := make([]string, 0, len(rcgb.fns))
+	for _, fn := range rcgb.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	if len(selector.SelectedColumns()) == 0 {
+		columns := make([]string, 0, len(*rcgb.flds)+len(rcgb.fns))
+		for _, f := range *rcgb.flds {
+			columns = append(columns, selector.C(f))
+		}
+		columns = append(columns, aggregation...)
+		selector.Select(columns...)
+	}
+	selector.GroupBy(selector.Columns(*rcgb.flds...)...)
+	if err := selector.Err(); err != nil {
+		return err
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := rcgb.build.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// ResponseCacheSelect is the builder for selecting fields of ResponseCache entities.
+type ResponseCacheSelect struct {
+	*ResponseCacheQuery
+	selector
+}
+
+// Aggregate adds the given aggregation functions to the selector query.
+func (rcs *ResponseCacheSelect) Aggregate(fns ...AggregateFunc) *ResponseCacheSelect {
+	rcs.fns = append(rcs.fns, fns...)
+	return rcs
+}
+
+// Scan applies the selector query and scans the result into the given value.
+func (rcs *ResponseCacheSelect) Scan(ctx context.Context, v any) error {
+	ctx = setContextOp(ctx, rcs.ctx, "Select")
+	if err := rcs.prepareQuery(ctx); err != nil {
+		return err
+	}
+	return scanWithInterceptors[*ResponseCacheQuery, *ResponseCacheSelect](ctx, rcs.ResponseCacheQuery, rcs, rcs.inters, v)
+}
+
+func (rcs *ResponseCacheSelect) sqlScan(ctx context.Context, root *ResponseCacheQuery, v any) error {
+	selector := root.sqlQuery(ctx)
+	aggregation := make([]string, 0, len(rcs.fns))
+	for _, fn := range rcs.fns {
+		aggregation = append(aggregation, fn(selector))
+	}
+	switch n := len(*rcs.selector.flds); {
+	case n == 0 && len(aggregation) > 0:
+		selector.Select(aggregation...)
+	case n != 0 && len(aggregation) > 0:
+		selector.AppendSelect(aggregation...)
+	}
+	rows := &sql.Rows{}
+	query, args := selector.Query()
+	if err := rcs.driver.Query(ctx, query, args, rows); err != nil {
+		return err
+	}
+	defer rows.Close()
+	return sql.ScanSlice(rows, v)
+}
+
+// Modify adds a query modifier for attaching custom logic to queries.
+func (rcs *ResponseCacheSelect) Modify(modifiers ...func(s *sql.Selector)) *ResponseCacheSelect {
+	rcs.modifiers = append(rcs.modifiers, modifiers...)
+	return rcs
+}
@@ -0,0 +1,310 @@
+// Code generated by ent, DO NOT EDIT.
+
+package chatent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+)
+
+// ResponseCacheUpdate is the builder for updating ResponseCache entities.
+type ResponseCacheUpdate struct {
+	config
+	hooks     []Hook
+	mutation  *ResponseCacheMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// Where appends a list predicates to the ResponseCacheUpdate builder.
+func (rcu *ResponseCacheUpdate) Where(ps ...predicate.ResponseCache) *ResponseCacheUpdate {
+	rcu.mutation.Where(ps...)
+	return rcu
+}
+
+// SetKey sets the "key" field.
+func (rcu *ResponseCacheUpdate) SetKey(s string) *ResponseCacheUpdate {
+	rcu.mutation.SetKey(s)
+	return rcu
+}
+
+// SetValue sets the "value" field.
+func (rcu *ResponseCacheUpdate) SetValue(s string) *ResponseCacheUpdate {
+	rcu.mutation.SetValue(s)
+	return rcu
+}
+
+// SetUserID sets the "user_id" field.
+func (rcu *ResponseCacheUpdate) SetUserID(s string) *ResponseCacheUpdate {
+	rcu.mutation.SetUserID(s)
+	return rcu
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (rcu *ResponseCacheUpdate) SetNillableUserID(s *string) *ResponseCacheUpdate {
+	if s != nil {
+		rcu.SetUserID(*s)
+	}
+	return rcu
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rcu *ResponseCacheUpdate) SetUpdatedAt(t time.Time) *ResponseCacheUpdate {
+	rcu.mutation.SetUpdatedAt(t)
+	return rcu
+}
+
+// Mutation returns the ResponseCacheMutation object of the builder.
+func (rcu *ResponseCacheUpdate) Mutation() *ResponseCacheMutation {
+	return rcu.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (rcu *ResponseCacheUpdate) Save(ctx context.Context) (int, error) {
+	rcu.defaults()
+	return withHooks[int, ResponseCacheMutation](ctx, rcu.sqlSave, rcu.mutation, rcu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rcu *ResponseCacheUpdate) SaveX(ctx context.Context) int {
+	affected, err := rcu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (rcu *ResponseCacheUpdate) Exec(ctx context.Context) error {
+	_, err := rcu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcu *ResponseCacheUpdate) ExecX(ctx context.Context) {
+	if err := rcu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (rcu *ResponseCacheUpdate) defaults() {
+	if _, ok := rcu.mutation.UpdatedAt(); !ok {
+		v := responsecache.UpdateDefaultUpdatedAt()
+		rcu.mutation.SetUpdatedAt(v)
+	}
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (rcu *ResponseCacheUpdate) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ResponseCacheUpdate {
+	rcu.modifiers = append(rcu.modifiers, modifiers...)
+	return rcu
+}
+
+func (rcu *ResponseCacheUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   responsecache.Table,
+			Columns: responsecache.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: responsecache.FieldID,
+			},
+		},
+	}
+	if ps := rcu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rcu.mutation.Key(); ok {
+		_spec.SetField(responsecache.FieldKey, field.TypeString, value)
+	}
+	if value, ok := rcu.mutation.Value(); ok {
+		_spec.SetField(responsecache.FieldValue, field.TypeString, value)
+	}
+	if value, ok := rcu.mutation.UserID(); ok {
+		_spec.SetField(responsecache.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := rcu.mutation.UpdatedAt(); ok {
+		_spec.SetField(responsecache.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_spec.AddModifiers(rcu.modifiers...)
+	if n, err = sqlgraph.UpdateNodes(ctx, rcu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{responsecache.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	rcu.mutation.done = true
+	return n, nil
+}
+
+// ResponseCacheUpdateOne is the builder for updating a single ResponseCache entity.
+type ResponseCacheUpdateOne struct {
+	config
+	fields    []string
+	hooks     []Hook
+	mutation  *ResponseCacheMutation
+	modifiers []func(*sql.UpdateBuilder)
+}
+
+// SetKey sets the "key" field.
+func (rcuo *ResponseCacheUpdateOne) SetKey(s string) *ResponseCacheUpdateOne {
+	rcuo.mutation.SetKey(s)
+	return rcuo
+}
+
+// SetValue sets the "value" field.
+func (rcuo *ResponseCacheUpdateOne) SetValue(s string) *ResponseCacheUpdateOne {
+	rcuo.mutation.SetValue(s)
+	return rcuo
+}
+
+// SetUserID sets the "user_id" field.
+func (rcuo *ResponseCacheUpdateOne) SetUserID(s string) *ResponseCacheUpdateOne {
+	rcuo.mutation.SetUserID(s)
+	return rcuo
+}
+
+// SetNillableUserID sets the "user_id" field if the given value is not nil.
+func (rcuo *ResponseCacheUpdateOne) SetNillableUserID(s *string) *ResponseCacheUpdateOne {
+	if s != nil {
+		rcuo.SetUserID(*s)
+	}
+	return rcuo
+}
+
+// SetUpdatedAt sets the "updated_at" field.
+func (rcuo *ResponseCacheUpdateOne) SetUpdatedAt(t time.Time) *ResponseCacheUpdateOne {
+	rcuo.mutation.SetUpdatedAt(t)
+	return rcuo
+}
+
+// Mutation returns the ResponseCacheMutation object of the builder.
+func (rcuo *ResponseCacheUpdateOne) Mutation() *ResponseCacheMutation {
+	return rcuo.mutation
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (rcuo *ResponseCacheUpdateOne) Select(field string, fields ...string) *ResponseCacheUpdateOne {
+	rcuo.fields = append([]string{field}, fields...)
+	return rcuo
+}
+
+// Save executes the query and returns the updated ResponseCache entity.
+func (rcuo *ResponseCacheUpdateOne) Save(ctx context.Context) (*ResponseCache, error) {
+	rcuo.defaults()
+	return withHooks[*ResponseCache, ResponseCacheMutation](ctx, rcuo.sqlSave, rcuo.mutation, rcuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (rcuo *ResponseCacheUpdateOne) SaveX(ctx context.Context) *ResponseCache {
+	node, err := rcuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (rcuo *ResponseCacheUpdateOne) Exec(ctx context.Context) error {
+	_, err := rcuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (rcuo *ResponseCacheUpdateOne) ExecX(ctx context.Context) {
+	if err := rcuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (rcuo *ResponseCacheUpdateOne) defaults() {
+	if _, ok := rcuo.mutation.UpdatedAt(); !ok {
+		v := responsecache.UpdateDefaultUpdatedAt()
+		rcuo.mutation.SetUpdatedAt(v)
+	}
+}
+
+// Modify adds a statement modifier for attaching custom logic to the UPDATE statement.
+func (rcuo *ResponseCacheUpdateOne) Modify(modifiers ...func(u *sql.UpdateBuilder)) *ResponseCacheUpdateOne {
+	rcuo.modifiers = append(rcuo.modifiers, modifiers...)
+	return rcuo
+}
+
+func (rcuo *ResponseCacheUpdateOne) sqlSave(ctx context.Context) (_node *ResponseCache, err error) {
+	_spec := &sqlgraph.UpdateSpec{
+		Node: &sqlgraph.NodeSpec{
+			Table:   responsecache.Table,
+			Columns: responsecache.Columns,
+			ID: &sqlgraph.FieldSpec{
+				Type:   field.TypeInt,
+				Column: responsecache.FieldID,
+			},
+		},
+	}
+	id, ok := rcuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`chatent: missing "ResponseCache.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := rcuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, responsecache.FieldID)
+		for _, f := range fields {
+			if !responsecache.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("chatent: invalid field %q for query", f)}
+			}
+			if f != responsecache.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := rcuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := rcuo.mutation.Key(); ok {
+		_spec.SetField(responsecache.FieldKey, field.TypeString, value)
+	}
+	if value, ok := rcuo.mutation.Value(); ok {
+		_spec.SetField(responsecache.FieldValue, field.TypeString, value)
+	}
+	if value, ok := rcuo.mutation.UserID(); ok {
+		_spec.SetField(responsecache.FieldUserID, field.TypeString, value)
+	}
+	if value, ok := rcuo.mutation.UpdatedAt(); ok {
+		_spec.SetField(responsecache.FieldUpdatedAt, field.TypeTime, value)
+	}
+	_spec.AddModifiers(rcuo.modifiers...)
+	_node = &ResponseCache{config: rcuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, rcuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{responsecache.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	rcuo.mutation.done = true
+	return _node, nil
+}
@@ -5,7 +5,10 @@ package chatent
 import (
 	"time"
 
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
 	"github.com/fanchunke/xgpt3/conversation/ent/schema"
 )
@@ -14,30 +17,82 @@ import (
 // (default values, validators, hooks and policies) and stitches it
 // to their package variables.
 func init() {
+	datakeyFields := schema.DataKey{}.Fields()
+	_ = datakeyFields
+	// datakeyDescCreatedAt is the schema descriptor for created_at field.
+	datakeyDescCreatedAt := datakeyFields[3].Descriptor()
+	// datakey.DefaultCreatedAt holds the default value on creation for the created_at field.
+	datakey.DefaultCreatedAt = datakeyDescCreatedAt.Default.(func() time.Time)
+	embeddingFields := schema.Embedding{}.Fields()
+	_ = embeddingFields
+	// embeddingDescUserID is the schema descriptor for user_id field.
+	embeddingDescUserID := embeddingFields[1].Descriptor()
+	// embedding.DefaultUserID holds the default value on creation for the user_id field.
+	embedding.DefaultUserID = embeddingDescUserID.Default.(string)
+	// embeddingDescSessionID is the schema descriptor for session_id field.
+	embeddingDescSessionID := embeddingFields[2].Descriptor()
+	// embedding.DefaultSessionID holds the default value on creation for the session_id field.
+	embedding.DefaultSessionID = embeddingDescSessionID.Default.(int)
+	// embeddingDescMessageID is the schema descriptor for message_id field.
+	embeddingDescMessageID := embeddingFields[3].Descriptor()
+	// embedding.DefaultMessageID holds the default value on creation for the message_id field.
+	embedding.DefaultMessageID = embeddingDescMessageID.Default.(int)
+	// embeddingDescCreatedAt is the schema descriptor for created_at field.
+	embeddingDescCreatedAt := embeddingFields[7].Descriptor()
+	// embedding.DefaultCreatedAt holds the default value on creation for the created_at field.
+	embedding.DefaultCreatedAt = embeddingDescCreatedAt.Default.(func() time.Time)
 	messageFields := schema.Message{}.Fields()
 	_ = messageFields
 	// messageDescCreatedAt is the schema descriptor for created_at field.
-	messageDescCreatedAt := messageFields[5].Descriptor()
+	messageDescCreatedAt := messageFields[8].Descriptor()
 	// message.DefaultCreatedAt holds the default value on creation for the created_at field.
 	message.DefaultCreatedAt = messageDescCreatedAt.Default.(func() time.Time)
+	responsecacheFields := schema.ResponseCache{}.Fields()
+	_ = responsecacheFields
+	// responsecacheDescUserID is the schema descriptor for user_id field.
+	responsecacheDescUserID := responsecacheFields[2].Descriptor()
+	// responsecache.DefaultUserID holds the default value on creation for the user_id field.
+	responsecache.DefaultUserID = responsecacheDescUserID.Default.(string)
+	// responsecacheDescCreatedAt is the schema descriptor for created_at field.
+	responsecacheDescCreatedAt := responsecacheFields[3].Descriptor()
+	// responsecache.DefaultCreatedAt holds the default value on creation for the created_at field.
+	responsecache.DefaultCreatedAt = responsecacheDescCreatedAt.Default.(func() time.Time)
+	// responsecacheDescUpdatedAt is the schema descriptor for updated_at field.
+	responsecacheDescUpdatedAt := responsecacheFields[4].Descriptor()
+	// responsecache.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	responsecache.DefaultUpdatedAt = responsecacheDescUpdatedAt.Default.(func() time.Time)
+	// responsecache.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	responsecache.UpdateDefaultUpdatedAt = responsecacheDescUpdatedAt.UpdateDefault.(func() time.Time)
 	sessionFields := schema.Session{}.Fields()
 	_ = sessionFields
+	// sessionDescChannel is the schema descriptor for channel field.
+	sessionDescChannel := sessionFields[1].Descriptor()
+	// session.DefaultChannel holds the default value on creation for the channel field.
+	session.DefaultChannel = sessionDescChannel.Default.(string)
 	// sessionDescStatus is the schema descriptor for status field.
-	sessionDescStatus := sessionFields[1].Descriptor()
+	sessionDescStatus := sessionFields[2].Descriptor()
 	// session.DefaultStatus holds the default value on creation for the status field.
 	session.DefaultStatus = sessionDescStatus.Default.(bool)
+	// sessionDescTitle is the schema descriptor for title field.
+	sessionDescTitle := sessionFields[3].Descriptor()
+	// session.DefaultTitle holds the default value on creation for the title field.
+	session.DefaultTitle = sessionDescTitle.Default.(string)
+	// sessionDescGroupChat is the schema descriptor for group_chat field.
+	sessionDescGroupChat := sessionFields[4].Descriptor()
+	// session.DefaultGroupChat holds the default value on creation for the group_chat field.
+	session.DefaultGroupChat = sessionDescGroupChat.Default.(bool)
 	// sessionDescCreatedAt is the schema descriptor for created_at field.
-	sessionDescCreatedAt := sessionFields[2].Descriptor()
+	sessionDescCreatedAt := sessionFields[6].Descriptor()
 	// session.DefaultCreatedAt holds the default value on creation for the created_at field.
 	session.DefaultCreatedAt = sessionDescCreatedAt.Default.(func() time.Time)
 	// sessionDescUpdatedAt is the schema descriptor for updated_at field.
-	sessionDescUpdatedAt := sessionFields[3].Descriptor()
+	sessionDescUpdatedAt := sessionFields[7].Descriptor()
 	// session.DefaultUpdatedAt holds the default value on creation for the updated_at field.
 	session.DefaultUpdatedAt = sessionDescUpdatedAt.Default.(func() time.Time)
 	// session.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	session.UpdateDefaultUpdatedAt = sessionDescUpdatedAt.UpdateDefault.(func() time.Time)
 	// sessionDescDeletedAt is the schema descriptor for deleted_at field.
-	sessionDescDeletedAt := sessionFields[4].Descriptor()
+	sessionDescDeletedAt := sessionFields[8].Descriptor()
 	// session.DefaultDeletedAt holds the default value on creation for the deleted_at field.
 	session.DefaultDeletedAt = sessionDescDeletedAt.Default.(int)
 }
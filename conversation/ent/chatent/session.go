@@ -3,6 +3,7 @@
 package chatent
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -18,8 +19,16 @@ type Session struct {
 	ID int `json:"id,omitempty"`
 	// 用户Id
 	UserID string `json:"user_id,omitempty"`
+	// 消息渠道
+	Channel string `json:"channel,omitempty"`
 	// 会话是否开启
 	Status bool `json:"status,omitempty"`
+	// 会话标题
+	Title string `json:"title,omitempty"`
+	// 是否为群聊会话，群聊会话的用户Id为群Id
+	GroupChat bool `json:"group_chat,omitempty"`
+	// 群聊会话的参与者
+	Participants []string `json:"participants,omitempty"`
 	// CreatedAt holds the value of the "created_at" field.
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	// UpdatedAt holds the value of the "updated_at" field.
@@ -54,11 +63,13 @@ func (*Session) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case session.FieldStatus:
+		case session.FieldParticipants:
+			values[i] = new([]byte)
+		case session.FieldStatus, session.FieldGroupChat:
 			values[i] = new(sql.NullBool)
 		case session.FieldID, session.FieldDeletedAt:
 			values[i] = new(sql.NullInt64)
-		case session.FieldUserID:
+		case session.FieldUserID, session.FieldChannel, session.FieldTitle:
 			values[i] = new(sql.NullString)
 		case session.FieldCreatedAt, session.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
@@ -89,12 +100,38 @@ func (s *Session) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				s.UserID = value.String
 			}
+		case session.FieldChannel:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field channel", values[i])
+			} else if value.Valid {
+				s.Channel = value.String
+			}
 		case session.FieldStatus:
 			if value, ok := values[i].(*sql.NullBool); !ok {
 				return fmt.Errorf("unexpected type %T for field status", values[i])
 			} else if value.Valid {
 				s.Status = value.Bool
 			}
+		case session.FieldTitle:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field title", values[i])
+			} else if value.Valid {
+				s.Title = value.String
+			}
+		case session.FieldGroupChat:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field group_chat", values[i])
+			} else if value.Valid {
+				s.GroupChat = value.Bool
+			}
+		case session.FieldParticipants:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field participants", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &s.Participants); err != nil {
+					return fmt.Errorf("unmarshal field participants: %w", err)
+				}
+			}
 		case session.FieldCreatedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field created_at", values[i])
@@ -149,9 +186,21 @@ func (s *Session) String() string {
 	builder.WriteString("user_id=")
 	builder.WriteString(s.UserID)
 	builder.WriteString(", ")
+	builder.WriteString("channel=")
+	builder.WriteString(s.Channel)
+	builder.WriteString(", ")
 	builder.WriteString("status=")
 	builder.WriteString(fmt.Sprintf("%v", s.Status))
 	builder.WriteString(", ")
+	builder.WriteString("title=")
+	builder.WriteString(s.Title)
+	builder.WriteString(", ")
+	builder.WriteString("group_chat=")
+	builder.WriteString(fmt.Sprintf("%v", s.GroupChat))
+	builder.WriteString(", ")
+	builder.WriteString("participants=")
+	builder.WriteString(fmt.Sprintf("%v", s.Participants))
+	builder.WriteString(", ")
 	builder.WriteString("created_at=")
 	builder.WriteString(s.CreatedAt.Format(time.ANSIC))
 	builder.WriteString(", ")
@@ -13,8 +13,16 @@ const (
 	FieldID = "id"
 	// FieldUserID holds the string denoting the user_id field in the database.
 	FieldUserID = "user_id"
+	// FieldChannel holds the string denoting the channel field in the database.
+	FieldChannel = "channel"
 	// FieldStatus holds the string denoting the status field in the database.
 	FieldStatus = "status"
+	// FieldTitle holds the string denoting the title field in the database.
+	FieldTitle = "title"
+	// FieldGroupChat holds the string denoting the group_chat field in the database.
+	FieldGroupChat = "group_chat"
+	// FieldParticipants holds the string denoting the participants field in the database.
+	FieldParticipants = "participants"
 	// FieldCreatedAt holds the string denoting the created_at field in the database.
 	FieldCreatedAt = "created_at"
 	// FieldUpdatedAt holds the string denoting the updated_at field in the database.
@@ -38,7 +46,11 @@ const (
 var Columns = []string{
 	FieldID,
 	FieldUserID,
+	FieldChannel,
 	FieldStatus,
+	FieldTitle,
+	FieldGroupChat,
+	FieldParticipants,
 	FieldCreatedAt,
 	FieldUpdatedAt,
 	FieldDeletedAt,
@@ -55,8 +67,14 @@ func ValidColumn(column string) bool {
 }
 
 var (
+	// DefaultChannel holds the default value on creation for the "channel" field.
+	DefaultChannel string
 	// DefaultStatus holds the default value on creation for the "status" field.
 	DefaultStatus bool
+	// DefaultTitle holds the default value on creation for the "title" field.
+	DefaultTitle string
+	// DefaultGroupChat holds the default value on creation for the "group_chat" field.
+	DefaultGroupChat bool
 	// DefaultCreatedAt holds the default value on creation for the "created_at" field.
 	DefaultCreatedAt func() time.Time
 	// DefaultUpdatedAt holds the default value on creation for the "updated_at" field.
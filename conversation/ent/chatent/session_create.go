@@ -29,6 +29,20 @@ func (sc *SessionCreate) SetUserID(s string) *SessionCreate {
 	return sc
 }
 
+// SetChannel sets the "channel" field.
+func (sc *SessionCreate) SetChannel(s string) *SessionCreate {
+	sc.mutation.SetChannel(s)
+	return sc
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableChannel(s *string) *SessionCreate {
+	if s != nil {
+		sc.SetChannel(*s)
+	}
+	return sc
+}
+
 // SetStatus sets the "status" field.
 func (sc *SessionCreate) SetStatus(b bool) *SessionCreate {
 	sc.mutation.SetStatus(b)
@@ -43,6 +57,40 @@ func (sc *SessionCreate) SetNillableStatus(b *bool) *SessionCreate {
 	return sc
 }
 
+// SetTitle sets the "title" field.
+func (sc *SessionCreate) SetTitle(s string) *SessionCreate {
+	sc.mutation.SetTitle(s)
+	return sc
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableTitle(s *string) *SessionCreate {
+	if s != nil {
+		sc.SetTitle(*s)
+	}
+	return sc
+}
+
+// SetGroupChat sets the "group_chat" field.
+func (sc *SessionCreate) SetGroupChat(b bool) *SessionCreate {
+	sc.mutation.SetGroupChat(b)
+	return sc
+}
+
+// SetNillableGroupChat sets the "group_chat" field if the given value is not nil.
+func (sc *SessionCreate) SetNillableGroupChat(b *bool) *SessionCreate {
+	if b != nil {
+		sc.SetGroupChat(*b)
+	}
+	return sc
+}
+
+// SetParticipants sets the "participants" field.
+func (sc *SessionCreate) SetParticipants(s []string) *SessionCreate {
+	sc.mutation.SetParticipants(s)
+	return sc
+}
+
 // SetCreatedAt sets the "created_at" field.
 func (sc *SessionCreate) SetCreatedAt(t time.Time) *SessionCreate {
 	sc.mutation.SetCreatedAt(t)
@@ -135,10 +183,22 @@ func (sc *SessionCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (sc *SessionCreate) defaults() {
+	if _, ok := sc.mutation.Channel(); !ok {
+		v := session.DefaultChannel
+		sc.mutation.SetChannel(v)
+	}
 	if _, ok := sc.mutation.Status(); !ok {
 		v := session.DefaultStatus
 		sc.mutation.SetStatus(v)
 	}
+	if _, ok := sc.mutation.Title(); !ok {
+		v := session.DefaultTitle
+		sc.mutation.SetTitle(v)
+	}
+	if _, ok := sc.mutation.GroupChat(); !ok {
+		v := session.DefaultGroupChat
+		sc.mutation.SetGroupChat(v)
+	}
 	if _, ok := sc.mutation.CreatedAt(); !ok {
 		v := session.DefaultCreatedAt()
 		sc.mutation.SetCreatedAt(v)
@@ -158,9 +218,18 @@ func (sc *SessionCreate) check() error {
 	if _, ok := sc.mutation.UserID(); !ok {
 		return &ValidationError{Name: "user_id", err: errors.New(`chatent: missing required field "Session.user_id"`)}
 	}
+	if _, ok := sc.mutation.Channel(); !ok {
+		return &ValidationError{Name: "channel", err: errors.New(`chatent: missing required field "Session.channel"`)}
+	}
 	if _, ok := sc.mutation.Status(); !ok {
 		return &ValidationError{Name: "status", err: errors.New(`chatent: missing required field "Session.status"`)}
 	}
+	if _, ok := sc.mutation.Title(); !ok {
+		return &ValidationError{Name: "title", err: errors.New(`chatent: missing required field "Session.title"`)}
+	}
+	if _, ok := sc.mutation.GroupChat(); !ok {
+		return &ValidationError{Name: "group_chat", err: errors.New(`chatent: missing required field "Session.group_chat"`)}
+	}
 	if _, ok := sc.mutation.CreatedAt(); !ok {
 		return &ValidationError{Name: "created_at", err: errors.New(`chatent: missing required field "Session.created_at"`)}
 	}
@@ -207,10 +276,26 @@ func (sc *SessionCreate) createSpec() (*Session, *sqlgraph.CreateSpec) {
 		_spec.SetField(session.FieldUserID, field.TypeString, value)
 		_node.UserID = value
 	}
+	if value, ok := sc.mutation.Channel(); ok {
+		_spec.SetField(session.FieldChannel, field.TypeString, value)
+		_node.Channel = value
+	}
 	if value, ok := sc.mutation.Status(); ok {
 		_spec.SetField(session.FieldStatus, field.TypeBool, value)
 		_node.Status = value
 	}
+	if value, ok := sc.mutation.Title(); ok {
+		_spec.SetField(session.FieldTitle, field.TypeString, value)
+		_node.Title = value
+	}
+	if value, ok := sc.mutation.GroupChat(); ok {
+		_spec.SetField(session.FieldGroupChat, field.TypeBool, value)
+		_node.GroupChat = value
+	}
+	if value, ok := sc.mutation.Participants(); ok {
+		_spec.SetField(session.FieldParticipants, field.TypeJSON, value)
+		_node.Participants = value
+	}
 	if value, ok := sc.mutation.CreatedAt(); ok {
 		_spec.SetField(session.FieldCreatedAt, field.TypeTime, value)
 		_node.CreatedAt = value
@@ -261,7 +346,6 @@ func (sc *SessionCreate) createSpec() (*Session, *sqlgraph.CreateSpec) {
 //			SetUserID(v+v).
 //		}).
 //		Exec(ctx)
-//
 func (sc *SessionCreate) OnConflict(opts ...sql.ConflictOption) *SessionUpsertOne {
 	sc.conflict = opts
 	return &SessionUpsertOne{
@@ -275,7 +359,6 @@ func (sc *SessionCreate) OnConflict(opts ...sql.ConflictOption) *SessionUpsertOn
 //	client.Session.Create().
 //		OnConflict(sql.ConflictColumns(columns...)).
 //		Exec(ctx)
-//
 func (sc *SessionCreate) OnConflictColumns(columns ...string) *SessionUpsertOne {
 	sc.conflict = append(sc.conflict, sql.ConflictColumns(columns...))
 	return &SessionUpsertOne{
@@ -308,6 +391,18 @@ func (u *SessionUpsert) UpdateUserID() *SessionUpsert {
 	return u
 }
 
+// SetChannel sets the "channel" field.
+func (u *SessionUpsert) SetChannel(v string) *SessionUpsert {
+	u.Set(session.FieldChannel, v)
+	return u
+}
+
+// UpdateChannel sets the "channel" field to the value that was provided on create.
+func (u *SessionUpsert) UpdateChannel() *SessionUpsert {
+	u.SetExcluded(session.FieldChannel)
+	return u
+}
+
 // SetStatus sets the "status" field.
 func (u *SessionUpsert) SetStatus(v bool) *SessionUpsert {
 	u.Set(session.FieldStatus, v)
@@ -320,6 +415,48 @@ func (u *SessionUpsert) UpdateStatus() *SessionUpsert {
 	return u
 }
 
+// SetTitle sets the "title" field.
+func (u *SessionUpsert) SetTitle(v string) *SessionUpsert {
+	u.Set(session.FieldTitle, v)
+	return u
+}
+
+// UpdateTitle sets the "title" field to the value that was provided on create.
+func (u *SessionUpsert) UpdateTitle() *SessionUpsert {
+	u.SetExcluded(session.FieldTitle)
+	return u
+}
+
+// SetGroupChat sets the "group_chat" field.
+func (u *SessionUpsert) SetGroupChat(v bool) *SessionUpsert {
+	u.Set(session.FieldGroupChat, v)
+	return u
+}
+
+// UpdateGroupChat sets the "group_chat" field to the value that was provided on create.
+func (u *SessionUpsert) UpdateGroupChat() *SessionUpsert {
+	u.SetExcluded(session.FieldGroupChat)
+	return u
+}
+
+// SetParticipants sets the "participants" field.
+func (u *SessionUpsert) SetParticipants(v []string) *SessionUpsert {
+	u.Set(session.FieldParticipants, v)
+	return u
+}
+
+// UpdateParticipants sets the "participants" field to the value that was provided on create.
+func (u *SessionUpsert) UpdateParticipants() *SessionUpsert {
+	u.SetExcluded(session.FieldParticipants)
+	return u
+}
+
+// ClearParticipants clears the value of the "participants" field.
+func (u *SessionUpsert) ClearParticipants() *SessionUpsert {
+	u.SetNull(session.FieldParticipants)
+	return u
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (u *SessionUpsert) SetUpdatedAt(v time.Time) *SessionUpsert {
 	u.Set(session.FieldUpdatedAt, v)
@@ -358,7 +495,6 @@ func (u *SessionUpsert) AddDeletedAt(v int) *SessionUpsert {
 //			sql.ResolveWithNewValues(),
 //		).
 //		Exec(ctx)
-//
 func (u *SessionUpsertOne) UpdateNewValues() *SessionUpsertOne {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
 	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
@@ -372,10 +508,9 @@ func (u *SessionUpsertOne) UpdateNewValues() *SessionUpsertOne {
 // Ignore sets each column to itself in case of conflict.
 // Using this option is equivalent to using:
 //
-//  client.Session.Create().
-//      OnConflict(sql.ResolveWithIgnore()).
-//      Exec(ctx)
-//
+//	client.Session.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
 func (u *SessionUpsertOne) Ignore() *SessionUpsertOne {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
 	return u
@@ -411,6 +546,20 @@ func (u *SessionUpsertOne) UpdateUserID() *SessionUpsertOne {
 	})
 }
 
+// SetChannel sets the "channel" field.
+func (u *SessionUpsertOne) SetChannel(v string) *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetChannel(v)
+	})
+}
+
+// UpdateChannel sets the "channel" field to the value that was provided on create.
+func (u *SessionUpsertOne) UpdateChannel() *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateChannel()
+	})
+}
+
 // SetStatus sets the "status" field.
 func (u *SessionUpsertOne) SetStatus(v bool) *SessionUpsertOne {
 	return u.Update(func(s *SessionUpsert) {
@@ -425,6 +574,55 @@ func (u *SessionUpsertOne) UpdateStatus() *SessionUpsertOne {
 	})
 }
 
+// SetTitle sets the "title" field.
+func (u *SessionUpsertOne) SetTitle(v string) *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetTitle(v)
+	})
+}
+
+// UpdateTitle sets the "title" field to the value that was provided on create.
+func (u *SessionUpsertOne) UpdateTitle() *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateTitle()
+	})
+}
+
+// SetGroupChat sets the "group_chat" field.
+func (u *SessionUpsertOne) SetGroupChat(v bool) *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetGroupChat(v)
+	})
+}
+
+// UpdateGroupChat sets the "group_chat" field to the value that was provided on create.
+func (u *SessionUpsertOne) UpdateGroupChat() *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateGroupChat()
+	})
+}
+
+// SetParticipants sets the "participants" field.
+func (u *SessionUpsertOne) SetParticipants(v []string) *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetParticipants(v)
+	})
+}
+
+// UpdateParticipants sets the "participants" field to the value that was provided on create.
+func (u *SessionUpsertOne) UpdateParticipants() *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateParticipants()
+	})
+}
+
+// ClearParticipants clears the value of the "participants" field.
+func (u *SessionUpsertOne) ClearParticipants() *SessionUpsertOne {
+	return u.Update(func(s *SessionUpsert) {
+		s.ClearParticipants()
+	})
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (u *SessionUpsertOne) SetUpdatedAt(v time.Time) *SessionUpsertOne {
 	return u.Update(func(s *SessionUpsert) {
@@ -594,7 +792,6 @@ func (scb *SessionCreateBulk) ExecX(ctx context.Context) {
 //			SetUserID(v+v).
 //		}).
 //		Exec(ctx)
-//
 func (scb *SessionCreateBulk) OnConflict(opts ...sql.ConflictOption) *SessionUpsertBulk {
 	scb.conflict = opts
 	return &SessionUpsertBulk{
@@ -608,7 +805,6 @@ func (scb *SessionCreateBulk) OnConflict(opts ...sql.ConflictOption) *SessionUps
 //	client.Session.Create().
 //		OnConflict(sql.ConflictColumns(columns...)).
 //		Exec(ctx)
-//
 func (scb *SessionCreateBulk) OnConflictColumns(columns ...string) *SessionUpsertBulk {
 	scb.conflict = append(scb.conflict, sql.ConflictColumns(columns...))
 	return &SessionUpsertBulk{
@@ -630,7 +826,6 @@ type SessionUpsertBulk struct {
 //			sql.ResolveWithNewValues(),
 //		).
 //		Exec(ctx)
-//
 func (u *SessionUpsertBulk) UpdateNewValues() *SessionUpsertBulk {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
 	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
@@ -649,7 +844,6 @@ func (u *SessionUpsertBulk) UpdateNewValues() *SessionUpsertBulk {
 //	client.Session.Create().
 //		OnConflict(sql.ResolveWithIgnore()).
 //		Exec(ctx)
-//
 func (u *SessionUpsertBulk) Ignore() *SessionUpsertBulk {
 	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
 	return u
@@ -685,6 +879,20 @@ func (u *SessionUpsertBulk) UpdateUserID() *SessionUpsertBulk {
 	})
 }
 
+// SetChannel sets the "channel" field.
+func (u *SessionUpsertBulk) SetChannel(v string) *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetChannel(v)
+	})
+}
+
+// UpdateChannel sets the "channel" field to the value that was provided on create.
+func (u *SessionUpsertBulk) UpdateChannel() *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateChannel()
+	})
+}
+
 // SetStatus sets the "status" field.
 func (u *SessionUpsertBulk) SetStatus(v bool) *SessionUpsertBulk {
 	return u.Update(func(s *SessionUpsert) {
@@ -699,6 +907,55 @@ func (u *SessionUpsertBulk) UpdateStatus() *SessionUpsertBulk {
 	})
 }
 
+// SetTitle sets the "title" field.
+func (u *SessionUpsertBulk) SetTitle(v string) *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetTitle(v)
+	})
+}
+
+// UpdateTitle sets the "title" field to the value that was provided on create.
+func (u *SessionUpsertBulk) UpdateTitle() *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateTitle()
+	})
+}
+
+// SetGroupChat sets the "group_chat" field.
+func (u *SessionUpsertBulk) SetGroupChat(v bool) *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetGroupChat(v)
+	})
+}
+
+// UpdateGroupChat sets the "group_chat" field to the value that was provided on create.
+func (u *SessionUpsertBulk) UpdateGroupChat() *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateGroupChat()
+	})
+}
+
+// SetParticipants sets the "participants" field.
+func (u *SessionUpsertBulk) SetParticipants(v []string) *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.SetParticipants(v)
+	})
+}
+
+// UpdateParticipants sets the "participants" field to the value that was provided on create.
+func (u *SessionUpsertBulk) UpdateParticipants() *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.UpdateParticipants()
+	})
+}
+
+// ClearParticipants clears the value of the "participants" field.
+func (u *SessionUpsertBulk) ClearParticipants() *SessionUpsertBulk {
+	return u.Update(func(s *SessionUpsert) {
+		s.ClearParticipants()
+	})
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (u *SessionUpsertBulk) SetUpdatedAt(v time.Time) *SessionUpsertBulk {
 	return u.Update(func(s *SessionUpsert) {
@@ -306,7 +306,6 @@ func (sq *SessionQuery) WithMessages(opts ...func(*MessageQuery)) *SessionQuery
 //		GroupBy(session.FieldUserID).
 //		Aggregate(chatent.Count()).
 //		Scan(ctx, &v)
-//
 func (sq *SessionQuery) GroupBy(field string, fields ...string) *SessionGroupBy {
 	sq.ctx.Fields = append([]string{field}, fields...)
 	grbuild := &SessionGroupBy{build: sq}
@@ -328,7 +327,6 @@ func (sq *SessionQuery) GroupBy(field string, fields ...string) *SessionGroupBy
 //	client.Session.Query().
 //		Select(session.FieldUserID).
 //		Scan(ctx, &v)
-//
 func (sq *SessionQuery) Select(fields ...string) *SessionSelect {
 	sq.ctx.Fields = append(sq.ctx.Fields, fields...)
 	sbuild := &SessionSelect{SessionQuery: sq}
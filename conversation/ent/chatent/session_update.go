@@ -10,6 +10,7 @@ import (
 
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
 	"entgo.io/ent/schema/field"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
 	"github.com/fanchunke/xgpt3/conversation/ent/chatent/predicate"
@@ -36,6 +37,20 @@ func (su *SessionUpdate) SetUserID(s string) *SessionUpdate {
 	return su
 }
 
+// SetChannel sets the "channel" field.
+func (su *SessionUpdate) SetChannel(s string) *SessionUpdate {
+	su.mutation.SetChannel(s)
+	return su
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableChannel(s *string) *SessionUpdate {
+	if s != nil {
+		su.SetChannel(*s)
+	}
+	return su
+}
+
 // SetStatus sets the "status" field.
 func (su *SessionUpdate) SetStatus(b bool) *SessionUpdate {
 	su.mutation.SetStatus(b)
@@ -50,6 +65,52 @@ func (su *SessionUpdate) SetNillableStatus(b *bool) *SessionUpdate {
 	return su
 }
 
+// SetTitle sets the "title" field.
+func (su *SessionUpdate) SetTitle(s string) *SessionUpdate {
+	su.mutation.SetTitle(s)
+	return su
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableTitle(s *string) *SessionUpdate {
+	if s != nil {
+		su.SetTitle(*s)
+	}
+	return su
+}
+
+// SetGroupChat sets the "group_chat" field.
+func (su *SessionUpdate) SetGroupChat(b bool) *SessionUpdate {
+	su.mutation.SetGroupChat(b)
+	return su
+}
+
+// SetNillableGroupChat sets the "group_chat" field if the given value is not nil.
+func (su *SessionUpdate) SetNillableGroupChat(b *bool) *SessionUpdate {
+	if b != nil {
+		su.SetGroupChat(*b)
+	}
+	return su
+}
+
+// SetParticipants sets the "participants" field.
+func (su *SessionUpdate) SetParticipants(s []string) *SessionUpdate {
+	su.mutation.SetParticipants(s)
+	return su
+}
+
+// AppendParticipants appends s to the "participants" field.
+func (su *SessionUpdate) AppendParticipants(s []string) *SessionUpdate {
+	su.mutation.AppendParticipants(s)
+	return su
+}
+
+// ClearParticipants clears the value of the "participants" field.
+func (su *SessionUpdate) ClearParticipants() *SessionUpdate {
+	su.mutation.ClearParticipants()
+	return su
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (su *SessionUpdate) SetUpdatedAt(t time.Time) *SessionUpdate {
 	su.mutation.SetUpdatedAt(t)
@@ -181,9 +242,29 @@ func (su *SessionUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := su.mutation.UserID(); ok {
 		_spec.SetField(session.FieldUserID, field.TypeString, value)
 	}
+	if value, ok := su.mutation.Channel(); ok {
+		_spec.SetField(session.FieldChannel, field.TypeString, value)
+	}
 	if value, ok := su.mutation.Status(); ok {
 		_spec.SetField(session.FieldStatus, field.TypeBool, value)
 	}
+	if value, ok := su.mutation.Title(); ok {
+		_spec.SetField(session.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := su.mutation.GroupChat(); ok {
+		_spec.SetField(session.FieldGroupChat, field.TypeBool, value)
+	}
+	if value, ok := su.mutation.Participants(); ok {
+		_spec.SetField(session.FieldParticipants, field.TypeJSON, value)
+	}
+	if value, ok := su.mutation.AppendedParticipants(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, session.FieldParticipants, value)
+		})
+	}
+	if su.mutation.ParticipantsCleared() {
+		_spec.ClearField(session.FieldParticipants, field.TypeJSON)
+	}
 	if value, ok := su.mutation.UpdatedAt(); ok {
 		_spec.SetField(session.FieldUpdatedAt, field.TypeTime, value)
 	}
@@ -275,6 +356,20 @@ func (suo *SessionUpdateOne) SetUserID(s string) *SessionUpdateOne {
 	return suo
 }
 
+// SetChannel sets the "channel" field.
+func (suo *SessionUpdateOne) SetChannel(s string) *SessionUpdateOne {
+	suo.mutation.SetChannel(s)
+	return suo
+}
+
+// SetNillableChannel sets the "channel" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableChannel(s *string) *SessionUpdateOne {
+	if s != nil {
+		suo.SetChannel(*s)
+	}
+	return suo
+}
+
 // SetStatus sets the "status" field.
 func (suo *SessionUpdateOne) SetStatus(b bool) *SessionUpdateOne {
 	suo.mutation.SetStatus(b)
@@ -289,6 +384,52 @@ func (suo *SessionUpdateOne) SetNillableStatus(b *bool) *SessionUpdateOne {
 	return suo
 }
 
+// SetTitle sets the "title" field.
+func (suo *SessionUpdateOne) SetTitle(s string) *SessionUpdateOne {
+	suo.mutation.SetTitle(s)
+	return suo
+}
+
+// SetNillableTitle sets the "title" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableTitle(s *string) *SessionUpdateOne {
+	if s != nil {
+		suo.SetTitle(*s)
+	}
+	return suo
+}
+
+// SetGroupChat sets the "group_chat" field.
+func (suo *SessionUpdateOne) SetGroupChat(b bool) *SessionUpdateOne {
+	suo.mutation.SetGroupChat(b)
+	return suo
+}
+
+// SetNillableGroupChat sets the "group_chat" field if the given value is not nil.
+func (suo *SessionUpdateOne) SetNillableGroupChat(b *bool) *SessionUpdateOne {
+	if b != nil {
+		suo.SetGroupChat(*b)
+	}
+	return suo
+}
+
+// SetParticipants sets the "participants" field.
+func (suo *SessionUpdateOne) SetParticipants(s []string) *SessionUpdateOne {
+	suo.mutation.SetParticipants(s)
+	return suo
+}
+
+// AppendParticipants appends s to the "participants" field.
+func (suo *SessionUpdateOne) AppendParticipants(s []string) *SessionUpdateOne {
+	suo.mutation.AppendParticipants(s)
+	return suo
+}
+
+// ClearParticipants clears the value of the "participants" field.
+func (suo *SessionUpdateOne) ClearParticipants() *SessionUpdateOne {
+	suo.mutation.ClearParticipants()
+	return suo
+}
+
 // SetUpdatedAt sets the "updated_at" field.
 func (suo *SessionUpdateOne) SetUpdatedAt(t time.Time) *SessionUpdateOne {
 	suo.mutation.SetUpdatedAt(t)
@@ -444,9 +585,29 @@ func (suo *SessionUpdateOne) sqlSave(ctx context.Context) (_node *Session, err e
 	if value, ok := suo.mutation.UserID(); ok {
 		_spec.SetField(session.FieldUserID, field.TypeString, value)
 	}
+	if value, ok := suo.mutation.Channel(); ok {
+		_spec.SetField(session.FieldChannel, field.TypeString, value)
+	}
 	if value, ok := suo.mutation.Status(); ok {
 		_spec.SetField(session.FieldStatus, field.TypeBool, value)
 	}
+	if value, ok := suo.mutation.Title(); ok {
+		_spec.SetField(session.FieldTitle, field.TypeString, value)
+	}
+	if value, ok := suo.mutation.GroupChat(); ok {
+		_spec.SetField(session.FieldGroupChat, field.TypeBool, value)
+	}
+	if value, ok := suo.mutation.Participants(); ok {
+		_spec.SetField(session.FieldParticipants, field.TypeJSON, value)
+	}
+	if value, ok := suo.mutation.AppendedParticipants(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, session.FieldParticipants, value)
+		})
+	}
+	if suo.mutation.ParticipantsCleared() {
+		_spec.ClearField(session.FieldParticipants, field.TypeJSON)
+	}
 	if value, ok := suo.mutation.UpdatedAt(); ok {
 		_spec.SetField(session.FieldUpdatedAt, field.TypeTime, value)
 	}
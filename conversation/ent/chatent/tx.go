@@ -14,8 +14,14 @@ import (
 // Tx is a transactional client that is created by calling Client.Tx().
 type Tx struct {
 	config
+	// DataKey is the client for interacting with the DataKey builders.
+	DataKey *DataKeyClient
+	// Embedding is the client for interacting with the Embedding builders.
+	Embedding *EmbeddingClient
 	// Message is the client for interacting with the Message builders.
 	Message *MessageClient
+	// ResponseCache is the client for interacting with the ResponseCache builders.
+	ResponseCache *ResponseCacheClient
 	// Session is the client for interacting with the Session builders.
 	Session *SessionClient
 
@@ -149,7 +155,10 @@ func (tx *Tx) Client() *Client {
 }
 
 func (tx *Tx) init() {
+	tx.DataKey = NewDataKeyClient(tx.config)
+	tx.Embedding = NewEmbeddingClient(tx.config)
 	tx.Message = NewMessageClient(tx.config)
+	tx.ResponseCache = NewResponseCacheClient(tx.config)
 	tx.Session = NewSessionClient(tx.config)
 }
 
@@ -160,7 +169,7 @@ func (tx *Tx) init() {
 // of them in order to commit or rollback the transaction.
 //
 // If a closed transaction is embedded in one of the generated entities, and the entity
-// applies a query, for example: Message.QueryXXX(), the query will be executed
+// applies a query, for example: DataKey.QueryXXX(), the query will be executed
 // through the driver which created this transaction.
 //
 // Note that txDriver is not goroutine safe.
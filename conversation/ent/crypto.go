@@ -0,0 +1,460 @@
+package ent
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/datakey"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/responsecache"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
+)
+
+const (
+	encryptedPrefix = "enc:v1:"
+	dataKeySize     = 32
+	// 数据密钥在进程内缓存的默认时间
+	defaultKeyCacheTTL = 5 * time.Minute
+	// 并发创建数据密钥时唯一索引冲突的重试次数
+	maxKeyCreateRetries = 3
+)
+
+// ErrKeyNotFound 用户数据密钥不存在，通常是密钥已被删除 (crypto-shredding)
+var ErrKeyNotFound = errors.New("data key not found")
+
+// KeyProvider 管理每个用户的数据密钥
+type KeyProvider interface {
+	// 获取用户当前使用的数据密钥，不存在时创建
+	CurrentKey(ctx context.Context, userId string) (version int, key []byte, err error)
+	// 获取用户指定版本的数据密钥
+	GetKey(ctx context.Context, userId string, version int) ([]byte, error)
+	// 轮换用户数据密钥，返回新的密钥版本
+	RotateKey(ctx context.Context, userId string) (int, error)
+	// 删除用户所有数据密钥，之后该用户的消息无法再解密。
+	// 密钥版本号保持递增，之后创建的密钥不会与已删除的版本重复
+	DeleteKeys(ctx context.Context, userId string) error
+}
+
+// EntKeyProvider 将数据密钥用主密钥加密后保存在 data_keys 表中。
+// 删除密钥时清空 wrapped_key 并保留最新版本的记录作为墓碑，用于分配之后的版本号
+type EntKeyProvider struct {
+	client    *chatent.Client
+	masterKey cipher.AEAD
+	ttl       time.Duration
+	cache     sync.Map
+}
+
+type cachedKey struct {
+	key       []byte
+	expiredAt time.Time
+}
+
+func NewKeyProvider(client *chatent.Client, masterKey []byte) (*EntKeyProvider, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	return &EntKeyProvider{client: client, masterKey: aead, ttl: defaultKeyCacheTTL}, nil
+}
+
+// WithCacheTTL 设置数据密钥在进程内缓存的时间，默认 5 分钟。
+// 其他实例删除用户密钥后，本实例最多在该时间内仍能解密；为 0 时不缓存，每次都从数据库读取
+func (p *EntKeyProvider) WithCacheTTL(ttl time.Duration) *EntKeyProvider {
+	p.ttl = ttl
+	return p
+}
+
+func (p *EntKeyProvider) CurrentKey(ctx context.Context, userId string) (int, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		r, err := p.latest(ctx, userId)
+		if err != nil && !chatent.IsNotFound(err) {
+			return 0, nil, fmt.Errorf("query data key failed: %w", err)
+		}
+		if err == nil && !shredded(r) {
+			key, err := p.unwrap(r)
+			if err != nil {
+				return 0, nil, err
+			}
+			return r.Version, key, nil
+		}
+
+		// 没有密钥或密钥已删除，创建下一个版本的密钥
+		version := 1
+		if r != nil {
+			version = r.Version + 1
+		}
+		key, err := p.create(ctx, userId, version)
+		// 并发请求同时创建密钥，重新读取其他请求创建的密钥
+		if chatent.IsConstraintError(err) && attempt < maxKeyCreateRetries {
+			continue
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		return version, key, nil
+	}
+}
+
+func (p *EntKeyProvider) GetKey(ctx context.Context, userId string, version int) ([]byte, error) {
+	if key, ok := p.loadCache(userId, version); ok {
+		return key, nil
+	}
+
+	r, err := p.client.DataKey.
+		Query().
+		Where(datakey.UserIDEQ(userId), datakey.VersionEQ(version)).
+		Only(ctx)
+	if chatent.IsNotFound(err) || (err == nil && shredded(r)) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query data key failed: %w", err)
+	}
+	return p.unwrap(r)
+}
+
+func (p *EntKeyProvider) RotateKey(ctx context.Context, userId string) (int, error) {
+	for attempt := 0; ; attempt++ {
+		version := 1
+		latest, err := p.latest(ctx, userId)
+		if err == nil {
+			version = latest.Version + 1
+		} else if !chatent.IsNotFound(err) {
+			return 0, fmt.Errorf("query data key failed: %w", err)
+		}
+
+		_, err = p.create(ctx, userId, version)
+		// 其他请求同时创建了相同版本的密钥，重新读取最新版本
+		if chatent.IsConstraintError(err) && attempt < maxKeyCreateRetries {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		return version, nil
+	}
+}
+
+func (p *EntKeyProvider) DeleteKeys(ctx context.Context, userId string) error {
+	latest, err := p.latest(ctx, userId)
+	if chatent.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("query data key failed: %w", err)
+	}
+
+	// 删除旧版本，最新版本清空密钥后作为墓碑保留
+	tx, err := p.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("Start Transaction failed: %w", err)
+	}
+	if _, err := tx.DataKey.Delete().Where(datakey.UserIDEQ(userId), datakey.VersionLT(latest.Version)).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete data key failed: %w", err)
+	}
+	if err := tx.DataKey.UpdateOneID(latest.ID).SetWrappedKey([]byte{}).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete data key failed: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("delete data key failed: %w", err)
+	}
+	for version := 1; version <= latest.Version; version++ {
+		p.cache.Delete(cacheKey(userId, version))
+	}
+	return nil
+}
+
+func (p *EntKeyProvider) latest(ctx context.Context, userId string) (*chatent.DataKey, error) {
+	return p.client.DataKey.
+		Query().
+		Where(datakey.UserIDEQ(userId)).
+		Order(chatent.Desc(datakey.FieldVersion)).
+		First(ctx)
+}
+
+// create 生成并保存指定版本的数据密钥。版本已存在时返回唯一索引冲突的错误
+func (p *EntKeyProvider) create(ctx context.Context, userId string, version int) ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("generate data key failed: %w", err)
+	}
+	wrapped, err := seal(p.masterKey, key, []byte(userId))
+	if err != nil {
+		return nil, fmt.Errorf("wrap data key failed: %w", err)
+	}
+
+	_, err = p.client.DataKey.
+		Create().
+		SetUserID(userId).
+		SetVersion(version).
+		SetWrappedKey(wrapped).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create data key failed: %w", err)
+	}
+	p.storeCache(userId, version, key)
+	return key, nil
+}
+
+// shredded 密钥是否已被删除
+func shredded(r *chatent.DataKey) bool {
+	return len(r.WrappedKey) == 0
+}
+
+func (p *EntKeyProvider) unwrap(r *chatent.DataKey) ([]byte, error) {
+	if key, ok := p.loadCache(r.UserID, r.Version); ok {
+		return key, nil
+	}
+	key, err := open(p.masterKey, r.WrappedKey, []byte(r.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key failed: %w", err)
+	}
+	p.storeCache(r.UserID, r.Version, key)
+	return key, nil
+}
+
+func (p *EntKeyProvider) loadCache(userId string, version int) ([]byte, bool) {
+	v, ok := p.cache.Load(cacheKey(userId, version))
+	if !ok {
+		return nil, false
+	}
+	entry := v.(cachedKey)
+	if time.Now().After(entry.expiredAt) {
+		p.cache.Delete(cacheKey(userId, version))
+		return nil, false
+	}
+	return entry.key, true
+}
+
+func (p *EntKeyProvider) storeCache(userId string, version int, key []byte) {
+	if p.ttl <= 0 {
+		return
+	}
+	p.cache.Store(cacheKey(userId, version), cachedKey{key: key, expiredAt: time.Now().Add(p.ttl)})
+}
+
+func cacheKey(userId string, version int) string {
+	return fmt.Sprintf("%s:%d", userId, version)
+}
+
+// WithEncryption 开启消息内容加密。消息使用会话所属用户的数据密钥加密
+func (c *ConversationHandler) WithEncryption(kp KeyProvider) *ConversationHandler {
+	c.keys = kp
+	return c
+}
+
+// RotateUserKey 轮换用户数据密钥，并使用新密钥重新加密该用户的所有消息和向量文本。
+// 消息和向量在一个事务中更新，失败时保留原来的密文，旧版本密钥仍然可以解密
+func (c *ConversationHandler) RotateUserKey(ctx context.Context, userId string) error {
+	if c.keys == nil {
+		return errors.New("encryption is not enabled")
+	}
+	if _, err := c.keys.RotateKey(ctx, userId); err != nil {
+		return fmt.Errorf("rotate user %s key failed: %w", userId, err)
+	}
+
+	msgs, err := c.client.Message.
+		Query().
+		Where(message.HasSessionWith(session.UserIDEQ(userId))).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("query message failed: %w", err)
+	}
+	es, err := c.client.Embedding.
+		Query().
+		Where(embedding.UserIDEQ(userId)).
+		All(ctx)
+	if err != nil {
+		return fmt.Errorf("query embedding failed: %w", err)
+	}
+	// 事务开始前完成加解密，事务中只执行更新
+	for _, m := range msgs {
+		if m.Content, err = c.reencrypt(ctx, userId, m.Content); err != nil {
+			return err
+		}
+		if m.Parts, err = c.reencrypt(ctx, userId, m.Parts); err != nil {
+			return err
+		}
+	}
+	for _, e := range es {
+		if e.Content, err = c.reencrypt(ctx, userId, e.Content); err != nil {
+			return err
+		}
+		for k, v := range e.Metadata {
+			if e.Metadata[k], err = c.reencrypt(ctx, userId, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	tx, err := c.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("Start Transaction failed: %w", err)
+	}
+	for _, m := range msgs {
+		update := tx.Message.UpdateOneID(m.ID).SetContent(m.Content)
+		if m.Parts != "" {
+			update.SetParts(m.Parts)
+		}
+		if err := update.Exec(ctx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("update message failed: %w", err)
+		}
+	}
+	for _, e := range es {
+		if err := tx.Embedding.UpdateOneID(e.ID).SetContent(e.Content).SetMetadata(e.Metadata).Exec(ctx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("update embedding failed: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *ConversationHandler) reencrypt(ctx context.Context, userId, content string) (string, error) {
+	if content == "" {
+		return "", nil
+	}
+	plaintext, err := c.decrypt(ctx, userId, content)
+	if err != nil {
+		return "", err
+	}
+	return c.encrypt(ctx, userId, plaintext)
+}
+
+// ShredUser 删除用户所有数据密钥，该用户的历史消息将无法解密。
+// 同时删除由用户消息生成的长期记忆、语义缓存、回复缓存和会话标题
+func (c *ConversationHandler) ShredUser(ctx context.Context, userId string) error {
+	if c.keys == nil {
+		return errors.New("encryption is not enabled")
+	}
+
+	tx, err := c.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("Start Transaction failed: %w", err)
+	}
+	if _, err := tx.Embedding.Delete().Where(embedding.UserIDEQ(userId)).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete user %s embeddings failed: %w", userId, err)
+	}
+	if _, err := tx.ResponseCache.Delete().Where(responsecache.UserIDEQ(userId)).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("delete user %s response caches failed: %w", userId, err)
+	}
+	// 会话标题由模型根据对话内容生成，以明文保存
+	if err := tx.Session.Update().Where(session.UserIDEQ(userId)).SetTitle("").Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("clear user %s session titles failed: %w", userId, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Commit Transaction failed: %w", err)
+	}
+	return c.keys.DeleteKeys(ctx, userId)
+}
+
+func (c *ConversationHandler) encrypt(ctx context.Context, userId, content string) (string, error) {
+	return encryptContent(ctx, c.keys, userId, content)
+}
+
+func (c *ConversationHandler) decrypt(ctx context.Context, userId, content string) (string, error) {
+	return decryptContent(ctx, c.keys, userId, content)
+}
+
+// encryptContent 使用用户当前的数据密钥加密内容，kp 为空时不加密
+func encryptContent(ctx context.Context, kp KeyProvider, userId, content string) (string, error) {
+	if kp == nil {
+		return content, nil
+	}
+	version, key, err := kp.CurrentKey(ctx, userId)
+	if err != nil {
+		return "", fmt.Errorf("get user %s data key failed: %w", userId, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(content), []byte(userId))
+	if err != nil {
+		return "", fmt.Errorf("encrypt content failed: %w", err)
+	}
+	return fmt.Sprintf("%s%d:%s", encryptedPrefix, version, base64.StdEncoding.EncodeToString(ciphertext)), nil
+}
+
+// decryptContent 使用内容中记录的密钥版本解密，未加密的内容直接返回
+func decryptContent(ctx context.Context, kp KeyProvider, userId, content string) (string, error) {
+	// 未加密的历史消息直接返回
+	if !strings.HasPrefix(content, encryptedPrefix) {
+		return content, nil
+	}
+	if kp == nil {
+		return "", errors.New("encrypted content found but encryption is not enabled")
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(content, encryptedPrefix), ":", 2)
+	if len(parts) != 2 {
+		return "", errors.New("malformed encrypted content")
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("malformed key version: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed ciphertext: %w", err)
+	}
+
+	key, err := kp.GetKey(ctx, userId, version)
+	if err != nil {
+		return "", fmt.Errorf("get user %s data key failed: %w", userId, err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, ciphertext, []byte(userId))
+	if err != nil {
+		// 使用其他密钥加密的内容 (例如密钥删除后重新创建了相同版本) 同样视为密钥不存在
+		return "", fmt.Errorf("decrypt content failed: %v: %w", err, ErrKeyNotFound)
+	}
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("key must be %d bytes", dataKeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,80 @@
+package ent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+func TestShredUserKeepsKeyVersions(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	kp, err := NewKeyProvider(client, []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewKeyProvider() error = %v", err)
+	}
+	h := New(client).WithEncryption(kp)
+
+	old, err := h.CreateSession(ctx, "alice")
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	q, err := h.CreateMessage(ctx, old, "alice", "default", "我的护照号是 E12345678")
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if _, err := h.CreateSpouseMessage(ctx, old, "default", "alice", "已记录", q); err != nil {
+		t.Fatalf("CreateSpouseMessage() error = %v", err)
+	}
+	if err := h.RenameSession(ctx, "alice", old.ID, "护照信息"); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+
+	if err := h.ShredUser(ctx, "alice"); err != nil {
+		t.Fatalf("ShredUser() error = %v", err)
+	}
+	if _, err := kp.GetKey(ctx, "alice", 1); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("GetKey() error = %v, want ErrKeyNotFound", err)
+	}
+
+	// 用户再次对话时使用新版本的密钥，旧消息标记为已删除
+	version, _, err := kp.CurrentKey(ctx, "alice")
+	if err != nil {
+		t.Fatalf("CurrentKey() error = %v", err)
+	}
+	if version != 2 {
+		t.Fatalf("version = %d, want 2", version)
+	}
+	q, err = h.CreateMessage(ctx, old, "alice", "default", "你好")
+	if err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if _, err := h.CreateSpouseMessage(ctx, old, "default", "alice", "你好！", q); err != nil {
+		t.Fatalf("CreateSpouseMessage() error = %v", err)
+	}
+
+	msgs, err := h.ListMessages(ctx, old, conversation.Page{})
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	if len(msgs) != 4 || !msgs[0].Shredded || msgs[0].Content != "" || !msgs[1].Shredded || msgs[2].Shredded || msgs[2].Content != "你好" {
+		t.Fatalf("messages = %+v", msgs)
+	}
+	history, err := h.ListLatestMessagesWithSpouse(ctx, old, "alice", 10)
+	if err != nil {
+		t.Fatalf("ListLatestMessagesWithSpouse() error = %v", err)
+	}
+	if len(history) != 2 || history[0].Content != "你好" || history[1].Content != "你好！" {
+		t.Fatalf("history = %+v", history)
+	}
+
+	s, err := h.GetSession(ctx, "alice", old.ID)
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if s.Title != "" {
+		t.Fatalf("title = %q, want empty", s.Title)
+	}
+}
@@ -0,0 +1,139 @@
+package ent
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/embedding"
+)
+
+// CreateEmbedding 保存向量。属于用户的向量 (长期记忆、语义缓存) 开启加密时，文本和附加信息使用用户的数据密钥加密
+func (c *ConversationHandler) CreateEmbedding(ctx context.Context, e *conversation.Embedding) (*conversation.Embedding, error) {
+	content, metadata, err := c.encryptEmbedding(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.client.Embedding.
+		Create().
+		SetNamespace(e.Namespace).
+		SetUserID(e.UserID).
+		SetSessionID(e.SessionID).
+		SetMessageID(e.MessageID).
+		SetContent(content).
+		SetVector(encodeVector(e.Vector)).
+		SetMetadata(metadata).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Create Embedding failed: %w", err)
+	}
+	result := toConversationEmbedding(r)
+	result.Content, result.Metadata = e.Content, e.Metadata
+	return result, nil
+}
+
+func (c *ConversationHandler) ListEmbeddings(ctx context.Context, namespace string) ([]*conversation.Embedding, error) {
+	rs, err := c.client.Embedding.
+		Query().
+		Where(embedding.NamespaceEQ(namespace)).
+		Order(chatent.Asc(embedding.FieldCreatedAt)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query embedding failed: %w", err)
+	}
+
+	result := make([]*conversation.Embedding, 0, len(rs))
+	for _, r := range rs {
+		e, err := c.decryptEmbedding(ctx, toConversationEmbedding(r))
+		// 用户密钥已删除，向量对应的内容无法再使用
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) DeleteEmbeddings(ctx context.Context, namespace string, ids []int) error {
+	_, err := c.client.Embedding.
+		Delete().
+		Where(embedding.NamespaceEQ(namespace), embedding.IDIn(ids...)).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("delete embedding failed: %w", err)
+	}
+	return nil
+}
+
+func (c *ConversationHandler) encryptEmbedding(ctx context.Context, e *conversation.Embedding) (string, map[string]string, error) {
+	if c.keys == nil || e.UserID == "" {
+		return e.Content, e.Metadata, nil
+	}
+	content, err := c.encrypt(ctx, e.UserID, e.Content)
+	if err != nil {
+		return "", nil, err
+	}
+	var metadata map[string]string
+	if e.Metadata != nil {
+		metadata = make(map[string]string, len(e.Metadata))
+		for k, v := range e.Metadata {
+			if metadata[k], err = c.encrypt(ctx, e.UserID, v); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return content, metadata, nil
+}
+
+func (c *ConversationHandler) decryptEmbedding(ctx context.Context, e *conversation.Embedding) (*conversation.Embedding, error) {
+	if e.UserID == "" {
+		return e, nil
+	}
+	var err error
+	if e.Content, err = c.decrypt(ctx, e.UserID, e.Content); err != nil {
+		return nil, err
+	}
+	for k, v := range e.Metadata {
+		if e.Metadata[k], err = c.decrypt(ctx, e.UserID, v); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func toConversationEmbedding(e *chatent.Embedding) *conversation.Embedding {
+	return &conversation.Embedding{
+		ID:        e.ID,
+		Namespace: e.Namespace,
+		UserID:    e.UserID,
+		SessionID: e.SessionID,
+		MessageID: e.MessageID,
+		Content:   e.Content,
+		Vector:    decodeVector(e.Vector),
+		Metadata:  e.Metadata,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func encodeVector(v []float32) []byte {
+	b := make([]byte, len(v)*4)
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[i*4:], math.Float32bits(f))
+	}
+	return b
+}
+
+func decodeVector(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return v
+}
@@ -13,6 +13,7 @@ import (
 
 type ConversationHandler struct {
 	client *chatent.Client
+	keys   KeyProvider
 }
 
 func New(client *chatent.Client) *ConversationHandler {
@@ -20,9 +21,22 @@ func New(client *chatent.Client) *ConversationHandler {
 }
 
 func (c *ConversationHandler) CreateSession(ctx context.Context, userId string) (*conversation.Session, error) {
+	return c.CreateChannelSession(ctx, userId, conversation.DefaultChannel)
+}
+
+func (c *ConversationHandler) CloseSession(ctx context.Context, userId string) error {
+	return c.CloseChannelSession(ctx, userId, conversation.DefaultChannel)
+}
+
+func (c *ConversationHandler) GetLatestActiveSession(ctx context.Context, userId string) (*conversation.Session, error) {
+	return c.GetLatestActiveChannelSession(ctx, userId, conversation.DefaultChannel)
+}
+
+func (c *ConversationHandler) CreateChannelSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
 	result, err := c.client.Session.
 		Create().
 		SetUserID(userId).
+		SetChannel(channel).
 		SetStatus(true).
 		Save(ctx)
 	if err != nil {
@@ -31,10 +45,10 @@ func (c *ConversationHandler) CreateSession(ctx context.Context, userId string)
 	return toConversationSession(result), nil
 }
 
-func (c *ConversationHandler) CloseSession(ctx context.Context, userId string) error {
+func (c *ConversationHandler) CloseChannelSession(ctx context.Context, userId, channel string) error {
 	_, err := c.client.Session.
 		Update().
-		Where(session.UserIDEQ(userId), session.StatusEQ(true)).
+		Where(session.UserIDEQ(userId), session.ChannelEQ(channel), session.StatusEQ(true)).
 		SetStatus(false).
 		Save(ctx)
 	if err != nil {
@@ -43,12 +57,15 @@ func (c *ConversationHandler) CloseSession(ctx context.Context, userId string) e
 	return nil
 }
 
-func (c *ConversationHandler) GetLatestActiveSession(ctx context.Context, userId string) (*conversation.Session, error) {
+func (c *ConversationHandler) GetLatestActiveChannelSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
 	result, err := c.client.Session.
 		Query().
-		Where(session.UserIDEQ(userId), session.StatusEQ(true)).
+		Where(session.UserIDEQ(userId), session.ChannelEQ(channel), session.StatusEQ(true)).
 		Order(chatent.Desc(session.FieldCreatedAt)).
 		First(ctx)
+	if chatent.IsNotFound(err) {
+		return nil, fmt.Errorf("GetLatestActiveSession failed: %w", conversation.ErrNotFound)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("GetLatestActiveSession failed: %w", err)
 	}
@@ -56,32 +73,44 @@ func (c *ConversationHandler) GetLatestActiveSession(ctx context.Context, userId
 }
 
 func (c *ConversationHandler) CreateMessage(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content string) (*conversation.Message, error) {
+	encrypted, err := c.encrypt(ctx, session.UserID, content)
+	if err != nil {
+		return nil, err
+	}
 	r, err := c.client.Message.
 		Create().
 		SetSession(toEntSession(session)).
 		SetFromUserID(fromUserId).
 		SetToUserID(toUserId).
-		SetContent(content).
+		SetContent(encrypted).
 		Save(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Create Message failed: %w", err)
 	}
-	return toConversationMessage(r), nil
+	result := toConversationMessage(r)
+	result.Content = content
+	return result, nil
 }
 
 func (c *ConversationHandler) CreateSpouseMessage(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content string, spouse *conversation.Message) (*conversation.Message, error) {
+	encrypted, err := c.encrypt(ctx, session.UserID, content)
+	if err != nil {
+		return nil, err
+	}
 	r, err := c.client.Message.
 		Create().
 		SetSession(toEntSession(session)).
 		SetFromUserID(fromUserId).
 		SetToUserID(toUserId).
-		SetContent(content).
+		SetContent(encrypted).
 		SetSpouse(toEntMessage(spouse)).
 		Save(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Create Spouse Message failed: %w", err)
 	}
-	return toConversationMessage(r), nil
+	result := toConversationMessage(r)
+	result.Content = content
+	return result, nil
 }
 
 func (c *ConversationHandler) ListLatestMessagesWithSpouse(ctx context.Context, session *conversation.Session, userId string, turns int) ([]*conversation.Message, error) {
@@ -114,45 +143,82 @@ func (c *ConversationHandler) ListLatestMessagesWithSpouse(ctx context.Context,
 	})
 	for _, m := range msgs {
 		spouse, ok := spouseMsgMap[m.ID]
-		if ok {
-			result = append(result, toConversationMessage(m), toConversationMessage(spouse))
+		if !ok {
+			continue
+		}
+		q, err := c.decryptMessage(ctx, session.UserID, m)
+		if err != nil {
+			return nil, err
+		}
+		a, err := c.decryptMessage(ctx, session.UserID, spouse)
+		if err != nil {
+			return nil, err
+		}
+		// 用户密钥已删除的对话不再作为上下文
+		if q.Shredded || a.Shredded {
+			continue
 		}
+		result = append(result, q, a)
 	}
 	return result, nil
 }
 
+func (c *ConversationHandler) SaveCitations(ctx context.Context, message *conversation.Message, citations []conversation.Citation) error {
+	err := c.client.Message.
+		UpdateOneID(message.ID).
+		SetCitations(citations).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("Save Message %d Citations failed: %w", message.ID, err)
+	}
+	return nil
+}
+
 func toConversationSession(s *chatent.Session) *conversation.Session {
 	return &conversation.Session{
-		ID:        s.ID,
-		UserID:    s.UserID,
-		Status:    s.Status,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
-		DeletedAt: s.DeletedAt,
+		ID:           s.ID,
+		UserID:       s.UserID,
+		Channel:      s.Channel,
+		Status:       s.Status,
+		Title:        s.Title,
+		GroupChat:    s.GroupChat,
+		Participants: s.Participants,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		DeletedAt:    s.DeletedAt,
 	}
 }
 
 func toEntSession(s *conversation.Session) *chatent.Session {
 	return &chatent.Session{
-		ID:        s.ID,
-		UserID:    s.UserID,
-		Status:    s.Status,
-		CreatedAt: s.CreatedAt,
-		UpdatedAt: s.UpdatedAt,
-		DeletedAt: s.DeletedAt,
+		ID:           s.ID,
+		UserID:       s.UserID,
+		Channel:      s.Channel,
+		Status:       s.Status,
+		Title:        s.Title,
+		GroupChat:    s.GroupChat,
+		Participants: s.Participants,
+		CreatedAt:    s.CreatedAt,
+		UpdatedAt:    s.UpdatedAt,
+		DeletedAt:    s.DeletedAt,
 	}
 }
 
 func toConversationMessage(m *chatent.Message) *conversation.Message {
-	return &conversation.Message{
+	r := &conversation.Message{
 		ID:         m.ID,
 		SessionID:  m.SessionID,
 		FromUserID: m.FromUserID,
 		ToUserID:   m.ToUserID,
 		Content:    m.Content,
 		SpouseID:   m.SpouseID,
+		Citations:  m.Citations,
 		CreatedAt:  m.CreatedAt,
 	}
+	if m.IdempotencyKey != nil {
+		r.IdempotencyKey = *m.IdempotencyKey
+	}
+	return r
 }
 
 func toEntMessage(m *conversation.Message) *chatent.Message {
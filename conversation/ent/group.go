@@ -0,0 +1,119 @@
+package ent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
+)
+
+func (c *ConversationHandler) CreateGroupSession(ctx context.Context, groupId, channel string) (*conversation.Session, error) {
+	result, err := c.client.Session.
+		Create().
+		SetUserID(groupId).
+		SetChannel(channel).
+		SetStatus(true).
+		SetGroupChat(true).
+		SetParticipants([]string{}).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Create Group Session failed: %w", err)
+	}
+	return toConversationSession(result), nil
+}
+
+func (c *ConversationHandler) GetLatestActiveGroupSession(ctx context.Context, groupId, channel string) (*conversation.Session, error) {
+	result, err := c.client.Session.
+		Query().
+		Where(session.UserIDEQ(groupId), session.ChannelEQ(channel), session.StatusEQ(true), session.GroupChatEQ(true)).
+		Order(chatent.Desc(session.FieldCreatedAt)).
+		First(ctx)
+	if chatent.IsNotFound(err) {
+		return nil, fmt.Errorf("GetLatestActiveGroupSession failed: %w", conversation.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetLatestActiveGroupSession failed: %w", err)
+	}
+	return toConversationSession(result), nil
+}
+
+func (c *ConversationHandler) AddParticipant(ctx context.Context, s *conversation.Session, userId string) error {
+	for _, p := range s.Participants {
+		if p == userId {
+			return nil
+		}
+	}
+	// 在事务中重新读取并锁定会话，避免并发加入的参与者互相覆盖
+	tx, err := c.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("Start Transaction failed: %w", err)
+	}
+	r, err := tx.Session.
+		Query().
+		Where(session.IDEQ(s.ID)).
+		Modify(lockForUpdate).
+		Only(ctx)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Get Session %d failed: %w", s.ID, err)
+	}
+	participants := r.Participants
+	for _, p := range participants {
+		if p == userId {
+			tx.Rollback()
+			s.Participants = participants
+			return nil
+		}
+	}
+	participants = append(participants, userId)
+	if err := tx.Session.UpdateOneID(s.ID).SetParticipants(participants).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Add Participant %s to Session %d failed: %w", userId, s.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Add Participant %s to Session %d failed: %w", userId, s.ID, err)
+	}
+	s.Participants = participants
+	return nil
+}
+
+// lockForUpdate 在 MySQL 中使用 SELECT ... FOR UPDATE 锁定读取的行。SQLite 不支持行锁，写事务本身是串行的
+func lockForUpdate(s *sql.Selector) {
+	if s.Dialect() == dialect.MySQL {
+		s.ForUpdate()
+	}
+}
+
+func (c *ConversationHandler) ListLatestGroupMessages(ctx context.Context, s *conversation.Session, limit int) ([]*conversation.Message, error) {
+	msgs, err := c.client.Message.
+		Query().
+		Where(message.SessionIDEQ(s.ID)).
+		Order(chatent.Desc(message.FieldCreatedAt), chatent.Desc(message.FieldID)).
+		Limit(limit).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query group message failed: %w", err)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].ID < msgs[j].ID
+	})
+
+	result := make([]*conversation.Message, 0, len(msgs))
+	for _, m := range msgs {
+		r, err := c.decryptMessage(ctx, s.UserID, m)
+		if err != nil {
+			return nil, err
+		}
+		if r.Shredded {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package ent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestClient(t *testing.T) *chatent.Client {
+	t.Helper()
+	// 使用文件数据库和 BEGIN IMMEDIATE，并发的写事务按顺序执行
+	dsn := fmt.Sprintf("file:%s?_fk=1&_busy_timeout=5000&_txlock=immediate", filepath.Join(t.TempDir(), "xgpt3.db"))
+	client, err := chatent.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("open sqlite failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	if err := client.Schema.Create(context.Background()); err != nil {
+		t.Fatalf("create schema failed: %v", err)
+	}
+	return client
+}
+
+func TestAddParticipantConcurrent(t *testing.T) {
+	ctx := context.Background()
+	h := New(newTestClient(t))
+	s, err := h.CreateGroupSession(ctx, "group-1", "slack")
+	if err != nil {
+		t.Fatalf("CreateGroupSession() error = %v", err)
+	}
+
+	// 每个请求持有各自的会话副本，都看不到其他请求加入的参与者
+	users := []string{"alice", "bob", "carol", "dave", "erin", "frank"}
+	var wg sync.WaitGroup
+	errs := make(chan error, len(users))
+	for _, u := range users {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			copied := *s
+			errs <- h.AddParticipant(ctx, &copied, u)
+		}(u)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("AddParticipant() error = %v", err)
+		}
+	}
+
+	got, err := h.GetLatestActiveGroupSession(ctx, "group-1", "slack")
+	if err != nil {
+		t.Fatalf("GetLatestActiveGroupSession() error = %v", err)
+	}
+	participants := append([]string(nil), got.Participants...)
+	sort.Strings(participants)
+	if fmt.Sprint(participants) != fmt.Sprint(users) {
+		t.Fatalf("participants = %v, want %v", participants, users)
+	}
+}
+
+func TestGetMessageByKeyInEncryptedGroup(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	kp, err := NewKeyProvider(client, []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewKeyProvider() error = %v", err)
+	}
+	h := New(client).WithEncryption(kp)
+	s, err := h.CreateGroupSession(ctx, "group-1", "slack")
+	if err != nil {
+		t.Fatalf("CreateGroupSession() error = %v", err)
+	}
+
+	// 群聊消息使用群的密钥加密，发言的成员没有自己的密钥
+	question, err := h.CreateMessageWithKey(ctx, s, "alice", "slack", "明天去京都还是大阪？", "Ev0LAN670R")
+	if err != nil {
+		t.Fatalf("CreateMessageWithKey() error = %v", err)
+	}
+	if _, err := h.CreateSpouseMessage(ctx, s, "slack", "alice", "去京都", question); err != nil {
+		t.Fatalf("CreateSpouseMessage() error = %v", err)
+	}
+
+	msg, reply, err := h.GetMessageByKey(ctx, "alice", "Ev0LAN670R")
+	if err != nil {
+		t.Fatalf("GetMessageByKey() error = %v", err)
+	}
+	if msg.Content != "明天去京都还是大阪？" || msg.FromUserID != "alice" {
+		t.Fatalf("message = %+v", msg)
+	}
+	if reply == nil || reply.Content != "去京都" {
+		t.Fatalf("reply = %+v", reply)
+	}
+
+	if _, _, err := h.GetMessageByKey(ctx, "bob", "Ev0LAN670R"); err == nil {
+		t.Fatal("GetMessageByKey() of another member error = nil, want ErrNotFound")
+	}
+	var _ conversation.IdempotencyStore = h
+}
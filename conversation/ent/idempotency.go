@@ -0,0 +1,72 @@
+package ent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+)
+
+func (c *ConversationHandler) CreateMessageWithKey(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content, key string) (*conversation.Message, error) {
+	encrypted, err := c.encrypt(ctx, session.UserID, content)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.client.Message.
+		Create().
+		SetSession(toEntSession(session)).
+		SetFromUserID(fromUserId).
+		SetToUserID(toUserId).
+		SetContent(encrypted).
+		SetIdempotencyKey(key).
+		Save(ctx)
+	if chatent.IsConstraintError(err) {
+		return nil, fmt.Errorf("Create Message failed: %v: %w", err, conversation.ErrDuplicateKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Create Message failed: %w", err)
+	}
+	result := toConversationMessage(r)
+	result.Content = content
+	return result, nil
+}
+
+func (c *ConversationHandler) GetMessageByKey(ctx context.Context, userId, key string) (*conversation.Message, *conversation.Message, error) {
+	m, err := c.client.Message.
+		Query().
+		Where(message.FromUserIDEQ(userId), message.IdempotencyKeyEQ(key)).
+		Only(ctx)
+	if chatent.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("Message %s: %w", key, conversation.ErrNotFound)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetMessageByKey failed: %w", err)
+	}
+	// 消息使用会话所有者的密钥加密，群聊中与发送者不同
+	owner, err := c.client.Session.Get(ctx, m.SessionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Get Session %d failed: %w", m.SessionID, err)
+	}
+	msg, err := c.decryptMessage(ctx, owner.UserID, m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := c.client.Message.
+		Query().
+		Where(message.SessionIDEQ(m.SessionID), message.SpouseIDEQ(m.ID)).
+		First(ctx)
+	if chatent.IsNotFound(err) {
+		return msg, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("query spouse message failed: %w", err)
+	}
+	reply, err := c.decryptMessage(ctx, owner.UserID, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, reply, nil
+}
@@ -0,0 +1,28 @@
+package ent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+func TestCreateMessageWithDuplicateKey(t *testing.T) {
+	ctx := context.Background()
+	h := New(newTestClient(t))
+	s, err := h.CreateSession(ctx, "alice")
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if _, err := h.CreateMessageWithKey(ctx, s, "alice", "assistant", "你好", "msg-1"); err != nil {
+		t.Fatalf("CreateMessageWithKey() error = %v", err)
+	}
+	if _, err := h.CreateMessageWithKey(ctx, s, "alice", "assistant", "你好", "msg-1"); !errors.Is(err, conversation.ErrDuplicateKey) {
+		t.Fatalf("CreateMessageWithKey() error = %v, want ErrDuplicateKey", err)
+	}
+	// 其他用户可以使用相同的幂等键
+	if _, err := h.CreateMessageWithKey(ctx, s, "bob", "assistant", "你好", "msg-1"); err != nil {
+		t.Fatalf("CreateMessageWithKey() of another user error = %v", err)
+	}
+}
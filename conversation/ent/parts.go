@@ -0,0 +1,58 @@
+package ent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+)
+
+func (c *ConversationHandler) SaveContentParts(ctx context.Context, session *conversation.Session, message *conversation.Message, parts []conversation.ContentPart) error {
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return err
+	}
+	encrypted, err := c.encrypt(ctx, session.UserID, string(b))
+	if err != nil {
+		return err
+	}
+	err = c.client.Message.
+		UpdateOneID(message.ID).
+		SetParts(encrypted).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("Save Message %d Parts failed: %w", message.ID, err)
+	}
+	return nil
+}
+
+// decryptMessage 转换消息并解密消息内容和内容片段。用户密钥已删除时返回标记为 Shredded 的空消息
+func (c *ConversationHandler) decryptMessage(ctx context.Context, userId string, r *chatent.Message) (*conversation.Message, error) {
+	m := toConversationMessage(r)
+	var err error
+	if m.Content, err = c.decrypt(ctx, userId, m.Content); err != nil {
+		return shreddedMessage(m, err)
+	}
+	if r.Parts == "" {
+		return m, nil
+	}
+	parts, err := c.decrypt(ctx, userId, r.Parts)
+	if err != nil {
+		return shreddedMessage(m, err)
+	}
+	if err := json.Unmarshal([]byte(parts), &m.Parts); err != nil {
+		return nil, fmt.Errorf("unmarshal message %d parts failed: %w", r.ID, err)
+	}
+	return m, nil
+}
+
+func shreddedMessage(m *conversation.Message, err error) (*conversation.Message, error) {
+	if !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+	m.Content, m.Parts, m.Shredded = "", nil, true
+	return m, nil
+}
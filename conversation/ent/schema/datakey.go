@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+type DataKey struct {
+	ent.Schema
+}
+
+func (DataKey) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("user_id").
+			Annotations(entsql.Annotation{Size: 50}).
+			Comment("用户Id"),
+		field.Int("version").
+			Comment("密钥版本"),
+		field.Bytes("wrapped_key").
+			Sensitive().
+			Comment("主密钥加密后的数据密钥"),
+		field.Time("created_at").
+			Default(time.Now).
+			Annotations(&entsql.Annotation{
+				Default: "CURRENT_TIMESTAMP",
+			}).
+			Immutable(),
+	}
+}
+
+func (DataKey) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id", "version").Unique(),
+	}
+}
@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+type Embedding struct {
+	ent.Schema
+}
+
+func (Embedding) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("namespace").
+			Annotations(entsql.Annotation{Size: 100}).
+			Comment("命名空间"),
+		field.String("user_id").
+			Annotations(entsql.Annotation{Size: 50}).
+			Default("").
+			Comment("用户Id"),
+		field.Int("session_id").
+			Default(0).
+			Comment("会话Id"),
+		field.Int("message_id").
+			Default(0).
+			Comment("消息Id"),
+		field.Text("content").
+			Comment("向量对应的文本"),
+		field.Bytes("vector").
+			Comment("向量"),
+		field.JSON("metadata", map[string]string{}).
+			Optional().
+			Comment("附加信息"),
+		field.Time("created_at").
+			Default(time.Now).
+			Annotations(&entsql.Annotation{
+				Default: "CURRENT_TIMESTAMP",
+			}).
+			Immutable(),
+	}
+}
+
+func (Embedding) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("namespace", "created_at"),
+	}
+}
@@ -2,10 +2,12 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
 	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
+	"github.com/fanchunke/xgpt3/conversation"
 	"time"
 )
 
@@ -28,6 +30,17 @@ func (Message) Fields() []ent.Field {
 			Comment("消息内容"),
 		field.Int("spouse_id").
 			Optional(),
+		field.JSON("citations", []conversation.Citation{}).
+			Optional().
+			Comment("回复引用的资料"),
+		field.Text("parts").
+			Optional().
+			Comment("多模态消息的内容片段，JSON 编码，开启加密时与消息内容一样加密保存"),
+		field.String("idempotency_key").
+			Optional().
+			Nillable().
+			Annotations(entsql.Annotation{Size: 100}).
+			Comment("幂等键，通常是平台的消息Id"),
 		field.Time("created_at").
 			Default(time.Now).
 			Annotations(&entsql.Annotation{
@@ -53,5 +66,12 @@ func (Message) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("session_id", "from_user_id", "created_at"),
 		index.Fields("session_id", "to_user_id", "created_at"),
+		index.Fields("from_user_id", "idempotency_key").
+			Unique(),
+		// 全文搜索。中文内容需要使用 ngram 分词器重建索引
+		index.Fields("content").
+			Annotations(entsql.IndexTypes(map[string]string{
+				dialect.MySQL: "FULLTEXT",
+			})),
 	}
 }
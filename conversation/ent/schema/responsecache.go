@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+type ResponseCache struct {
+	ent.Schema
+}
+
+func (ResponseCache) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("key").
+			Annotations(entsql.Annotation{Size: 64}).
+			Unique().
+			Comment("缓存键"),
+		field.Text("value").
+			Comment("缓存内容"),
+		field.String("user_id").
+			Annotations(entsql.Annotation{Size: 50}).
+			Default("").
+			Comment("缓存所属的用户Id，删除用户数据时一并删除"),
+		field.Time("created_at").
+			Default(time.Now).
+			Annotations(&entsql.Annotation{
+				Default: "CURRENT_TIMESTAMP",
+			}).
+			Immutable(),
+		field.Time("updated_at").
+			Default(time.Now).
+			UpdateDefault(time.Now).
+			Comment("缓存更新时间，用于判断是否过期"),
+	}
+}
+
+func (ResponseCache) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("user_id"),
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
+	"github.com/fanchunke/xgpt3/conversation"
 )
 
 type Session struct {
@@ -20,8 +21,22 @@ func (Session) Fields() []ent.Field {
 		field.String("user_id").
 			Annotations(entsql.Annotation{Size: 50}).
 			Comment("用户Id"),
+		field.String("channel").
+			Annotations(entsql.Annotation{Size: 50}).
+			Default(conversation.DefaultChannel).
+			Comment("消息渠道"),
 		field.Bool("status").
 			Comment("会话是否开启").Default(false),
+		field.String("title").
+			Annotations(entsql.Annotation{Size: 255}).
+			Default("").
+			Comment("会话标题"),
+		field.Bool("group_chat").
+			Default(false).
+			Comment("是否为群聊会话，群聊会话的用户Id为群Id"),
+		field.JSON("participants", []string{}).
+			Optional().
+			Comment("群聊会话的参与者"),
 		field.Time("created_at").
 			Default(time.Now).
 			Annotations(&entsql.Annotation{
@@ -51,6 +66,7 @@ func (Session) Edges() []ent.Edge {
 
 func (Session) Indexes() []ent.Index {
 	return []ent.Index{
-		index.Fields("status", "user_id"),
+		index.Fields("status", "user_id", "channel"),
+		index.Fields("user_id", "created_at"),
 	}
 }
@@ -0,0 +1,93 @@
+package ent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
+)
+
+// SearchMessages 在 MySQL 中使用 FULLTEXT 索引搜索，其他数据库使用 LIKE 匹配。
+// 开启加密后消息内容以密文保存，无法搜索
+func (c *ConversationHandler) SearchMessages(ctx context.Context, query string, filter conversation.SearchFilter, page conversation.Page) ([]*conversation.SearchResult, error) {
+	if c.keys != nil {
+		return nil, fmt.Errorf("full-text search is not supported with encryption")
+	}
+	terms := conversation.SearchTerms(query)
+	if len(terms) == 0 {
+		return []*conversation.SearchResult{}, nil
+	}
+
+	q := c.client.Message.
+		Query().
+		Where(matchContent(terms), message.HasSessionWith(session.DeletedAtEQ(0)))
+	if filter.UserID != "" {
+		q = q.Where(message.HasSessionWith(session.UserIDEQ(filter.UserID)))
+	}
+	if filter.SessionID != 0 {
+		q = q.Where(message.SessionIDEQ(filter.SessionID))
+	}
+	if filter.Channel != "" {
+		q = q.Where(message.HasSessionWith(session.ChannelEQ(filter.Channel)))
+	}
+	if !filter.Since.IsZero() {
+		q = q.Where(message.CreatedAtGTE(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		q = q.Where(message.CreatedAtLT(filter.Until))
+	}
+	if after := page.After; after != nil {
+		q = q.Where(message.Or(
+			message.CreatedAtLT(after.CreatedAt),
+			message.And(message.CreatedAtEQ(after.CreatedAt), message.IDLT(after.ID)),
+		))
+	}
+	if page.Limit > 0 {
+		q = q.Limit(page.Limit)
+	}
+	rs, err := q.
+		Order(chatent.Desc(message.FieldCreatedAt), chatent.Desc(message.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("search message failed: %w", err)
+	}
+
+	result := make([]*conversation.SearchResult, 0, len(rs))
+	for _, r := range rs {
+		m := toConversationMessage(r)
+		result = append(result, &conversation.SearchResult{
+			Message: m,
+			Snippet: conversation.Snippet(m.Content, terms),
+		})
+	}
+	return result, nil
+}
+
+// matchContent 消息内容包含所有关键词
+func matchContent(terms []string) func(s *sql.Selector) {
+	return func(s *sql.Selector) {
+		if s.Dialect() == dialect.MySQL {
+			// 布尔模式下每个关键词都必须出现，关键词作为短语匹配
+			words := make([]string, 0, len(terms))
+			for _, t := range terms {
+				words = append(words, `+"`+strings.ReplaceAll(t, `"`, ``)+`"`)
+			}
+			s.Where(sql.P(func(b *sql.Builder) {
+				b.WriteString("MATCH(").Ident(s.C(message.FieldContent)).WriteString(") AGAINST(").
+					Arg(strings.Join(words, " ")).WriteString(" IN BOOLEAN MODE)")
+			}))
+			return
+		}
+		ps := make([]*sql.Predicate, 0, len(terms))
+		for _, t := range terms {
+			ps = append(ps, sql.ContainsFold(s.C(message.FieldContent), t))
+		}
+		s.Where(sql.And(ps...))
+	}
+}
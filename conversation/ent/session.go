@@ -0,0 +1,218 @@
+package ent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/message"
+	"github.com/fanchunke/xgpt3/conversation/ent/chatent/session"
+)
+
+func (c *ConversationHandler) ListSessions(ctx context.Context, userId string, page conversation.Page) ([]*conversation.Session, error) {
+	query := c.client.Session.
+		Query().
+		Where(session.UserIDEQ(userId), session.DeletedAtEQ(0))
+	if after := page.After; after != nil {
+		query = query.Where(session.Or(
+			session.CreatedAtLT(after.CreatedAt),
+			session.And(session.CreatedAtEQ(after.CreatedAt), session.IDLT(after.ID)),
+		))
+	}
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	rs, err := query.
+		Order(chatent.Desc(session.FieldCreatedAt), chatent.Desc(session.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query session failed: %w", err)
+	}
+
+	result := make([]*conversation.Session, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, toConversationSession(r))
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) ScanSessions(ctx context.Context, filter conversation.SessionFilter, page conversation.Page) ([]*conversation.Session, error) {
+	query := c.client.Session.
+		Query().
+		Where(session.DeletedAtEQ(0))
+	if filter.UserID != "" {
+		query = query.Where(session.UserIDEQ(filter.UserID))
+	}
+	if filter.Channel != "" {
+		query = query.Where(session.ChannelEQ(filter.Channel))
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where(session.CreatedAtGTE(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where(session.CreatedAtLT(filter.Until))
+	}
+	if after := page.After; after != nil {
+		query = query.Where(session.Or(
+			session.CreatedAtGT(after.CreatedAt),
+			session.And(session.CreatedAtEQ(after.CreatedAt), session.IDGT(after.ID)),
+		))
+	}
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	rs, err := query.
+		Order(chatent.Asc(session.FieldCreatedAt), chatent.Asc(session.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query session failed: %w", err)
+	}
+
+	result := make([]*conversation.Session, 0, len(rs))
+	for _, r := range rs {
+		result = append(result, toConversationSession(r))
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) GetSession(ctx context.Context, userId string, sessionId int) (*conversation.Session, error) {
+	r, err := c.client.Session.
+		Query().
+		Where(session.IDEQ(sessionId), session.UserIDEQ(userId), session.DeletedAtEQ(0)).
+		Only(ctx)
+	if chatent.IsNotFound(err) {
+		return nil, fmt.Errorf("Session %d: %w", sessionId, conversation.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetSession failed: %w", err)
+	}
+	return toConversationSession(r), nil
+}
+
+func (c *ConversationHandler) ListMessages(ctx context.Context, s *conversation.Session, page conversation.Page) ([]*conversation.Message, error) {
+	query := c.client.Message.
+		Query().
+		Where(message.SessionIDEQ(s.ID))
+	if after := page.After; after != nil {
+		query = query.Where(message.Or(
+			message.CreatedAtGT(after.CreatedAt),
+			message.And(message.CreatedAtEQ(after.CreatedAt), message.IDGT(after.ID)),
+		))
+	}
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	rs, err := query.
+		Order(chatent.Asc(message.FieldCreatedAt), chatent.Asc(message.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query message failed: %w", err)
+	}
+
+	result := make([]*conversation.Message, 0, len(rs))
+	for _, r := range rs {
+		m, err := c.decryptMessage(ctx, s.UserID, r)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) CloseSessionByID(ctx context.Context, userId string, sessionId int) error {
+	return c.updateSession(ctx, userId, sessionId, func(u *chatent.SessionUpdate) {
+		u.SetStatus(false)
+	})
+}
+
+func (c *ConversationHandler) ReopenSession(ctx context.Context, userId string, sessionId int) error {
+	tx, err := c.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("Start Transaction failed: %w", err)
+	}
+	target, err := tx.Session.
+		Query().
+		Where(session.IDEQ(sessionId), session.UserIDEQ(userId), session.DeletedAtEQ(0)).
+		Only(ctx)
+	if chatent.IsNotFound(err) {
+		tx.Rollback()
+		return fmt.Errorf("Session %d: %w", sessionId, conversation.ErrNotFound)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("GetSession failed: %w", err)
+	}
+	if err := tx.Session.
+		Update().
+		Where(session.UserIDEQ(userId), session.ChannelEQ(target.Channel), session.StatusEQ(true), session.IDNEQ(sessionId)).
+		SetStatus(false).
+		Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Close User %s Session failed: %w", userId, err)
+	}
+	if err := tx.Session.UpdateOne(target).SetStatus(true).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Reopen Session %d failed: %w", sessionId, err)
+	}
+	return tx.Commit()
+}
+
+func (c *ConversationHandler) RenameSession(ctx context.Context, userId string, sessionId int, title string) error {
+	return c.updateSession(ctx, userId, sessionId, func(u *chatent.SessionUpdate) {
+		u.SetTitle(title)
+	})
+}
+
+// DeleteSession 软删除会话，已删除的会话不会再被查询到
+func (c *ConversationHandler) DeleteSession(ctx context.Context, userId string, sessionId int) error {
+	return c.updateSession(ctx, userId, sessionId, func(u *chatent.SessionUpdate) {
+		u.SetStatus(false).SetDeletedAt(int(time.Now().Unix()))
+	})
+}
+
+func (c *ConversationHandler) DeleteMessage(ctx context.Context, s *conversation.Session, messageId int) error {
+	tx, err := c.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("Start Transaction failed: %w", err)
+	}
+	ids, err := tx.Message.
+		Query().
+		Where(message.SessionIDEQ(s.ID), message.Or(message.IDEQ(messageId), message.SpouseIDEQ(messageId))).
+		IDs(ctx)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("query message failed: %w", err)
+	}
+	if len(ids) == 0 {
+		tx.Rollback()
+		return fmt.Errorf("Message %d: %w", messageId, conversation.ErrNotFound)
+	}
+	// 先解除配对关系，避免删除时违反外键约束
+	if err := tx.Message.Update().Where(message.IDIn(ids...)).ClearSpouse().Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Clear Message %d Spouse failed: %w", messageId, err)
+	}
+	if _, err := tx.Message.Delete().Where(message.IDIn(ids...)).Exec(ctx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("Delete Message %d failed: %w", messageId, err)
+	}
+	return tx.Commit()
+}
+
+func (c *ConversationHandler) updateSession(ctx context.Context, userId string, sessionId int, set func(u *chatent.SessionUpdate)) error {
+	u := c.client.Session.
+		Update().
+		Where(session.IDEQ(sessionId), session.UserIDEQ(userId), session.DeletedAtEQ(0))
+	set(u)
+	n, err := u.Save(ctx)
+	if err != nil {
+		return fmt.Errorf("Update Session %d failed: %w", sessionId, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("Session %d: %w", sessionId, conversation.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+func (c *ConversationHandler) CreateGroupSession(ctx context.Context, groupId, channel string) (*conversation.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	s := &conversation.Session{
+		ID:           c.id(),
+		UserID:       groupId,
+		Channel:      channel,
+		Status:       true,
+		GroupChat:    true,
+		Participants: []string{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	c.sessions = append(c.sessions, s)
+	return copySession(s), nil
+}
+
+func (c *ConversationHandler) GetLatestActiveGroupSession(ctx context.Context, groupId, channel string) (*conversation.Session, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var latest *conversation.Session
+	for _, s := range c.sessions {
+		if s.UserID == groupId && s.Channel == channel && s.Status && s.GroupChat && (latest == nil || !s.CreatedAt.Before(latest.CreatedAt)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("GetLatestActiveGroupSession failed: %w", conversation.ErrNotFound)
+	}
+	return copySession(latest), nil
+}
+
+func (c *ConversationHandler) AddParticipant(ctx context.Context, session *conversation.Session, userId string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range c.sessions {
+		if s.ID != session.ID {
+			continue
+		}
+		for _, p := range s.Participants {
+			if p == userId {
+				session.Participants = append([]string(nil), s.Participants...)
+				return nil
+			}
+		}
+		s.Participants = append(s.Participants, userId)
+		s.UpdatedAt = time.Now()
+		session.Participants = append([]string(nil), s.Participants...)
+		return nil
+	}
+	return fmt.Errorf("Session %d: %w", session.ID, conversation.ErrNotFound)
+}
+
+func (c *ConversationHandler) ListLatestGroupMessages(ctx context.Context, session *conversation.Session, limit int) ([]*conversation.Message, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	msgs := make([]*conversation.Message, 0)
+	for _, m := range c.messages {
+		if m.SessionID == session.ID {
+			msgs = append(msgs, m)
+		}
+	}
+	if len(msgs) > limit {
+		msgs = msgs[len(msgs)-limit:]
+	}
+
+	result := make([]*conversation.Message, 0, len(msgs))
+	for _, m := range msgs {
+		result = append(result, copyMessage(m))
+	}
+	return result, nil
+}
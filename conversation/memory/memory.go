@@ -0,0 +1,443 @@
+// Package memory 提供基于内存的会话后端，适用于测试和命令行等不需要持久化的场景
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+type ConversationHandler struct {
+	mu         sync.RWMutex
+	sessions   []*conversation.Session
+	messages   []*conversation.Message
+	embeddings []*conversation.Embedding
+	index      invertedIndex
+	nextId     int
+}
+
+func New() *ConversationHandler {
+	return &ConversationHandler{index: make(invertedIndex)}
+}
+
+func (c *ConversationHandler) id() int {
+	c.nextId++
+	return c.nextId
+}
+
+func (c *ConversationHandler) CreateSession(ctx context.Context, userId string) (*conversation.Session, error) {
+	return c.CreateChannelSession(ctx, userId, conversation.DefaultChannel)
+}
+
+func (c *ConversationHandler) CloseSession(ctx context.Context, userId string) error {
+	return c.CloseChannelSession(ctx, userId, conversation.DefaultChannel)
+}
+
+func (c *ConversationHandler) GetLatestActiveSession(ctx context.Context, userId string) (*conversation.Session, error) {
+	return c.GetLatestActiveChannelSession(ctx, userId, conversation.DefaultChannel)
+}
+
+func (c *ConversationHandler) CreateChannelSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	s := &conversation.Session{
+		ID:        c.id(),
+		UserID:    userId,
+		Channel:   channel,
+		Status:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	c.sessions = append(c.sessions, s)
+	return copySession(s), nil
+}
+
+func (c *ConversationHandler) CloseChannelSession(ctx context.Context, userId, channel string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, s := range c.sessions {
+		if s.UserID == userId && s.Channel == channel && s.Status {
+			s.Status = false
+			s.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (c *ConversationHandler) GetLatestActiveChannelSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var latest *conversation.Session
+	for _, s := range c.sessions {
+		if s.UserID == userId && s.Channel == channel && s.Status && (latest == nil || !s.CreatedAt.Before(latest.CreatedAt)) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("GetLatestActiveSession failed: %w", conversation.ErrNotFound)
+	}
+	return copySession(latest), nil
+}
+
+func (c *ConversationHandler) CreateMessage(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content string) (*conversation.Message, error) {
+	return c.CreateMessageWithKey(ctx, session, fromUserId, toUserId, content, "")
+}
+
+func (c *ConversationHandler) CreateMessageWithKey(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content, key string) (*conversation.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key != "" && c.messageByKey(fromUserId, key) != nil {
+		return nil, fmt.Errorf("Create Message %s failed: %w", key, conversation.ErrDuplicateKey)
+	}
+	m := &conversation.Message{
+		ID:             c.id(),
+		SessionID:      session.ID,
+		FromUserID:     fromUserId,
+		ToUserID:       toUserId,
+		Content:        content,
+		IdempotencyKey: key,
+		CreatedAt:      time.Now(),
+	}
+	c.messages = append(c.messages, m)
+	c.index.add(m)
+	return copyMessage(m), nil
+}
+
+func (c *ConversationHandler) GetMessageByKey(ctx context.Context, userId, key string) (*conversation.Message, *conversation.Message, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m := c.messageByKey(userId, key)
+	if m == nil {
+		return nil, nil, fmt.Errorf("Message %s: %w", key, conversation.ErrNotFound)
+	}
+	for _, r := range c.messages {
+		if r.SpouseID == m.ID && r.ToUserID == userId {
+			return copyMessage(m), copyMessage(r), nil
+		}
+	}
+	return copyMessage(m), nil, nil
+}
+
+func (c *ConversationHandler) messageByKey(userId, key string) *conversation.Message {
+	for _, m := range c.messages {
+		if m.FromUserID == userId && m.IdempotencyKey == key {
+			return m
+		}
+	}
+	return nil
+}
+
+func (c *ConversationHandler) CreateSpouseMessage(ctx context.Context, session *conversation.Session, fromUserId, toUserId, content string, spouse *conversation.Message) (*conversation.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := &conversation.Message{
+		ID:         c.id(),
+		SessionID:  session.ID,
+		FromUserID: fromUserId,
+		ToUserID:   toUserId,
+		Content:    content,
+		SpouseID:   spouse.ID,
+		CreatedAt:  time.Now(),
+	}
+	for _, s := range c.messages {
+		if s.ID == spouse.ID {
+			s.SpouseID = m.ID
+		}
+	}
+	c.messages = append(c.messages, m)
+	c.index.add(m)
+	return copyMessage(m), nil
+}
+
+func (c *ConversationHandler) ListLatestMessagesWithSpouse(ctx context.Context, session *conversation.Session, userId string, turns int) ([]*conversation.Message, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	spouses := make(map[int]*conversation.Message)
+	questions := make([]*conversation.Message, 0)
+	for _, m := range c.messages {
+		if m.SessionID != session.ID || m.SpouseID == 0 {
+			continue
+		}
+		if m.FromUserID == userId {
+			questions = append(questions, m)
+		} else if m.ToUserID == userId {
+			spouses[m.SpouseID] = m
+		}
+	}
+	if len(questions) > turns {
+		questions = questions[len(questions)-turns:]
+	}
+
+	result := make([]*conversation.Message, 0, len(questions)*2)
+	for _, m := range questions {
+		if spouse, ok := spouses[m.ID]; ok {
+			result = append(result, copyMessage(m), copyMessage(spouse))
+		}
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) SaveCitations(ctx context.Context, message *conversation.Message, citations []conversation.Citation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range c.messages {
+		if m.ID == message.ID {
+			m.Citations = append([]conversation.Citation{}, citations...)
+			return nil
+		}
+	}
+	return fmt.Errorf("Message %d: %w", message.ID, conversation.ErrNotFound)
+}
+
+func (c *ConversationHandler) SaveContentParts(ctx context.Context, session *conversation.Session, message *conversation.Message, parts []conversation.ContentPart) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range c.messages {
+		if m.ID == message.ID {
+			m.Parts = append([]conversation.ContentPart{}, parts...)
+			return nil
+		}
+	}
+	return fmt.Errorf("Message %d: %w", message.ID, conversation.ErrNotFound)
+}
+
+func (c *ConversationHandler) CreateEmbedding(ctx context.Context, e *conversation.Embedding) (*conversation.Embedding, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := *e
+	r.ID = c.id()
+	r.CreatedAt = time.Now()
+	c.embeddings = append(c.embeddings, &r)
+	result := r
+	return &result, nil
+}
+
+func (c *ConversationHandler) ListEmbeddings(ctx context.Context, namespace string) ([]*conversation.Embedding, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*conversation.Embedding, 0)
+	for _, e := range c.embeddings {
+		if e.Namespace == namespace {
+			r := *e
+			result = append(result, &r)
+		}
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) DeleteEmbeddings(ctx context.Context, namespace string, ids []int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deleted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		deleted[id] = true
+	}
+	kept := c.embeddings[:0]
+	for _, e := range c.embeddings {
+		if e.Namespace != namespace || !deleted[e.ID] {
+			kept = append(kept, e)
+		}
+	}
+	c.embeddings = kept
+	return nil
+}
+
+func (c *ConversationHandler) ListSessions(ctx context.Context, userId string, page conversation.Page) ([]*conversation.Session, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*conversation.Session, 0)
+	for _, s := range c.sessions {
+		if s.UserID == userId && s.DeletedAt == 0 && (page.After == nil || before(s.CreatedAt, s.ID, page.After)) {
+			result = append(result, copySession(s))
+		}
+	}
+	// 按创建时间倒序
+	sort.Slice(result, func(i, j int) bool {
+		return before(result[j].CreatedAt, result[j].ID, &conversation.Cursor{CreatedAt: result[i].CreatedAt, ID: result[i].ID})
+	})
+	if page.Limit > 0 && len(result) > page.Limit {
+		result = result[:page.Limit]
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) ScanSessions(ctx context.Context, filter conversation.SessionFilter, page conversation.Page) ([]*conversation.Session, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]*conversation.Session, 0)
+	for _, s := range c.sessions {
+		if s.DeletedAt != 0 || (filter.UserID != "" && s.UserID != filter.UserID) || (filter.Channel != "" && s.Channel != filter.Channel) {
+			continue
+		}
+		if (!filter.Since.IsZero() && s.CreatedAt.Before(filter.Since)) || (!filter.Until.IsZero() && !s.CreatedAt.Before(filter.Until)) {
+			continue
+		}
+		if page.After != nil && !before(page.After.CreatedAt, page.After.ID, &conversation.Cursor{CreatedAt: s.CreatedAt, ID: s.ID}) {
+			continue
+		}
+		result = append(result, copySession(s))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return before(result[i].CreatedAt, result[i].ID, &conversation.Cursor{CreatedAt: result[j].CreatedAt, ID: result[j].ID})
+	})
+	if page.Limit > 0 && len(result) > page.Limit {
+		result = result[:page.Limit]
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) GetSession(ctx context.Context, userId string, sessionId int) (*conversation.Session, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s := c.session(userId, sessionId)
+	if s == nil {
+		return nil, fmt.Errorf("Session %d: %w", sessionId, conversation.ErrNotFound)
+	}
+	return copySession(s), nil
+}
+
+func (c *ConversationHandler) ListMessages(ctx context.Context, session *conversation.Session, page conversation.Page) ([]*conversation.Message, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// 消息按创建顺序追加，已经是正序
+	result := make([]*conversation.Message, 0)
+	for _, m := range c.messages {
+		if m.SessionID != session.ID {
+			continue
+		}
+		if page.After != nil && !before(page.After.CreatedAt, page.After.ID, &conversation.Cursor{CreatedAt: m.CreatedAt, ID: m.ID}) {
+			continue
+		}
+		result = append(result, copyMessage(m))
+		if page.Limit > 0 && len(result) == page.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) CloseSessionByID(ctx context.Context, userId string, sessionId int) error {
+	return c.updateSession(userId, sessionId, func(s *conversation.Session) {
+		s.Status = false
+	})
+}
+
+func (c *ConversationHandler) ReopenSession(ctx context.Context, userId string, sessionId int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	target := c.session(userId, sessionId)
+	if target == nil {
+		return fmt.Errorf("Session %d: %w", sessionId, conversation.ErrNotFound)
+	}
+	now := time.Now()
+	for _, s := range c.sessions {
+		if s.UserID == userId && s.Channel == target.Channel && s.Status && s != target {
+			s.Status = false
+			s.UpdatedAt = now
+		}
+	}
+	target.Status = true
+	target.UpdatedAt = now
+	return nil
+}
+
+func (c *ConversationHandler) RenameSession(ctx context.Context, userId string, sessionId int, title string) error {
+	return c.updateSession(userId, sessionId, func(s *conversation.Session) {
+		s.Title = title
+	})
+}
+
+func (c *ConversationHandler) DeleteSession(ctx context.Context, userId string, sessionId int) error {
+	return c.updateSession(userId, sessionId, func(s *conversation.Session) {
+		s.Status = false
+		s.DeletedAt = int(time.Now().Unix())
+	})
+}
+
+func (c *ConversationHandler) DeleteMessage(ctx context.Context, session *conversation.Session, messageId int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kept := c.messages[:0]
+	deleted := 0
+	for _, m := range c.messages {
+		if m.SessionID == session.ID && (m.ID == messageId || m.SpouseID == messageId) {
+			deleted++
+			c.index.remove(m)
+			continue
+		}
+		kept = append(kept, m)
+	}
+	c.messages = kept
+	if deleted == 0 {
+		return fmt.Errorf("Message %d: %w", messageId, conversation.ErrNotFound)
+	}
+	return nil
+}
+
+func (c *ConversationHandler) updateSession(userId string, sessionId int, set func(s *conversation.Session)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.session(userId, sessionId)
+	if s == nil {
+		return fmt.Errorf("Session %d: %w", sessionId, conversation.ErrNotFound)
+	}
+	set(s)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *ConversationHandler) session(userId string, sessionId int) *conversation.Session {
+	for _, s := range c.sessions {
+		if s.ID == sessionId && s.UserID == userId && s.DeletedAt == 0 {
+			return s
+		}
+	}
+	return nil
+}
+
+// before 判断记录是否排在游标之前 (按 created_at、id 比较)
+func before(createdAt time.Time, id int, cursor *conversation.Cursor) bool {
+	if createdAt.Equal(cursor.CreatedAt) {
+		return id < cursor.ID
+	}
+	return createdAt.Before(cursor.CreatedAt)
+}
+
+func copySession(s *conversation.Session) *conversation.Session {
+	r := *s
+	if s.Participants != nil {
+		r.Participants = append([]string{}, s.Participants...)
+	}
+	return &r
+}
+
+func copyMessage(m *conversation.Message) *conversation.Message {
+	r := *m
+	r.Citations = append([]conversation.Citation(nil), m.Citations...)
+	r.Parts = append([]conversation.ContentPart(nil), m.Parts...)
+	return &r
+}
@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+// invertedIndex 关键词到消息Id的倒排索引。英文等按单词切分，中日韩文字按相邻两个字切分
+type invertedIndex map[string]map[int]struct{}
+
+func (idx invertedIndex) add(m *conversation.Message) {
+	for _, token := range tokenize(m.Content) {
+		ids, ok := idx[token]
+		if !ok {
+			ids = make(map[int]struct{})
+			idx[token] = ids
+		}
+		ids[m.ID] = struct{}{}
+	}
+}
+
+func (idx invertedIndex) remove(m *conversation.Message) {
+	for _, token := range tokenize(m.Content) {
+		delete(idx[token], m.ID)
+		if len(idx[token]) == 0 {
+			delete(idx, token)
+		}
+	}
+}
+
+// candidates 包含所有 token 的消息Id
+func (idx invertedIndex) candidates(tokens []string) map[int]struct{} {
+	var result map[int]struct{}
+	for _, token := range tokens {
+		ids := idx[token]
+		next := make(map[int]struct{}, len(ids))
+		for id := range ids {
+			if _, ok := result[id]; result == nil || ok {
+				next[id] = struct{}{}
+			}
+		}
+		result = next
+		if len(result) == 0 {
+			break
+		}
+	}
+	return result
+}
+
+func tokenize(s string) []string {
+	tokens := make([]string, 0)
+	var word []rune
+	var cjk []rune
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			tokens = append(tokens, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			tokens = append(tokens, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+func (c *ConversationHandler) SearchMessages(ctx context.Context, query string, filter conversation.SearchFilter, page conversation.Page) ([]*conversation.SearchResult, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	terms := conversation.SearchTerms(query)
+	if len(terms) == 0 {
+		return []*conversation.SearchResult{}, nil
+	}
+	tokens := make([]string, 0)
+	for _, t := range terms {
+		tokens = append(tokens, tokenize(t)...)
+	}
+	ids := c.index.candidates(tokens)
+
+	sessions := make(map[int]*conversation.Session, len(c.sessions))
+	for _, s := range c.sessions {
+		sessions[s.ID] = s
+	}
+	matched := make([]*conversation.Message, 0)
+	for _, m := range c.messages {
+		if _, ok := ids[m.ID]; !ok || !c.matchFilter(m, sessions[m.SessionID], filter) || !containsAll(m.Content, terms) {
+			continue
+		}
+		if page.After != nil && !before(m.CreatedAt, m.ID, page.After) {
+			continue
+		}
+		matched = append(matched, m)
+	}
+	// 按创建时间倒序
+	sort.Slice(matched, func(i, j int) bool {
+		return before(matched[j].CreatedAt, matched[j].ID, &conversation.Cursor{CreatedAt: matched[i].CreatedAt, ID: matched[i].ID})
+	})
+	if page.Limit > 0 && len(matched) > page.Limit {
+		matched = matched[:page.Limit]
+	}
+
+	result := make([]*conversation.SearchResult, 0, len(matched))
+	for _, m := range matched {
+		result = append(result, &conversation.SearchResult{
+			Message: copyMessage(m),
+			Snippet: conversation.Snippet(m.Content, terms),
+		})
+	}
+	return result, nil
+}
+
+func (c *ConversationHandler) matchFilter(m *conversation.Message, s *conversation.Session, filter conversation.SearchFilter) bool {
+	// 已删除会话中的消息不会被搜索到
+	if s == nil || s.DeletedAt != 0 {
+		return false
+	}
+	if filter.UserID != "" && (s == nil || s.UserID != filter.UserID) {
+		return false
+	}
+	if filter.SessionID != 0 && m.SessionID != filter.SessionID {
+		return false
+	}
+	if filter.Channel != "" && (s == nil || s.Channel != filter.Channel) {
+		return false
+	}
+	if !filter.Since.IsZero() && m.CreatedAt.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !m.CreatedAt.Before(filter.Until) {
+		return false
+	}
+	return true
+}
+
+func containsAll(content string, terms []string) bool {
+	content = strings.ToLower(content)
+	for _, t := range terms {
+		if !strings.Contains(content, strings.ToLower(t)) {
+			return false
+		}
+	}
+	return true
+}
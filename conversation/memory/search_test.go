@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+func TestSearchSkipsDeletedSessions(t *testing.T) {
+	ctx := context.Background()
+	h := New()
+	kept, _ := h.CreateSession(ctx, "alice")
+	if _, err := h.CreateMessage(ctx, kept, "alice", "default", "怎么重置密码"); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if err := h.CloseSession(ctx, "alice"); err != nil {
+		t.Fatalf("CloseSession() error = %v", err)
+	}
+	deleted, _ := h.CreateSession(ctx, "alice")
+	if _, err := h.CreateMessage(ctx, deleted, "alice", "default", "忘记密码了"); err != nil {
+		t.Fatalf("CreateMessage() error = %v", err)
+	}
+	if err := h.DeleteSession(ctx, "alice", deleted.ID); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+
+	rs, err := h.SearchMessages(ctx, "密码", conversation.SearchFilter{UserID: "alice"}, conversation.Page{})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	if len(rs) != 1 || rs[0].Message.SessionID != kept.ID {
+		t.Fatalf("results = %+v, want only session %d", rs, kept.ID)
+	}
+}
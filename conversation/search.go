@@ -0,0 +1,120 @@
+package conversation
+
+import (
+	"context"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 高亮片段前后保留的字符数
+const snippetContext = 30
+
+// SearchFilter 消息搜索的筛选条件，零值表示不限制
+type SearchFilter struct {
+	// 用户Id
+	UserID string
+	// 会话Id
+	SessionID int
+	// 消息渠道
+	Channel string
+	// 消息创建时间不早于
+	Since time.Time
+	// 消息创建时间早于
+	Until time.Time
+}
+
+// SearchResult 消息搜索结果
+type SearchResult struct {
+	Message *Message `json:"message"`
+	// 高亮片段，内容经过 HTML 转义，命中的关键词用 <em></em> 包裹
+	Snippet string `json:"snippet"`
+}
+
+// MessageSearcher 按关键词搜索消息
+type MessageSearcher interface {
+	// 搜索同时包含所有关键词的消息，按创建时间倒序
+	SearchMessages(ctx context.Context, query string, filter SearchFilter, page Page) ([]*SearchResult, error)
+}
+
+// SearchTerms 将搜索语句按空白切分为关键词
+func SearchTerms(query string) []string {
+	return strings.Fields(query)
+}
+
+// Snippet 截取第一个关键词附近的内容，转义 HTML 后高亮其中所有的关键词
+func Snippet(content string, terms []string) string {
+	lower := fold(content)
+	start, end := 0, len(content)
+	for _, t := range terms {
+		if i := strings.Index(lower, fold(t)); i >= 0 {
+			start, end = i, i+len(t)
+			break
+		}
+	}
+
+	// 按字符扩展片段边界
+	prefix := ""
+	for n := 0; n < snippetContext && start > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(content[:start])
+		start -= size
+	}
+	if start > 0 {
+		prefix = "..."
+	}
+	suffix := ""
+	for n := 0; n < snippetContext && end < len(content); n++ {
+		_, size := utf8.DecodeRuneInString(content[end:])
+		end += size
+	}
+	if end < len(content) {
+		suffix = "..."
+	}
+	return prefix + highlight(content[start:end], terms) + suffix
+}
+
+func highlight(s string, terms []string) string {
+	lower := fold(s)
+	marked := make([]bool, len(s))
+	for _, t := range terms {
+		t = fold(t)
+		if t == "" {
+			continue
+		}
+		for i := 0; ; {
+			j := strings.Index(lower[i:], t)
+			if j < 0 {
+				break
+			}
+			for k := i + j; k < i+j+len(t); k++ {
+				marked[k] = true
+			}
+			i += j + len(t)
+		}
+	}
+
+	// 按命中与否分段转义，避免消息内容中的 HTML 被当作标记
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		j := i
+		for j < len(s) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString("<em>" + html.EscapeString(s[i:j]) + "</em>")
+		} else {
+			b.WriteString(html.EscapeString(s[i:j]))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// fold 忽略大小写，转换后字节长度变化时保持原样，保证下标与原文一致
+func fold(s string) string {
+	if lower := strings.ToLower(s); len(lower) == len(s) {
+		return lower
+	}
+	return s
+}
@@ -0,0 +1,11 @@
+package conversation
+
+import "testing"
+
+func TestSnippetEscapesHTML(t *testing.T) {
+	got := Snippet(`<script>alert("重置密码")</script>`, []string{"重置", "SCRIPT"})
+	want := `&lt;<em>script</em>&gt;alert(&#34;<em>重置</em>密码&#34;)&lt;/<em>script</em>&gt;`
+	if got != want {
+		t.Fatalf("Snippet() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,175 @@
+// Package transfer 以 JSONL 格式导出、导入会话，可以直接生成 OpenAI 微调数据
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	// 原始格式，每行一个会话及其全部消息，可以通过 Importer 恢复
+	FormatRaw Format = "raw"
+	// OpenAI chat 微调格式，每行一个 {"messages":[...]}，只包含有回复的对话。
+	// 群聊会话中所有成员的提问都作为用户消息
+	FormatFineTune Format = "finetune"
+)
+
+const batchSize = 500
+
+// Filter 导出的筛选条件，零值表示不限制
+type Filter struct {
+	// 用户Id
+	UserID string
+	// 消息渠道
+	Channel string
+	// 会话创建时间不早于
+	Since time.Time
+	// 会话创建时间早于
+	Until time.Time
+}
+
+// Record 原始格式的一行
+type Record struct {
+	Session  *conversation.Session   `json:"session"`
+	Messages []*conversation.Message `json:"messages"`
+}
+
+// FineTuneRecord 微调格式的一行
+type FineTuneRecord struct {
+	Messages []openai.ChatCompletionMessage `json:"messages"`
+}
+
+type Exporter struct {
+	ch     conversation.Handler
+	format Format
+	filter Filter
+	system string
+}
+
+// NewExporter 创建导出器。ch 需要实现 conversation.SessionScanner 和 conversation.SessionManager
+func NewExporter(ch conversation.Handler) *Exporter {
+	return &Exporter{ch: ch, format: FormatRaw}
+}
+
+func (e *Exporter) WithFormat(format Format) *Exporter {
+	e.format = format
+	return e
+}
+
+func (e *Exporter) WithFilter(filter Filter) *Exporter {
+	e.filter = filter
+	return e
+}
+
+// WithSystemPrompt 微调格式中每条数据开头加上的系统消息
+func (e *Exporter) WithSystemPrompt(prompt string) *Exporter {
+	e.system = prompt
+	return e
+}
+
+// Export 将符合条件的会话写入 w，返回导出的会话数
+func (e *Exporter) Export(ctx context.Context, w io.Writer) (int, error) {
+	scanner, ok := e.ch.(conversation.SessionScanner)
+	if !ok {
+		return 0, fmt.Errorf("conversation handler does not support session scan")
+	}
+	sm, ok := e.ch.(conversation.SessionManager)
+	if !ok {
+		return 0, fmt.Errorf("conversation handler does not support session management")
+	}
+	if e.format != FormatRaw && e.format != FormatFineTune {
+		return 0, fmt.Errorf("unsupported format: %s", e.format)
+	}
+
+	enc := json.NewEncoder(w)
+	filter := conversation.SessionFilter{UserID: e.filter.UserID, Channel: e.filter.Channel, Since: e.filter.Since, Until: e.filter.Until}
+	page := conversation.Page{Limit: batchSize}
+	count := 0
+	for {
+		sessions, err := scanner.ScanSessions(ctx, filter, page)
+		if err != nil {
+			return count, err
+		}
+		for _, s := range sessions {
+			msgs, err := listMessages(ctx, sm, s)
+			if err != nil {
+				return count, err
+			}
+			var record any = Record{Session: s, Messages: msgs}
+			if e.format == FormatFineTune {
+				ft := e.fineTune(s, msgs)
+				if len(ft.Messages) == 0 {
+					continue
+				}
+				record = ft
+			}
+			if err := enc.Encode(record); err != nil {
+				return count, fmt.Errorf("write session %d failed: %w", s.ID, err)
+			}
+			count++
+		}
+		if len(sessions) < page.Limit {
+			return count, nil
+		}
+		last := sessions[len(sessions)-1]
+		page.After = &conversation.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func (e *Exporter) fineTune(s *conversation.Session, msgs []*conversation.Message) FineTuneRecord {
+	byId := make(map[int]*conversation.Message, len(msgs))
+	for _, m := range msgs {
+		byId[m.ID] = m
+	}
+
+	result := make([]openai.ChatCompletionMessage, 0, len(msgs)+1)
+	if e.system != "" {
+		result = append(result, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: e.system})
+	}
+	turns := 0
+	for _, m := range msgs {
+		// 群聊会话的 UserID 为群Id，提问来自不同的成员，回复的发送方为渠道
+		if (s.GroupChat && m.FromUserID == s.Channel) || (!s.GroupChat && m.FromUserID != s.UserID) {
+			continue
+		}
+		reply, ok := byId[m.SpouseID]
+		if !ok || m.Shredded || reply.Shredded {
+			continue
+		}
+		result = append(result,
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: m.Content},
+			openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: reply.Content},
+		)
+		turns++
+	}
+	if turns == 0 {
+		return FineTuneRecord{}
+	}
+	return FineTuneRecord{Messages: result}
+}
+
+func listMessages(ctx context.Context, sm conversation.SessionManager, s *conversation.Session) ([]*conversation.Message, error) {
+	result := make([]*conversation.Message, 0)
+	page := conversation.Page{Limit: batchSize}
+	for {
+		msgs, err := sm.ListMessages(ctx, s, page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, msgs...)
+		if len(msgs) < page.Limit {
+			return result, nil
+		}
+		last := msgs[len(msgs)-1]
+		page.After = &conversation.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
@@ -0,0 +1,256 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+)
+
+// 单行数据的最大长度
+const maxLineSize = 16 * 1024 * 1024
+
+type Importer struct {
+	ch      conversation.Handler
+	format  Format
+	userId  string
+	channel string
+}
+
+// NewImporter 创建导入器，会话和消息通过 conversation.Handler 写入，适用于任意后端。
+// 导入的会话和消息使用导入时的创建时间
+func NewImporter(ch conversation.Handler) *Importer {
+	return &Importer{ch: ch, format: FormatRaw}
+}
+
+func (i *Importer) WithFormat(format Format) *Importer {
+	i.format = format
+	return i
+}
+
+// WithUser 微调格式不包含用户和渠道信息，导入时使用指定的用户和渠道
+func (i *Importer) WithUser(userId, channel string) *Importer {
+	i.userId = userId
+	i.channel = channel
+	return i
+}
+
+// Import 从 r 中读取会话并写入后端，返回导入的会话数
+func (i *Importer) Import(ctx context.Context, r io.Reader) (int, error) {
+	if i.format == FormatFineTune && (i.userId == "" || i.channel == "") {
+		return 0, fmt.Errorf("user and channel are required to import fine-tune data")
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	count, line := 0, 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var err error
+		switch i.format {
+		case FormatRaw:
+			var record Record
+			if err = json.Unmarshal(scanner.Bytes(), &record); err == nil {
+				err = i.importRecord(ctx, record)
+			}
+		case FormatFineTune:
+			var record FineTuneRecord
+			if err = json.Unmarshal(scanner.Bytes(), &record); err == nil {
+				err = i.importFineTune(ctx, record)
+			}
+		default:
+			return count, fmt.Errorf("unsupported format: %s", i.format)
+		}
+		if err != nil {
+			return count, fmt.Errorf("import line %d failed: %w", line, err)
+		}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, fmt.Errorf("read input failed: %w", err)
+	}
+	return count, nil
+}
+
+// importRecord 按原始顺序写入消息，配对消息通过旧Id映射到新Id。写入失败时删除已导入的部分
+func (i *Importer) importRecord(ctx context.Context, record Record) error {
+	if record.Session == nil {
+		return fmt.Errorf("missing session")
+	}
+	channel := record.Session.Channel
+	if channel == "" {
+		channel = conversation.DefaultChannel
+	}
+	var session *conversation.Session
+	var err error
+	if record.Session.GroupChat {
+		session, err = i.createGroupSession(ctx, record.Session.UserID, channel, record.Session.Participants)
+	} else {
+		session, err = i.createSession(ctx, record.Session.UserID, channel)
+	}
+	if err != nil {
+		return err
+	}
+
+	imported := make(map[int]*conversation.Message, len(record.Messages))
+	for _, m := range record.Messages {
+		var created *conversation.Message
+		if spouse, ok := imported[m.SpouseID]; ok && m.SpouseID != 0 {
+			created, err = i.ch.CreateSpouseMessage(ctx, session, m.FromUserID, m.ToUserID, m.Content, spouse)
+		} else {
+			created, err = i.ch.CreateMessage(ctx, session, m.FromUserID, m.ToUserID, m.Content)
+		}
+		if err != nil {
+			return i.discard(ctx, session, imported, err)
+		}
+		imported[m.ID] = created
+		if len(m.Citations) > 0 {
+			if cs, ok := i.ch.(conversation.CitationStore); ok {
+				if err := cs.SaveCitations(ctx, created, m.Citations); err != nil {
+					return i.discard(ctx, session, imported, err)
+				}
+			}
+		}
+		if len(m.Parts) > 0 {
+			if ps, ok := i.ch.(conversation.ContentPartStore); ok {
+				if err := ps.SaveContentParts(ctx, session, created, m.Parts); err != nil {
+					return i.discard(ctx, session, imported, err)
+				}
+			}
+		}
+	}
+
+	// 恢复标题
+	if sm, ok := i.ch.(conversation.SessionManager); ok && record.Session.Title != "" {
+		if err := sm.RenameSession(ctx, session.UserID, session.ID, record.Session.Title); err != nil {
+			return i.discard(ctx, session, imported, err)
+		}
+	}
+	return nil
+}
+
+func (i *Importer) importFineTune(ctx context.Context, record FineTuneRecord) error {
+	session, err := i.createSession(ctx, i.userId, i.channel)
+	if err != nil {
+		return err
+	}
+
+	imported := make(map[int]*conversation.Message, len(record.Messages))
+	var question *conversation.Message
+	for _, m := range record.Messages {
+		var created *conversation.Message
+		switch m.Role {
+		case openai.ChatMessageRoleUser:
+			created, err = i.ch.CreateMessage(ctx, session, i.userId, i.channel, m.Content)
+			question = created
+		case openai.ChatMessageRoleAssistant:
+			if question == nil {
+				continue
+			}
+			created, err = i.ch.CreateSpouseMessage(ctx, session, i.channel, i.userId, m.Content, question)
+			question = nil
+		}
+		if err != nil {
+			return i.discard(ctx, session, imported, err)
+		}
+		if created != nil {
+			imported[created.ID] = created
+		}
+	}
+	return nil
+}
+
+// createSession 创建导入的会话。导入的会话总是关闭状态，不影响用户当前进行中的会话，需要时可以通过 ReopenSession 重新开启
+func (i *Importer) createSession(ctx context.Context, userId, channel string) (*conversation.Session, error) {
+	ch, scoped := i.ch.(conversation.ChannelHandler)
+
+	var active *conversation.Session
+	var err error
+	if scoped {
+		active, err = ch.GetLatestActiveChannelSession(ctx, userId, channel)
+	} else {
+		active, err = i.ch.GetLatestActiveSession(ctx, userId)
+	}
+	if err != nil {
+		active = nil
+	}
+
+	var session *conversation.Session
+	if scoped {
+		session, err = ch.CreateChannelSession(ctx, userId, channel)
+	} else {
+		session, err = i.ch.CreateSession(ctx, userId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return i.deactivate(ctx, session, active)
+}
+
+// createGroupSession 创建导入的群聊会话并恢复参与者，会话后端需要实现 conversation.GroupHandler
+func (i *Importer) createGroupSession(ctx context.Context, groupId, channel string, participants []string) (*conversation.Session, error) {
+	gh, ok := i.ch.(conversation.GroupHandler)
+	if !ok {
+		return nil, fmt.Errorf("conversation handler does not support group chat")
+	}
+	active, err := gh.GetLatestActiveGroupSession(ctx, groupId, channel)
+	if err != nil {
+		active = nil
+	}
+	session, err := gh.CreateGroupSession(ctx, groupId, channel)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participants {
+		if err := gh.AddParticipant(ctx, session, p); err != nil {
+			return nil, i.discard(ctx, session, nil, err)
+		}
+	}
+	return i.deactivate(ctx, session, active)
+}
+
+// deactivate 关闭导入的会话，并重新开启导入前进行中的会话 active
+func (i *Importer) deactivate(ctx context.Context, session, active *conversation.Session) (*conversation.Session, error) {
+	sm, ok := i.ch.(conversation.SessionManager)
+	if !ok {
+		return session, nil
+	}
+	if err := sm.CloseSessionByID(ctx, session.UserID, session.ID); err != nil {
+		return nil, err
+	}
+	session.Status = false
+	// 创建会话时后端可能关闭了之前的会话，重新开启
+	if active != nil {
+		if err := sm.ReopenSession(ctx, active.UserID, active.ID); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// discard 删除导入失败的会话及其中已写入的消息，返回原始错误
+func (i *Importer) discard(ctx context.Context, session *conversation.Session, imported map[int]*conversation.Message, cause error) error {
+	sm, ok := i.ch.(conversation.SessionManager)
+	if !ok {
+		return cause
+	}
+	for _, m := range imported {
+		// 删除提问时会一并删除其回复，回复可能已经不存在
+		if err := sm.DeleteMessage(ctx, session, m.ID); err != nil && !errors.Is(err, conversation.ErrNotFound) {
+			return fmt.Errorf("%w (discard session %d failed: %s)", cause, session.ID, err)
+		}
+	}
+	if err := sm.DeleteSession(ctx, session.UserID, session.ID); err != nil {
+		return fmt.Errorf("%w (discard session %d failed: %s)", cause, session.ID, err)
+	}
+	return cause
+}
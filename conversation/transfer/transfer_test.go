@@ -0,0 +1,85 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+)
+
+func TestGroupSessionRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	src := memory.New()
+	s, err := src.CreateGroupSession(ctx, "group-1", "slack")
+	if err != nil {
+		t.Fatalf("CreateGroupSession() error = %v", err)
+	}
+	for _, p := range []string{"alice", "bob"} {
+		if err := src.AddParticipant(ctx, s, p); err != nil {
+			t.Fatalf("AddParticipant() error = %v", err)
+		}
+	}
+	q, _ := src.CreateMessage(ctx, s, "alice", "slack", "京都还是大阪？")
+	src.CreateSpouseMessage(ctx, s, "slack", "alice", "京都", q)
+	src.CreateMessage(ctx, s, "bob", "slack", "我想吃章鱼烧")
+
+	var raw bytes.Buffer
+	if n, err := NewExporter(src).Export(ctx, &raw); err != nil || n != 1 {
+		t.Fatalf("Export() = %d, %v", n, err)
+	}
+	dst := memory.New()
+	if n, err := NewImporter(dst).Import(ctx, bytes.NewReader(raw.Bytes())); err != nil || n != 1 {
+		t.Fatalf("Import() = %d, %v", n, err)
+	}
+
+	sessions, err := dst.ScanSessions(ctx, conversation.SessionFilter{}, conversation.Page{})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("ScanSessions() = %v, %v", sessions, err)
+	}
+	got := sessions[0]
+	if !got.GroupChat || got.UserID != "group-1" || got.Channel != "slack" || strings.Join(got.Participants, ",") != "alice,bob" {
+		t.Fatalf("session = %+v", got)
+	}
+	msgs, err := dst.ListMessages(ctx, got, conversation.Page{})
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	var attribution []string
+	for _, m := range msgs {
+		attribution = append(attribution, m.FromUserID+"->"+m.ToUserID+": "+m.Content)
+	}
+	want := "alice->slack: 京都还是大阪？\nslack->alice: 京都\nbob->slack: 我想吃章鱼烧"
+	if strings.Join(attribution, "\n") != want {
+		t.Fatalf("messages = %q, want %q", attribution, want)
+	}
+
+	// 微调格式包含群成员的提问
+	var ft bytes.Buffer
+	if n, err := NewExporter(src).WithFormat(FormatFineTune).Export(ctx, &ft); err != nil || n != 1 {
+		t.Fatalf("Export() = %d, %v", n, err)
+	}
+	var record FineTuneRecord
+	if err := json.Unmarshal(ft.Bytes(), &record); err != nil {
+		t.Fatalf("unmarshal fine-tune record failed: %v", err)
+	}
+	if len(record.Messages) != 2 || record.Messages[0].Content != "京都还是大阪？" || record.Messages[1].Content != "京都" {
+		t.Fatalf("fine-tune messages = %+v", record.Messages)
+	}
+}
+
+func TestImportGroupSessionUnsupported(t *testing.T) {
+	line := `{"session":{"user_id":"group-1","channel":"slack","group_chat":true,"participants":["alice"]},"messages":[]}`
+	_, err := NewImporter(groupless{memory.New()}).Import(context.Background(), strings.NewReader(line))
+	if err == nil || !strings.Contains(err.Error(), "group chat") {
+		t.Fatalf("Import() error = %v, want group chat unsupported", err)
+	}
+}
+
+// groupless 只实现 conversation.Handler 的会话后端
+type groupless struct {
+	conversation.Handler
+}
@@ -0,0 +1,117 @@
+package xgpt3
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+)
+
+// EventType 会话事件类型
+type EventType string
+
+const (
+	// 创建会话
+	EventSessionCreated EventType = "session.created"
+	// 关闭会话
+	EventSessionClosed EventType = "session.closed"
+	// 删除会话，仅通过 SessionManager 删除时触发
+	EventSessionDeleted EventType = "session.deleted"
+	// 保存用户消息
+	EventMessageStored EventType = "message.stored"
+	// 保存回复消息
+	EventReplyStored EventType = "reply.stored"
+	// 请求失败
+	EventRequestFailed EventType = "request.failed"
+)
+
+// Event 会话事件
+type Event struct {
+	// 事件类型
+	Type EventType
+	// 用户Id
+	UserID string
+	// 消息渠道
+	Channel string
+	// 事件相关的会话
+	Session *conversation.Session
+	// 事件相关的消息
+	Message *conversation.Message
+	// token 用量，仅 EventReplyStored 事件可用
+	Usage openai.Usage
+	// 失败原因，仅 EventRequestFailed 事件可用
+	Err error
+	// 事件发生时间
+	Time time.Time
+}
+
+// EventHandler 事件处理函数
+type EventHandler func(ctx context.Context, e Event)
+
+type subscriber struct {
+	handler EventHandler
+	async   bool
+}
+
+type eventBus struct {
+	mu          sync.RWMutex
+	subscribers map[EventType][]subscriber
+}
+
+// Subscribe 订阅事件，处理函数在请求的 goroutine 中同步执行
+func (c *Client) Subscribe(t EventType, h EventHandler) *Client {
+	c.events.subscribe(t, subscriber{handler: h})
+	return c
+}
+
+// SubscribeAsync 订阅事件，处理函数在新的 goroutine 中异步执行
+func (c *Client) SubscribeAsync(t EventType, h EventHandler) *Client {
+	c.events.subscribe(t, subscriber{handler: h, async: true})
+	return c
+}
+
+func (b *eventBus) subscribe(t EventType, s subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers == nil {
+		b.subscribers = make(map[EventType][]subscriber)
+	}
+	b.subscribers[t] = append(b.subscribers[t], s)
+}
+
+func (c *Client) emit(ctx context.Context, e Event) {
+	c.events.mu.RLock()
+	subscribers := c.events.subscribers[e.Type]
+	c.events.mu.RUnlock()
+	if len(subscribers) == 0 {
+		return
+	}
+
+	e.Time = time.Now()
+	for _, s := range subscribers {
+		if !s.async {
+			s.handler(ctx, e)
+			continue
+		}
+		go func(h EventHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					c.logger.Error().Msgf("event %s handler panic: %v", e.Type, r)
+				}
+			}()
+			h(detachedContext{ctx}, e)
+		}(s.handler)
+	}
+}
+
+// detachedContext 保留 ctx 中的值，但不会随请求结束而取消
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
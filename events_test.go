@@ -0,0 +1,43 @@
+package xgpt3_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+)
+
+func TestCloseConversationEvents(t *testing.T) {
+	ctx := context.Background()
+	c, srv := newTestClient(t)
+	srv.Respond(func(r xgpt3test.Request) xgpt3test.Response {
+		return xgpt3test.Response{Content: "你好"}
+	})
+	var mu sync.Mutex
+	var closed []xgpt3.Event
+	c.Subscribe(xgpt3.EventSessionClosed, func(ctx context.Context, e xgpt3.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		closed = append(closed, e)
+	})
+
+	// 没有开启的会话时不触发事件
+	if err := c.CloseConversation(ctx, "alice"); err != nil {
+		t.Fatalf("CloseConversation() error = %v", err)
+	}
+	if len(closed) != 0 {
+		t.Fatalf("events = %+v, want none", closed)
+	}
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if err := c.CloseConversation(ctx, "alice"); err != nil {
+		t.Fatalf("CloseConversation() error = %v", err)
+	}
+	if len(closed) != 1 || closed[0].Session == nil || closed[0].Session.Status || closed[0].UserID != "alice" {
+		t.Fatalf("events = %+v", closed)
+	}
+}
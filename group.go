@@ -0,0 +1,158 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrGroupUnsupported 会话后端没有实现 conversation.GroupHandler
+var ErrGroupUnsupported = errors.New("xgpt3: conversation handler does not support group chat")
+
+// maxNameLength 消息 name 字段的最大长度
+const maxNameLength = 64
+
+type groupContextKey struct{}
+
+// WithGroup 指定请求属于群聊 groupId。群内所有成员共用同一个会话，
+// 历史消息中其他成员的发言以带名字的用户消息呈现，长期记忆也按群保存。
+// 会话后端需要实现 conversation.GroupHandler
+func WithGroup(ctx context.Context, groupId string) context.Context {
+	return context.WithValue(ctx, groupContextKey{}, groupId)
+}
+
+// GroupID 返回请求所属的群聊，未设置时为空
+func GroupID(ctx context.Context) string {
+	groupId, _ := ctx.Value(groupContextKey{}).(string)
+	return groupId
+}
+
+// RecordGroupMessage 保存群聊中不需要回复的发言，作为后续对话的上下文
+func (c *Client) RecordGroupMessage(ctx context.Context, groupId, channel, userId, content string) error {
+	gh, ok := c.ch.(conversation.GroupHandler)
+	if !ok {
+		return ErrGroupUnsupported
+	}
+	session, err := c.getOrCreateGroupSession(ctx, gh, groupId, userId, channel)
+	if err != nil {
+		return err
+	}
+	if c.redactor != nil {
+		content = c.redactor.Redact(content, c.newRedactVault(ctx, userId))
+	}
+	msg, err := c.ch.CreateMessage(ctx, session, userId, channel, content)
+	if err != nil {
+		return fmt.Errorf("create group message failed: %w", err)
+	}
+	c.emit(ctx, Event{Type: EventMessageStored, UserID: userId, Channel: channel, Session: session, Message: msg})
+	return nil
+}
+
+// getOrCreateGroupSession 获取或创建群聊会话，并将发言的用户加入参与者列表
+func (c *Client) getOrCreateGroupSession(ctx context.Context, gh conversation.GroupHandler, groupId, userId, channel string) (session *conversation.Session, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.session_lookup", attribute.Bool("xgpt3.session.group", true))
+	defer func() { endSpan(span, err) }()
+
+	session, err = gh.GetLatestActiveGroupSession(ctx, groupId, channel)
+	if err != nil {
+		session, err = gh.CreateGroupSession(ctx, groupId, channel)
+		if err != nil {
+			return nil, fmt.Errorf("create group session failed: %w", err)
+		}
+		span.SetAttributes(attribute.Bool("xgpt3.session.created", true))
+		c.emit(ctx, Event{Type: EventSessionCreated, UserID: groupId, Channel: channel, Session: session})
+	}
+	if err := gh.AddParticipant(ctx, session, userId); err != nil {
+		return nil, fmt.Errorf("add participant failed: %w", err)
+	}
+	return session, nil
+}
+
+// listGroupHistory 获取群聊会话的历史消息，不包括本次保存的用户消息。
+// 回复消息的发送方为渠道，其他消息都是成员的发言
+func (c *Client) listGroupHistory(ctx context.Context, session *conversation.Session, current *conversation.Message) (msgs []openai.ChatCompletionMessage, err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.history_load", attribute.Int("xgpt3.history.max_turns", c.maxTurn))
+	defer func() { endSpan(span, err) }()
+
+	gh, ok := c.ch.(conversation.GroupHandler)
+	if !ok {
+		return nil, ErrGroupUnsupported
+	}
+	history, err := gh.ListLatestGroupMessages(ctx, session, c.maxTurn*2)
+	if err != nil {
+		return nil, err
+	}
+	msgs = make([]openai.ChatCompletionMessage, 0, len(history))
+	for _, m := range history {
+		if current != nil && m.ID == current.ID {
+			continue
+		}
+		if m.FromUserID == session.Channel {
+			msgs = append(msgs, historyMessage(openai.ChatMessageRoleAssistant, m))
+		} else {
+			ccm := historyMessage(openai.ChatMessageRoleUser, m)
+			ccm.Name = participantName(m.FromUserID)
+			msgs = append(msgs, ccm)
+		}
+	}
+	span.SetAttributes(attribute.Int("xgpt3.history.messages", len(msgs)))
+	return msgs, nil
+}
+
+// listGroupTurns 获取群聊会话中已回复的对话，按提问、回复的顺序排列
+func (c *Client) listGroupTurns(ctx context.Context, session *conversation.Session, turns int) ([]*conversation.Message, error) {
+	gh, ok := c.ch.(conversation.GroupHandler)
+	if !ok {
+		return nil, ErrGroupUnsupported
+	}
+	history, err := gh.ListLatestGroupMessages(ctx, session, turns*4)
+	if err != nil {
+		return nil, err
+	}
+	questions := make(map[int]*conversation.Message, len(history))
+	for _, m := range history {
+		questions[m.ID] = m
+	}
+	result := make([]*conversation.Message, 0)
+	for _, m := range history {
+		if q, ok := questions[m.SpouseID]; ok && m.SpouseID != 0 && m.FromUserID == session.Channel {
+			result = append(result, q, m)
+		}
+	}
+	if len(result) > turns*2 {
+		result = result[len(result)-turns*2:]
+	}
+	return result, nil
+}
+
+// participantName 将用户Id 转换为消息 name 字段允许的格式：字母、数字、下划线和连字符，不超过 64 个字符
+func participantName(userId string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, userId)
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
+
+// nameLastUserMessage 为请求中最后一条用户消息设置发言者的名字，不修改原来的消息列表
+func nameLastUserMessage(msgs []openai.ChatCompletionMessage, userId string) []openai.ChatCompletionMessage {
+	msgs = append([]openai.ChatCompletionMessage{}, msgs...)
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == openai.ChatMessageRoleUser {
+			msgs[i].Name = participantName(userId)
+			break
+		}
+	}
+	return msgs
+}
@@ -0,0 +1,94 @@
+package xgpt3_test
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestGroupChatAttribution(t *testing.T) {
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	ch := memory.New()
+	c := xgpt3.NewClient(srv.Client(), ch)
+	srv.Enqueue(xgpt3test.Response{Content: "京都的红叶更好看"}, xgpt3test.Response{Content: "那就去京都"})
+
+	ctx := xgpt3.WithGroup(context.Background(), "group-1")
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("alice", "京都还是大阪？"), "slack"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if err := c.RecordGroupMessage(ctx, "group-1", "slack", "carol", "我想吃章鱼烧"); err != nil {
+		t.Fatalf("RecordGroupMessage() error = %v", err)
+	}
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("bob", "我也想看红叶"), "slack"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	// 其他成员的发言以带名字的用户消息出现在历史中，回复作为 assistant 消息
+	var got []string
+	for _, m := range srv.LastRequest().Messages() {
+		got = append(got, m.Role+"("+m.Name+"): "+m.Content)
+	}
+	want := []string{
+		"user(alice): 京都还是大阪？",
+		"assistant(): 京都的红叶更好看",
+		"user(carol): 我想吃章鱼烧",
+		"user(bob): 我也想看红叶",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+
+	// 会话属于群，消息保存发言的成员，回复发给提问的成员
+	session, err := ch.GetLatestActiveGroupSession(ctx, "group-1", "slack")
+	if err != nil {
+		t.Fatalf("GetLatestActiveGroupSession() error = %v", err)
+	}
+	if session.UserID != "group-1" {
+		t.Fatalf("session.UserID = %q, want group-1", session.UserID)
+	}
+	participants := append([]string(nil), session.Participants...)
+	sort.Strings(participants)
+	if strings.Join(participants, ",") != "alice,bob,carol" {
+		t.Fatalf("participants = %v", participants)
+	}
+	msgs, err := ch.ListLatestGroupMessages(ctx, session, 10)
+	if err != nil {
+		t.Fatalf("ListLatestGroupMessages() error = %v", err)
+	}
+	var stored []string
+	for _, m := range msgs {
+		stored = append(stored, m.FromUserID+"->"+m.ToUserID)
+	}
+	wantStored := "alice->slack,slack->alice,carol->slack,bob->slack,slack->bob"
+	if strings.Join(stored, ",") != wantStored {
+		t.Fatalf("stored = %v, want %s", stored, wantStored)
+	}
+}
+
+func TestGroupParticipantName(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := xgpt3.WithGroup(context.Background(), "group-1")
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("U0 李雷@example.com", "你好"), "slack"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("bob", "你好"), "slack"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	var first openai.ChatCompletionMessage
+	for _, m := range srv.LastRequest().Messages() {
+		if m.Role == openai.ChatMessageRoleUser {
+			first = m
+			break
+		}
+	}
+	if first.Name != "U0____example_com" {
+		t.Fatalf("name = %q", first.Name)
+	}
+}
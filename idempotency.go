@@ -0,0 +1,162 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrDuplicateRequest 幂等键对应的请求已经回复过。
+// 只有 CreateChatCompletion 会直接返回保存的回复，其他接口遇到重复请求时返回该错误
+var ErrDuplicateRequest = errors.New("xgpt3: duplicate request")
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey 为请求设置幂等键，通常是聊天平台的消息Id，用于吸收平台的重试请求。
+// 同一用户携带相同幂等键的请求只会调用一次模型：处理中的重复请求等待首个请求的结果，
+// 已回复的重复请求直接返回保存的回复。会话后端需要实现 conversation.IdempotencyStore
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKey 返回请求的幂等键，未设置时为空
+func IdempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+// inflightCall 处理中的请求，重复请求等待其结果
+type inflightCall struct {
+	done chan struct{}
+	resp openai.ChatCompletionResponse
+	err  error
+}
+
+// InflightRequests 按 (用户, 幂等键) 合并进程内处理中的请求。
+// 多个 Client 共用同一个会话后端时 (例如每个 API Key 一个 Client)，需要通过 WithInflightRequests 共用同一个 InflightRequests，
+// 否则重试请求落到另一个 Client 时会再次调用模型
+type InflightRequests struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+func NewInflightRequests() *InflightRequests {
+	return &InflightRequests{calls: make(map[string]*inflightCall)}
+}
+
+// WithInflightRequests 与其他 Client 共用处理中的请求，重复请求等待任意 Client 中首个请求的结果
+func (c *Client) WithInflightRequests(r *InflightRequests) *Client {
+	c.inflight = r
+	return c
+}
+
+func (f *InflightRequests) do(ctx context.Context, key string, fn func() (openai.ChatCompletionResponse, error)) (openai.ChatCompletionResponse, error) {
+	f.mu.Lock()
+	if call, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		select {
+		case <-call.done:
+			return copyChatResponse(call.resp), call.err
+		case <-ctx.Done():
+			return openai.ChatCompletionResponse{}, ctx.Err()
+		}
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	f.calls[key] = call
+	f.mu.Unlock()
+
+	call.resp, call.err = fn()
+	f.mu.Lock()
+	delete(f.calls, key)
+	f.mu.Unlock()
+	close(call.done)
+	return copyChatResponse(call.resp), call.err
+}
+
+func copyChatResponse(resp openai.ChatCompletionResponse) openai.ChatCompletionResponse {
+	resp.Choices = append([]openai.ChatCompletionChoice(nil), resp.Choices...)
+	return resp
+}
+
+// idempotentChatCompletion 按幂等键去重的对话请求
+func (c *Client) idempotentChatCompletion(ctx context.Context, request openai.ChatCompletionRequest, channel, key string) (openai.ChatCompletionResponse, error) {
+	return c.inflight.do(ctx, request.User+"\x00"+key, func() (openai.ChatCompletionResponse, error) {
+		reply, err := c.storedReply(ctx, request.User, key)
+		if err != nil {
+			return openai.ChatCompletionResponse{}, c.failed(ctx, requestKindChat, StagePreprocess, request.User, channel, err)
+		}
+		if reply == nil {
+			resp, err := c.createChatCompletion(ctx, request, channel)
+			if !errors.Is(err, ErrDuplicateRequest) {
+				return resp, err
+			}
+			// 其他实例同时处理了相同的请求，已经回复时返回保存的回复
+			reply, rerr := c.storedReply(ctx, request.User, key)
+			if rerr != nil || reply == nil {
+				return resp, err
+			}
+			return c.replayReply(ctx, request, key, reply), nil
+		}
+		return c.replayReply(ctx, request, key, reply), nil
+	})
+}
+
+// replayReply 返回幂等键对应的已保存回复
+func (c *Client) replayReply(ctx context.Context, request openai.ChatCompletionRequest, key string, reply *conversation.Message) openai.ChatCompletionResponse {
+	c.logger.Debug().Msgf("User: %s, replay stored reply %d for idempotency key %s", request.User, reply.ID, key)
+	// 重新脱敏请求得到相同的占位符，用于还原保存的回复
+	vault := c.redactChatRequest(ctx, &request)
+	return openai.ChatCompletionResponse{
+		Object:  "chat.completion",
+		Created: reply.CreatedAt.Unix(),
+		Model:   request.Model,
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: vault.Restore(reply.Content),
+			},
+			FinishReason: openai.FinishReasonStop,
+		}},
+	}
+}
+
+// storedReply 获取幂等键对应的回复，不存在时返回空
+func (c *Client) storedReply(ctx context.Context, userId, key string) (*conversation.Message, error) {
+	s, ok := c.ch.(conversation.IdempotencyStore)
+	if !ok {
+		return nil, nil
+	}
+	_, reply, err := s.GetMessageByKey(ctx, userId, key)
+	if errors.Is(err, conversation.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get message by idempotency key failed: %w", err)
+	}
+	return reply, nil
+}
+
+// createKeyedMessage 保存带幂等键的用户消息。之前的请求已经保存了用户消息但没有回复时复用该消息，
+// 已经回复时返回 ErrDuplicateRequest。其他实例同时保存了相同幂等键的消息时，请求由其他实例处理，
+// 同样返回 ErrDuplicateRequest，CreateChatCompletion 重新查询并返回保存的回复
+func (c *Client) createKeyedMessage(ctx context.Context, s conversation.IdempotencyStore, session *conversation.Session, fromUserId, toUserId, content, key string) (*conversation.Message, error) {
+	msg, reply, err := s.GetMessageByKey(ctx, fromUserId, key)
+	if errors.Is(err, conversation.ErrNotFound) {
+		msg, err = s.CreateMessageWithKey(ctx, session, fromUserId, toUserId, content, key)
+		if errors.Is(err, conversation.ErrDuplicateKey) {
+			return nil, fmt.Errorf("idempotency key %s: %v: %w", key, err, ErrDuplicateRequest)
+		}
+		return msg, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if reply != nil {
+		return nil, fmt.Errorf("idempotency key %s: %w", key, ErrDuplicateRequest)
+	}
+	return msg, nil
+}
@@ -0,0 +1,202 @@
+package xgpt3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+)
+
+func chatRequests(srv *xgpt3test.Server) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Chat != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Enqueue(xgpt3test.Response{Content: "第一次的回复"})
+	ctx := xgpt3.WithIdempotencyKey(context.Background(), "msg-1")
+
+	first, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	retry, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+	if err != nil {
+		t.Fatalf("retry CreateChatCompletion() error = %v", err)
+	}
+	if got, want := retry.Choices[0].Message.Content, first.Choices[0].Message.Content; got != want {
+		t.Fatalf("replayed reply = %q, want %q", got, want)
+	}
+	if n := chatRequests(srv); n != 1 {
+		t.Fatalf("model called %d times, want 1", n)
+	}
+
+	// 流式接口遇到已回复的幂等键时返回错误
+	req := chatRequest("alice", "你好")
+	req.Stream = true
+	if _, err := c.CreateChatCompletionStream(ctx, req); err == nil {
+		t.Fatal("CreateChatCompletionStream() error = nil, want ErrDuplicateRequest")
+	}
+}
+
+func TestIdempotentInflightAcrossClients(t *testing.T) {
+	srv := xgpt3test.NewServer().WithLatency(100 * time.Millisecond)
+	t.Cleanup(srv.Close)
+	srv.Enqueue(xgpt3test.Response{Content: "回复"})
+
+	// 两个 Client 共用会话后端和处理中的请求，模拟每个 API Key 一个 Client
+	handler := memory.New()
+	inflight := xgpt3.NewInflightRequests()
+	clients := []*xgpt3.Client{
+		xgpt3.NewClient(srv.Client(), handler).WithInflightRequests(inflight),
+		xgpt3.NewClient(srv.Client(), handler).WithInflightRequests(inflight),
+	}
+	ctx := xgpt3.WithIdempotencyKey(context.Background(), "msg-1")
+
+	var wg sync.WaitGroup
+	replies := make([]string, len(clients))
+	errs := make([]error, len(clients))
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c *xgpt3.Client) {
+			defer wg.Done()
+			resp, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+			errs[i] = err
+			if err == nil {
+				replies[i] = resp.Choices[0].Message.Content
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i := range clients {
+		if errs[i] != nil || replies[i] != "回复" {
+			t.Fatalf("client %d reply = %q, err = %v", i, replies[i], errs[i])
+		}
+	}
+	if n := chatRequests(srv); n != 1 {
+		t.Fatalf("model called %d times, want 1", n)
+	}
+
+	session, err := handler.GetLatestActiveSession(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetLatestActiveSession() error = %v", err)
+	}
+	msgs, err := handler.ListMessages(context.Background(), session, conversation.Page{})
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("stored %d messages, want a question and a reply", len(msgs))
+	}
+}
+
+func TestIdempotentRetryAfterFailure(t *testing.T) {
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	handler := memory.New()
+	c := xgpt3.NewClient(srv.Client(), handler)
+	srv.Enqueue(
+		xgpt3test.Response{StatusCode: http.StatusInternalServerError, ErrorMessage: "server error", ErrorType: "server_error"},
+		xgpt3test.Response{Content: "重试后的回复"},
+	)
+	ctx := xgpt3.WithIdempotencyKey(context.Background(), "msg-1")
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好")); err == nil {
+		t.Fatal("CreateChatCompletion() error = nil")
+	}
+	// 首次请求失败时没有回复，重试的请求复用已保存的用户消息并再次调用模型
+	resp, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+	if err != nil {
+		t.Fatalf("retry CreateChatCompletion() error = %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "重试后的回复" {
+		t.Fatalf("reply = %q", got)
+	}
+
+	session, err := handler.GetLatestActiveSession(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetLatestActiveSession() error = %v", err)
+	}
+	msgs, err := handler.ListMessages(context.Background(), session, conversation.Page{})
+	if err != nil {
+		t.Fatalf("ListMessages() error = %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("stored %d messages, want a question and a reply", len(msgs))
+	}
+}
+
+// staleStore 模拟另一个实例：前 stale 次按幂等键查询时消息还没有保存
+type staleStore struct {
+	*memory.ConversationHandler
+	mu    sync.Mutex
+	stale int
+}
+
+func (s *staleStore) GetMessageByKey(ctx context.Context, userId, key string) (*conversation.Message, *conversation.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stale > 0 {
+		s.stale--
+		return nil, nil, conversation.ErrNotFound
+	}
+	return s.ConversationHandler.GetMessageByKey(ctx, userId, key)
+}
+
+func TestIdempotentRaceAcrossInstances(t *testing.T) {
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Enqueue(xgpt3test.Response{Content: "其他实例的回复"})
+	handler := memory.New()
+	ctx := xgpt3.WithIdempotencyKey(context.Background(), "msg-1")
+
+	// 其他实例已经保存了用户消息和回复
+	other := xgpt3.NewClient(srv.Client(), handler)
+	if _, err := other.CreateChatCompletion(ctx, chatRequest("alice", "你好")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	// 本实例查询时还没有看到其他实例保存的消息，保存时遇到幂等键冲突
+	c := xgpt3.NewClient(srv.Client(), &staleStore{ConversationHandler: handler, stale: 2})
+	resp, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好"))
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "其他实例的回复" {
+		t.Fatalf("reply = %q", got)
+	}
+	if n := chatRequests(srv); n != 1 {
+		t.Fatalf("model called %d times, want 1", n)
+	}
+
+	// 其他实例尚未回复时返回 ErrDuplicateRequest
+	pending := xgpt3.WithIdempotencyKey(context.Background(), "msg-2")
+	session, err := handler.GetLatestActiveChannelSession(ctx, "alice", conversation.DefaultChannel)
+	if err != nil {
+		t.Fatalf("GetLatestActiveChannelSession() error = %v", err)
+	}
+	if _, err := handler.CreateMessageWithKey(ctx, session, "alice", "assistant", "在吗", "msg-2"); err != nil {
+		t.Fatalf("CreateMessageWithKey() error = %v", err)
+	}
+	c = xgpt3.NewClient(srv.Client(), &staleStore{ConversationHandler: handler, stale: 2})
+	if _, err := c.CreateChatCompletion(pending, chatRequest("alice", "在吗")); !errors.Is(err, xgpt3.ErrDuplicateRequest) {
+		t.Fatalf("CreateChatCompletion() error = %v, want ErrDuplicateRequest", err)
+	}
+	if n := chatRequests(srv); n != 1 {
+		t.Fatalf("model called %d times, want 1", n)
+	}
+}
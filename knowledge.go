@@ -0,0 +1,79 @@
+package xgpt3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/knowledge"
+	"github.com/sashabaranov/go-openai"
+)
+
+const knowledgePrompt = "请参考以下资料回答用户的问题。使用资料中的内容时，请在句末用 [编号] 标注引用来源：\n"
+
+// WithKnowledgeBase 开启知识库检索，相关资料作为带引用编号的系统消息注入上下文
+func (c *Client) WithKnowledgeBase(kb *knowledge.KnowledgeBase) *Client {
+	c.knowledge = kb
+	return c
+}
+
+// retrieveKnowledge 检索与用户问题相关的资料，返回的内容长度不超过上下文长度的一定比例
+func (c *Client) retrieveKnowledge(ctx context.Context, request *openai.ChatCompletionRequest) (string, []conversation.Citation) {
+	if c.knowledge == nil {
+		return "", nil
+	}
+	question := lastUserContent(request.Messages)
+	if question == "" {
+		return "", nil
+	}
+
+	ctx, span := c.startSpan(ctx, "xgpt3.knowledge_retrieval")
+	defer span.End()
+
+	chunks, err := c.knowledge.Search(ctx, question)
+	if err != nil {
+		c.logger.Warn().Msgf("Search knowledge base failed: %s", err)
+		return "", nil
+	}
+
+	budget := int(float64(c.maxCtxLength) * c.knowledge.TokenShare())
+	var (
+		b         strings.Builder
+		citations []conversation.Citation
+	)
+	b.WriteString(knowledgePrompt)
+	for _, chunk := range chunks {
+		index := len(citations) + 1
+		source := chunk.Source
+		if chunk.Heading != "" {
+			source = fmt.Sprintf("%s > %s", source, chunk.Heading)
+		}
+		text := fmt.Sprintf("[%d] 来源：%s\n%s\n\n", index, source, chunk.Content)
+		if b.Len()+len(text) > budget {
+			break
+		}
+		b.WriteString(text)
+		citations = append(citations, conversation.Citation{
+			Index:       index,
+			Source:      chunk.Source,
+			Heading:     chunk.Heading,
+			EmbeddingID: chunk.ID,
+			Content:     chunk.Content,
+			Score:       chunk.Score,
+		})
+	}
+	if len(citations) == 0 {
+		return "", nil
+	}
+	return b.String(), citations
+}
+
+func (c *Client) saveCitations(ctx context.Context, m *conversation.Message, citations []conversation.Citation) error {
+	m.Citations = citations
+	cs, ok := c.ch.(conversation.CitationStore)
+	if !ok {
+		return nil
+	}
+	return cs.SaveCitations(ctx, m, citations)
+}
@@ -0,0 +1,293 @@
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/fanchunke/xgpt3/vector"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	namespacePrefix    = "knowledge"
+	defaultModel       = openai.AdaEmbeddingV2
+	defaultChunkSize   = 800
+	defaultTopK        = 4
+	defaultTokenShare  = 0.3
+	embeddingBatchSize = 64
+	sourceKey          = "source"
+	headingKey         = "heading"
+)
+
+// ErrDeleteUnsupported 存储没有实现 conversation.EmbeddingDeleter，不能重新导入资料
+var ErrDeleteUnsupported = errors.New("knowledge: store does not support deleting embeddings")
+
+// Chunk 资料片段
+type Chunk struct {
+	// 片段的向量Id
+	ID int
+	// 资料来源，通常是文件路径
+	Source string
+	// 片段所在章节
+	Heading string
+	// 片段内容
+	Content string
+	// 与问题的相似度，仅检索结果可用
+	Score float32
+}
+
+// Embedder 计算文本向量，*openai.Client 和 provider.Provider 都满足该接口
+type Embedder = provider.Embedder
+
+// KnowledgeBase 知识库。将 Markdown 或纯文本资料切分、向量化后保存，并根据问题检索相关片段
+type KnowledgeBase struct {
+	client    Embedder
+	store     conversation.EmbeddingStore
+	name      string
+	model     openai.EmbeddingModel
+	chunkSize int
+	topK      int
+	minScore  float32
+	share     float64
+
+	mu     sync.Mutex
+	index  *vector.Index
+	chunks map[int]*Chunk
+}
+
+func New(client Embedder, store conversation.EmbeddingStore, name string) *KnowledgeBase {
+	return &KnowledgeBase{
+		client:    client,
+		store:     store,
+		name:      name,
+		model:     defaultModel,
+		chunkSize: defaultChunkSize,
+		topK:      defaultTopK,
+		share:     defaultTokenShare,
+	}
+}
+
+// WithModel 设置 Embeddings 模型
+func (kb *KnowledgeBase) WithModel(model openai.EmbeddingModel) *KnowledgeBase {
+	kb.model = model
+	return kb
+}
+
+// WithChunkSize 设置片段的最大长度
+func (kb *KnowledgeBase) WithChunkSize(n int) *KnowledgeBase {
+	kb.chunkSize = n
+	return kb
+}
+
+// WithTopK 设置每次检索的片段数量
+func (kb *KnowledgeBase) WithTopK(k int) *KnowledgeBase {
+	kb.topK = k
+	return kb
+}
+
+// WithMinScore 设置片段的最低相似度
+func (kb *KnowledgeBase) WithMinScore(score float32) *KnowledgeBase {
+	kb.minScore = score
+	return kb
+}
+
+// WithTokenShare 设置资料最多占用上下文长度的比例
+func (kb *KnowledgeBase) WithTokenShare(share float64) *KnowledgeBase {
+	kb.share = share
+	return kb
+}
+
+func (kb *KnowledgeBase) TokenShare() float64 {
+	return kb.share
+}
+
+func (kb *KnowledgeBase) namespace() string {
+	return fmt.Sprintf("%s:%s", namespacePrefix, kb.name)
+}
+
+// IngestFile 导入文件。.md 和 .markdown 文件按 Markdown 标题切分，其他文件按纯文本切分
+func (kb *KnowledgeBase) IngestFile(ctx context.Context, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	return kb.ingest(ctx, path, f, ext == ".md" || ext == ".markdown")
+}
+
+// IngestMarkdown 导入 Markdown 资料，返回切分出的片段数量
+func (kb *KnowledgeBase) IngestMarkdown(ctx context.Context, source string, r io.Reader) (int, error) {
+	return kb.ingest(ctx, source, r, true)
+}
+
+// IngestText 导入纯文本资料，返回切分出的片段数量
+func (kb *KnowledgeBase) IngestText(ctx context.Context, source string, r io.Reader) (int, error) {
+	return kb.ingest(ctx, source, r, false)
+}
+
+// ingest 切分、向量化并保存资料。资料已经导入过时，新的片段全部保存后再删除之前的片段，失败时保留之前的片段
+func (kb *KnowledgeBase) ingest(ctx context.Context, source string, r io.Reader, markdown bool) (int, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("read %s failed: %w", source, err)
+	}
+	if err := kb.load(ctx); err != nil {
+		return 0, err
+	}
+
+	// 先计算所有片段的向量，失败时保留之前导入的片段
+	chunks := split(string(b), markdown, kb.chunkSize)
+	vs := make([][]float32, 0, len(chunks))
+	for i := 0; i < len(chunks); i += embeddingBatchSize {
+		end := i + embeddingBatchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+		batch := chunks[i:end]
+		inputs := make([]string, len(batch))
+		for j, c := range batch {
+			inputs[j] = c.embeddingInput()
+		}
+		batchVs, err := kb.embed(ctx, inputs)
+		if err != nil {
+			return 0, fmt.Errorf("embed %s failed: %w", source, err)
+		}
+		vs = append(vs, batchVs...)
+	}
+
+	// 先写入新的片段，全部写入成功后再删除之前的片段，失败时删除已写入的新片段
+	old := kb.sourceChunks(source)
+	d, deletable := kb.store.(conversation.EmbeddingDeleter)
+	if len(old) > 0 && !deletable {
+		return 0, fmt.Errorf("%s already ingested: %w", source, ErrDeleteUnsupported)
+	}
+	created := make([]*conversation.Embedding, 0, len(chunks))
+	for i, c := range chunks {
+		e, err := kb.store.CreateEmbedding(ctx, &conversation.Embedding{
+			Namespace: kb.namespace(),
+			Content:   c.content,
+			Vector:    vs[i],
+			Metadata:  map[string]string{sourceKey: source, headingKey: c.heading},
+		})
+		if err != nil {
+			return 0, kb.discard(ctx, created, fmt.Errorf("save %s chunk failed: %w", source, err))
+		}
+		created = append(created, e)
+	}
+	if len(old) > 0 {
+		if err := d.DeleteEmbeddings(ctx, kb.namespace(), old); err != nil {
+			return 0, kb.discard(ctx, created, fmt.Errorf("delete %s chunks failed: %w", source, err))
+		}
+	}
+
+	kb.mu.Lock()
+	defer kb.mu.Unlock()
+	kb.index.Remove(old...)
+	for _, id := range old {
+		delete(kb.chunks, id)
+	}
+	for _, e := range created {
+		kb.index.Add(e.ID, e.Vector)
+		kb.chunks[e.ID] = toChunk(e)
+	}
+	return len(chunks), nil
+}
+
+// sourceChunks 返回资料之前导入的片段Id
+func (kb *KnowledgeBase) sourceChunks(source string) []int {
+	kb.mu.Lock()
+	defer kb.mu.Unlock()
+
+	ids := make([]int, 0)
+	for id, c := range kb.chunks {
+		if c.Source == source {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// discard 删除导入失败时已写入的片段，返回原始错误
+func (kb *KnowledgeBase) discard(ctx context.Context, created []*conversation.Embedding, cause error) error {
+	d, ok := kb.store.(conversation.EmbeddingDeleter)
+	if !ok || len(created) == 0 {
+		return cause
+	}
+	ids := make([]int, 0, len(created))
+	for _, e := range created {
+		ids = append(ids, e.ID)
+	}
+	if err := d.DeleteEmbeddings(ctx, kb.namespace(), ids); err != nil {
+		return fmt.Errorf("%w (discard new chunks failed: %s)", cause, err)
+	}
+	return cause
+}
+
+// Search 检索与问题相关的片段，按相似度降序排列
+func (kb *KnowledgeBase) Search(ctx context.Context, question string) ([]*Chunk, error) {
+	if err := kb.load(ctx); err != nil {
+		return nil, err
+	}
+	vs, err := kb.embed(ctx, []string{question})
+	if err != nil {
+		return nil, fmt.Errorf("embed question failed: %w", err)
+	}
+
+	kb.mu.Lock()
+	defer kb.mu.Unlock()
+
+	result := make([]*Chunk, 0, kb.topK)
+	for _, r := range kb.index.Search(vs[0], kb.topK) {
+		if r.Score < kb.minScore {
+			break
+		}
+		c := *kb.chunks[r.ID]
+		c.Score = r.Score
+		result = append(result, &c)
+	}
+	return result, nil
+}
+
+// load 首次使用时从存储中加载知识库
+func (kb *KnowledgeBase) load(ctx context.Context) error {
+	kb.mu.Lock()
+	defer kb.mu.Unlock()
+
+	if kb.index != nil {
+		return nil
+	}
+	es, err := kb.store.ListEmbeddings(ctx, kb.namespace())
+	if err != nil {
+		return fmt.Errorf("load knowledge base %s failed: %w", kb.name, err)
+	}
+	kb.index = vector.NewIndex()
+	kb.chunks = make(map[int]*Chunk, len(es))
+	for _, e := range es {
+		kb.index.Add(e.ID, e.Vector)
+		kb.chunks[e.ID] = toChunk(e)
+	}
+	return nil
+}
+
+func (kb *KnowledgeBase) embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	return provider.Embed(ctx, kb.client, kb.model, inputs)
+}
+
+func toChunk(e *conversation.Embedding) *Chunk {
+	return &Chunk{
+		ID:      e.ID,
+		Source:  e.Metadata[sourceKey],
+		Heading: e.Metadata[headingKey],
+		Content: e.Content,
+	}
+}
@@ -0,0 +1,85 @@
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/sashabaranov/go-openai"
+)
+
+// fakeEmbedder 根据输入长度生成向量，duplicate 为 true 时所有结果的 index 都为 0
+type fakeEmbedder struct {
+	duplicate bool
+}
+
+func (f fakeEmbedder) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	req := conv.Convert()
+	var resp openai.EmbeddingResponse
+	for i, input := range req.Input.([]string) {
+		index := i
+		if f.duplicate {
+			index = 0
+		}
+		resp.Data = append(resp.Data, openai.Embedding{Index: index, Embedding: []float32{float32(len(input)), 1}})
+	}
+	return resp, nil
+}
+
+// failingStore 保存 limit 个向量后返回错误
+type failingStore struct {
+	*memory.ConversationHandler
+	limit int
+}
+
+func (s *failingStore) CreateEmbedding(ctx context.Context, e *conversation.Embedding) (*conversation.Embedding, error) {
+	if s.limit == 0 {
+		return nil, errors.New("disk full")
+	}
+	s.limit--
+	return s.ConversationHandler.CreateEmbedding(ctx, e)
+}
+
+func TestReingestFailureKeepsPreviousChunks(t *testing.T) {
+	ctx := context.Background()
+	store := &failingStore{ConversationHandler: memory.New(), limit: -1}
+	kb := New(fakeEmbedder{}, store, "faq").WithChunkSize(20)
+
+	if _, err := kb.IngestText(ctx, "faq.txt", strings.NewReader("旧的答案")); err != nil {
+		t.Fatalf("IngestText() error = %v", err)
+	}
+	store.limit = 1
+	doc := strings.Repeat("新的答案。", 20)
+	if _, err := kb.IngestText(ctx, "faq.txt", strings.NewReader(doc)); err == nil {
+		t.Fatal("IngestText() error = nil, want save failure")
+	}
+
+	// 之前的片段仍然可用，写入一半的新片段被删除
+	es, err := store.ListEmbeddings(ctx, kb.namespace())
+	if err != nil {
+		t.Fatalf("ListEmbeddings() error = %v", err)
+	}
+	if len(es) != 1 || es[0].Content != "旧的答案" {
+		t.Fatalf("stored chunks = %+v", es)
+	}
+	chunks, err := kb.Search(ctx, "答案")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Content != "旧的答案" {
+		t.Fatalf("chunks = %+v", chunks)
+	}
+}
+
+func TestEmbedMissingVector(t *testing.T) {
+	kb := New(fakeEmbedder{duplicate: true}, memory.New(), "faq")
+	if _, err := kb.Search(context.Background(), "答案"); err != nil {
+		t.Fatalf("Search() single input error = %v", err)
+	}
+	if _, err := kb.IngestText(context.Background(), "faq.txt", strings.NewReader(strings.Repeat("答案。", 500))); err == nil {
+		t.Fatal("IngestText() error = nil, want missing embedding")
+	}
+}
@@ -0,0 +1,118 @@
+package knowledge
+
+import (
+	"strings"
+)
+
+type chunk struct {
+	heading string
+	content string
+}
+
+// embeddingInput 向量化时带上章节标题，提高检索准确率
+func (c chunk) embeddingInput() string {
+	if c.heading == "" {
+		return c.content
+	}
+	return c.heading + "\n" + c.content
+}
+
+// split 将资料切分为不超过 size 个字符的片段。Markdown 资料按标题分节，片段不会跨越章节
+func split(text string, markdown bool, size int) []chunk {
+	var (
+		chunks   []chunk
+		headings []string
+		para     []string
+		buf      strings.Builder
+		inFence  bool
+	)
+
+	// 跳级的标题在路径中留有空位，拼接时忽略
+	heading := func() string {
+		names := make([]string, 0, len(headings))
+		for _, h := range headings {
+			if h != "" {
+				names = append(names, h)
+			}
+		}
+		return strings.Join(names, " > ")
+	}
+	flushChunk := func() {
+		if s := strings.TrimSpace(buf.String()); s != "" {
+			chunks = append(chunks, chunk{heading: heading(), content: s})
+		}
+		buf.Reset()
+	}
+	flushPara := func() {
+		p := strings.TrimSpace(strings.Join(para, "\n"))
+		para = para[:0]
+		if p == "" {
+			return
+		}
+		for _, piece := range splitRunes(p, size) {
+			if buf.Len() > 0 && len([]rune(buf.String()))+len([]rune(piece))+2 > size {
+				flushChunk()
+			}
+			if buf.Len() > 0 {
+				buf.WriteString("\n\n")
+			}
+			buf.WriteString(piece)
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if markdown && strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if markdown && !inFence {
+			if level, title, ok := parseHeading(trimmed); ok {
+				flushPara()
+				flushChunk()
+				if level <= len(headings) {
+					headings = headings[:level-1]
+				}
+				for len(headings) < level-1 {
+					headings = append(headings, "")
+				}
+				headings = append(headings, title)
+				continue
+			}
+		}
+		if trimmed == "" && !inFence {
+			flushPara()
+			continue
+		}
+		para = append(para, line)
+	}
+	flushPara()
+	flushChunk()
+	return chunks
+}
+
+func parseHeading(line string) (int, string, bool) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0, "", false
+	}
+	return level, strings.TrimSpace(line[level:]), true
+}
+
+func splitRunes(s string, size int) []string {
+	rs := []rune(s)
+	if size <= 0 || len(rs) <= size {
+		return []string{s}
+	}
+	result := make([]string, 0, len(rs)/size+1)
+	for i := 0; i < len(rs); i += size {
+		end := i + size
+		if end > len(rs) {
+			end = len(rs)
+		}
+		result = append(result, string(rs[i:end]))
+	}
+	return result
+}
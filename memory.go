@@ -0,0 +1,356 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/vector"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	memoryNamespace       = "memory"
+	defaultMemoryTopK     = 3
+	defaultMemoryShare    = 0.2
+	defaultMemoryMaxTurns = 100
+	memoryPrompt          = "以下是与用户过往对话中的相关内容，可以作为参考：\n"
+)
+
+// LongTermMemory 长期记忆。会话关闭后，会话中的消息被向量化保存；
+// 拼接消息时从用户更早的会话中检索相关内容，作为系统消息注入上下文
+type LongTermMemory struct {
+	store    conversation.EmbeddingStore
+	model    openai.EmbeddingModel
+	topK     int
+	share    float64
+	minScore float32
+	maxTurns int
+
+	mu      sync.Mutex
+	indexes map[string]*memoryIndex
+}
+
+type memoryIndex struct {
+	index    *vector.Index
+	snippets map[int]*conversation.Embedding
+}
+
+func NewLongTermMemory(store conversation.EmbeddingStore) *LongTermMemory {
+	return &LongTermMemory{
+		store:    store,
+		model:    defaultEmbeddingModel,
+		topK:     defaultMemoryTopK,
+		share:    defaultMemoryShare,
+		maxTurns: defaultMemoryMaxTurns,
+		indexes:  make(map[string]*memoryIndex),
+	}
+}
+
+// WithModel 设置 Embeddings 模型
+func (m *LongTermMemory) WithModel(model openai.EmbeddingModel) *LongTermMemory {
+	m.model = model
+	return m
+}
+
+// WithTopK 设置每次检索的片段数量
+func (m *LongTermMemory) WithTopK(k int) *LongTermMemory {
+	m.topK = k
+	return m
+}
+
+// WithTokenShare 设置记忆片段最多占用上下文长度的比例
+func (m *LongTermMemory) WithTokenShare(share float64) *LongTermMemory {
+	m.share = share
+	return m
+}
+
+// WithMinScore 设置记忆片段的最低相似度
+func (m *LongTermMemory) WithMinScore(score float32) *LongTermMemory {
+	m.minScore = score
+	return m
+}
+
+// loadIndex 获取用户的记忆索引，首次使用时从存储中加载。加载时不持有锁
+func (m *LongTermMemory) loadIndex(ctx context.Context, userId string) (*memoryIndex, error) {
+	m.mu.Lock()
+	idx, ok := m.indexes[userId]
+	m.mu.Unlock()
+	if ok {
+		return idx, nil
+	}
+
+	es, err := m.store.ListEmbeddings(ctx, memoryNamespaceOf(userId))
+	if err != nil {
+		return nil, err
+	}
+	idx = &memoryIndex{index: vector.NewIndex(), snippets: make(map[int]*conversation.Embedding, len(es))}
+	for _, e := range es {
+		idx.index.Add(e.ID, e.Vector)
+		idx.snippets[e.ID] = e
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// 并发加载时使用先加载完成的索引
+	if loaded, ok := m.indexes[userId]; ok {
+		return loaded, nil
+	}
+	m.indexes[userId] = idx
+	return idx, nil
+}
+
+func (m *LongTermMemory) add(ctx context.Context, e *conversation.Embedding) error {
+	idx, err := m.loadIndex(ctx, e.UserID)
+	if err != nil {
+		return err
+	}
+	e, err = m.store.CreateEmbedding(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	idx.snippets[e.ID] = e
+	m.mu.Unlock()
+	idx.index.Add(e.ID, e.Vector)
+	return nil
+}
+
+// memorized 返回会话中已经保存为记忆的消息Id
+func (m *LongTermMemory) memorized(ctx context.Context, userId string, sessionId int) (map[int]bool, error) {
+	idx, err := m.loadIndex(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[int]bool)
+	for _, e := range idx.snippets {
+		if e.SessionID == sessionId {
+			result[e.MessageID] = true
+		}
+	}
+	return result, nil
+}
+
+// removeSession 删除会话的记忆片段。存储没有实现 conversation.EmbeddingDeleter 时只从索引中移除
+func (m *LongTermMemory) removeSession(ctx context.Context, userId string, sessionId int) error {
+	idx, err := m.loadIndex(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	ids := make([]int, 0)
+	for id, e := range idx.snippets {
+		if e.SessionID == sessionId {
+			ids = append(ids, id)
+			delete(idx.snippets, id)
+		}
+	}
+	m.mu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+	idx.index.Remove(ids...)
+
+	if d, ok := m.store.(conversation.EmbeddingDeleter); ok {
+		return d.DeleteEmbeddings(ctx, memoryNamespaceOf(userId), ids)
+	}
+	return nil
+}
+
+// search 检索用户其他会话中的相关片段
+func (m *LongTermMemory) search(ctx context.Context, userId string, sessionId int, v []float32) ([]*conversation.Embedding, error) {
+	idx, err := m.loadIndex(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]*conversation.Embedding, 0, m.topK)
+	for _, r := range idx.index.Search(v, 0) {
+		if len(result) >= m.topK || r.Score < m.minScore {
+			break
+		}
+		e := idx.snippets[r.ID]
+		if e.SessionID == sessionId {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// forget 丢弃用户在进程内的记忆索引
+func (m *LongTermMemory) forget(userId string) {
+	m.mu.Lock()
+	delete(m.indexes, userId)
+	m.mu.Unlock()
+}
+
+func memoryNamespaceOf(userId string) string {
+	return fmt.Sprintf("%s:%s", memoryNamespace, userId)
+}
+
+// WithLongTermMemory 开启长期记忆。会话关闭时保存记忆，会话删除时删除其记忆。
+// 通过会话管理接口关闭或删除会话时，接口需要使用 SessionManager 返回的会话后端
+func (c *Client) WithLongTermMemory(m *LongTermMemory) *Client {
+	c.memory = m
+	c.SubscribeAsync(EventSessionClosed, c.memorize)
+	c.SubscribeAsync(EventSessionDeleted, c.forgetSession)
+	return c
+}
+
+// memorize 将关闭的会话中尚未保存的每一轮对话向量化保存
+func (c *Client) memorize(ctx context.Context, e Event) {
+	if e.Session == nil {
+		return
+	}
+	// 会话在关闭后已被删除
+	if sm, ok := c.ch.(conversation.SessionManager); ok {
+		if _, err := sm.GetSession(ctx, e.Session.UserID, e.Session.ID); errors.Is(err, conversation.ErrNotFound) {
+			return
+		}
+	}
+	memorized, err := c.memory.memorized(ctx, e.Session.UserID, e.Session.ID)
+	if err != nil {
+		c.logger.Warn().Msgf("Load session %d memory failed: %s", e.Session.ID, err)
+		return
+	}
+	// 群聊会话的记忆按群保存，提问来自不同的成员
+	var msgs []*conversation.Message
+	if e.Session.GroupChat {
+		msgs, err = c.listGroupTurns(ctx, e.Session, c.memory.maxTurns)
+	} else {
+		msgs, err = c.ch.ListLatestMessagesWithSpouse(ctx, e.Session, e.UserID, c.memory.maxTurns)
+	}
+	if err != nil {
+		c.logger.Warn().Msgf("List session %d messages failed: %s", e.Session.ID, err)
+		return
+	}
+
+	snippets := make([]*conversation.Embedding, 0, len(msgs)/2)
+	for i := 0; i+1 < len(msgs); i += 2 {
+		q, a := msgs[i], msgs[i+1]
+		if memorized[q.ID] {
+			continue
+		}
+		user := openai.ChatMessageRoleUser
+		if e.Session.GroupChat {
+			user = fmt.Sprintf("%s(%s)", user, participantName(q.FromUserID))
+		}
+		snippets = append(snippets, &conversation.Embedding{
+			Namespace: memoryNamespaceOf(e.Session.UserID),
+			UserID:    e.Session.UserID,
+			SessionID: e.Session.ID,
+			MessageID: q.ID,
+			Content:   fmt.Sprintf("%s: %s\n%s: %s", user, q.Content, openai.ChatMessageRoleAssistant, a.Content),
+		})
+	}
+	if len(snippets) == 0 {
+		return
+	}
+
+	inputs := make([]string, len(snippets))
+	for i, s := range snippets {
+		inputs[i] = s.Content
+	}
+	vs, err := c.embed(ctx, c.memory.model, inputs)
+	if err != nil {
+		c.logger.Warn().Msgf("Embed session %d messages failed: %s", e.Session.ID, err)
+		return
+	}
+	for i, s := range snippets {
+		s.Vector = vs[i]
+		if err := c.memory.add(ctx, s); err != nil {
+			c.logger.Warn().Msgf("Save session %d memory failed: %s", e.Session.ID, err)
+			return
+		}
+	}
+}
+
+// forgetSession 删除已删除会话的记忆
+func (c *Client) forgetSession(ctx context.Context, e Event) {
+	if e.Session == nil {
+		return
+	}
+	if err := c.memory.removeSession(ctx, e.Session.UserID, e.Session.ID); err != nil {
+		c.logger.Warn().Msgf("Delete session %d memory failed: %s", e.Session.ID, err)
+	}
+}
+
+// recall 检索与用户问题相关的记忆片段，返回的内容长度不超过上下文长度的 share 比例
+func (c *Client) recall(ctx context.Context, session *conversation.Session, request *openai.ChatCompletionRequest) string {
+	if c.memory == nil {
+		return ""
+	}
+	question := lastUserContent(request.Messages)
+	if question == "" {
+		return ""
+	}
+
+	ctx, span := c.startSpan(ctx, "xgpt3.memory_recall")
+	defer span.End()
+
+	vs, err := c.embed(ctx, c.memory.model, []string{question})
+	if err != nil {
+		c.logger.Warn().Msgf("Embed question failed: %s", err)
+		return ""
+	}
+	// 群聊会话的 UserID 为群Id，检索群的记忆
+	snippets, err := c.memory.search(ctx, session.UserID, session.ID, vs[0])
+	if err != nil {
+		c.logger.Warn().Msgf("Search memory failed: %s", err)
+		return ""
+	}
+
+	budget := int(float64(c.maxCtxLength) * c.memory.share)
+	var b strings.Builder
+	for _, s := range snippets {
+		if b.Len() == 0 && len(memoryPrompt) > budget {
+			break
+		}
+		if b.Len() == 0 {
+			b.WriteString(memoryPrompt)
+		}
+		snippet := fmt.Sprintf("---\n%s\n", s.Content)
+		if b.Len()+len(snippet) > budget {
+			break
+		}
+		b.WriteString(snippet)
+	}
+	if b.Len() <= len(memoryPrompt) {
+		return ""
+	}
+	return b.String()
+}
+
+func lastUserContent(msgs []openai.ChatCompletionMessage) string {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == openai.ChatMessageRoleUser {
+			return messageText(msgs[i])
+		}
+	}
+	return ""
+}
+
+// injectSystemMessage 在开头的系统消息之后插入一条系统消息
+func injectSystemMessage(msgs []openai.ChatCompletionMessage, content string) []openai.ChatCompletionMessage {
+	i := 0
+	for i < len(msgs) && msgs[i].Role == openai.ChatMessageRoleSystem {
+		i++
+	}
+	result := make([]openai.ChatCompletionMessage, 0, len(msgs)+1)
+	result = append(result, msgs[:i]...)
+	result = append(result, openai.ChatCompletionMessage{Role: openai.ChatMessageRoleSystem, Content: content})
+	return append(result, msgs[i:]...)
+}
@@ -0,0 +1,75 @@
+package xgpt3_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+)
+
+// waitEmbeddings 等待异步的事件处理完成，直到命名空间中的向量数量为 n
+func waitEmbeddings(t *testing.T, store conversation.EmbeddingStore, namespace string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		es, err := store.ListEmbeddings(context.Background(), namespace)
+		if err != nil {
+			t.Fatalf("ListEmbeddings() error = %v", err)
+		}
+		if len(es) == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("embeddings = %d, want %d", len(es), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLongTermMemorySessionManager(t *testing.T) {
+	ctx := context.Background()
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Respond(func(r xgpt3test.Request) xgpt3test.Response {
+		return xgpt3test.Response{Content: "好的"}
+	})
+	ch := memory.New()
+	c := xgpt3.NewClient(srv.Client(), ch).WithLongTermMemory(xgpt3.NewLongTermMemory(ch))
+	sm := c.SessionManager().(conversation.SessionManager)
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "我对花生过敏")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	first, err := ch.GetLatestActiveSession(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetLatestActiveSession() error = %v", err)
+	}
+
+	// 通过会话管理接口关闭会话时保存记忆
+	if err := sm.CloseSessionByID(ctx, "alice", first.ID); err != nil {
+		t.Fatalf("CloseSessionByID() error = %v", err)
+	}
+	waitEmbeddings(t, ch, "memory:alice", 1)
+
+	// 重新开启其他会话时关闭当前会话，已保存的对话不会重复保存
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "我住在杭州")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if err := sm.ReopenSession(ctx, "alice", first.ID); err != nil {
+		t.Fatalf("ReopenSession() error = %v", err)
+	}
+	waitEmbeddings(t, ch, "memory:alice", 2)
+	if err := sm.CloseSessionByID(ctx, "alice", first.ID); err != nil {
+		t.Fatalf("CloseSessionByID() error = %v", err)
+	}
+
+	// 删除会话时删除其记忆
+	if err := sm.DeleteSession(ctx, "alice", first.ID); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	waitEmbeddings(t, ch, "memory:alice", 1)
+}
@@ -0,0 +1,124 @@
+package xgpt3
+
+import (
+	"context"
+
+	"github.com/fanchunke/xgpt3/cache"
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Stage 对话请求的处理阶段
+type Stage string
+
+const (
+	// 预处理：获取会话、保存用户消息、拼接历史消息
+	StagePreprocess Stage = "preprocess"
+	// 请求 OpenAI
+	StageUpstream Stage = "upstream"
+	// 后处理：保存回复消息
+	StagePostprocess Stage = "postprocess"
+)
+
+// ChatContext 一次对话请求的上下文，在各个阶段和中间件之间传递
+type ChatContext struct {
+	// 当前所处阶段
+	Stage Stage
+	// 消息渠道
+	Channel string
+	// 请求。预处理之后包含拼接好的历史消息
+	Request openai.ChatCompletionRequest
+	// 当前会话，预处理之后可用
+	Session *conversation.Session
+	// 本次保存的用户消息，预处理之后可用
+	Message *conversation.Message
+	// OpenAI 返回结果，请求之后可用
+	Response openai.ChatCompletionResponse
+	// 返回结果是否来自缓存
+	CacheHit bool
+	// 本次保存的回复消息，后处理之后可用
+	Reply *conversation.Message
+	// 注入上下文的知识库资料，预处理之后可用
+	Citations []conversation.Citation
+
+	stream provider.ChatCompletionStream
+}
+
+// owner 返回由本次对话生成的数据所属的用户，群聊中为群的会话所属的群Id
+func (cc *ChatContext) owner() string {
+	if cc.Session != nil {
+		return cc.Session.UserID
+	}
+	return cc.Request.User
+}
+
+// ChatHandler 处理对话请求的某个阶段
+type ChatHandler func(ctx context.Context, cc *ChatContext) error
+
+// ChatMiddleware 包装每个阶段的 ChatHandler。通过 ChatContext.Stage 区分当前阶段
+type ChatMiddleware func(next ChatHandler) ChatHandler
+
+// Use 注册中间件。先注册的中间件位于外层
+func (c *Client) Use(middlewares ...ChatMiddleware) *Client {
+	c.middlewares = append(c.middlewares, middlewares...)
+	return c
+}
+
+func (c *Client) runStage(ctx context.Context, cc *ChatContext, stage Stage, h ChatHandler) error {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
+	}
+	cc.Stage = stage
+	return h(ctx, cc)
+}
+
+func (c *Client) preprocessStage(ctx context.Context, cc *ChatContext) error {
+	return c.preChatCompletion(ctx, cc)
+}
+
+func (c *Client) upstreamStage(ctx context.Context, cc *ChatContext) (err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.upstream", attribute.String("xgpt3.model", cc.Request.Model), attribute.Int("xgpt3.max_tokens", cc.Request.MaxTokens))
+	defer func() { endSpan(span, err) }()
+
+	// 命中缓存时不请求 OpenAI，后处理仍会保存回复消息
+	key, cacheable := c.responseCacheKey(cc.owner(), cc.Request)
+	if cacheable {
+		if resp, ok := c.getCachedResponse(ctx, key); ok {
+			cc.Response, cc.CacheHit = resp, true
+			span.SetAttributes(attribute.Bool("xgpt3.cache.hit", true))
+			return nil
+		}
+	}
+
+	// 语义缓存
+	query, semantic := c.semanticQuestion(cc)
+	var questionVector []float32
+	if semantic {
+		answer, v, hit := c.lookupSemanticCache(ctx, query)
+		if hit {
+			cc.Response, cc.CacheHit = semanticResponse(cc.Request.Model, answer), true
+			span.SetAttributes(attribute.Bool("xgpt3.cache.hit", true), attribute.Bool("xgpt3.cache.semantic", true))
+			return nil
+		}
+		questionVector = v
+	}
+
+	resp, err := c.provider.CreateChatCompletion(ctx, cc.Request)
+	cc.Response = resp
+	span.SetAttributes(usageAttributes(resp.Usage)...)
+	if err == nil && cacheable {
+		c.setCachedResponse(cache.WithOwner(ctx, cc.owner()), key, resp)
+	}
+	if err == nil && questionVector != nil && len(resp.Choices) > 0 {
+		if err := c.semanticCache.save(ctx, query, resp.Choices[0].Message.Content, questionVector); err != nil {
+			c.logger.Warn().Msgf("Save semantic cache failed: %s", err)
+		}
+	}
+	return err
+}
+
+func (c *Client) postprocessStage(ctx context.Context, cc *ChatContext) error {
+	return c.postChatCompletion(ctx, cc)
+}
@@ -0,0 +1,139 @@
+package xgpt3
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// 图片按固定长度计入上下文，与 OpenAI 对 low 和 high 精度图片的计费大致相当
+	lowDetailImageTokens  = 85
+	highDetailImageTokens = 765
+	// 历史消息中的图片全部被省略时使用的文本
+	omittedImageText = "[图片已省略]"
+)
+
+// messageText 返回消息的文本内容，多模态消息为其中文本片段的拼接
+func messageText(m openai.ChatCompletionMessage) string {
+	if len(m.MultiContent) == 0 {
+		return m.Content
+	}
+	texts := make([]string, 0, len(m.MultiContent))
+	for _, p := range m.MultiContent {
+		if p.Type == openai.ChatMessagePartTypeText && p.Text != "" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// messageTokens 估算消息占用的上下文长度：文本按字节数，图片按固定长度
+func messageTokens(m openai.ChatCompletionMessage) int {
+	if len(m.MultiContent) == 0 {
+		return len(m.Content)
+	}
+	l := 0
+	for _, p := range m.MultiContent {
+		switch {
+		case p.Type == openai.ChatMessagePartTypeText:
+			l += len(p.Text)
+		case p.ImageURL != nil && p.ImageURL.Detail == openai.ImageURLDetailLow:
+			l += lowDetailImageTokens
+		default:
+			l += highDetailImageTokens
+		}
+	}
+	return l
+}
+
+// hasImage 消息是否包含图片
+func hasImage(m openai.ChatCompletionMessage) bool {
+	for _, p := range m.MultiContent {
+		if p.Type == openai.ChatMessagePartTypeImageURL {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutImages 去掉消息中的图片，只保留文本
+func withoutImages(m openai.ChatCompletionMessage) openai.ChatCompletionMessage {
+	text := messageText(m)
+	if text == "" {
+		text = omittedImageText
+	}
+	m.Content, m.MultiContent = text, nil
+	return m
+}
+
+// toContentParts 将多模态消息转换为保存的内容片段，普通文本消息返回空
+func toContentParts(m openai.ChatCompletionMessage) []conversation.ContentPart {
+	if len(m.MultiContent) == 0 {
+		return nil
+	}
+	parts := make([]conversation.ContentPart, 0, len(m.MultiContent))
+	for _, p := range m.MultiContent {
+		part := conversation.ContentPart{Type: string(p.Type), Text: p.Text}
+		if p.ImageURL != nil {
+			part.ImageURL = p.ImageURL.URL
+			part.Detail = string(p.ImageURL.Detail)
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// messageParts 返回用户消息需要保存的内容片段：多模态消息的片段，语音消息的转写文本和音频引用
+func messageParts(ctx context.Context, m openai.ChatCompletionMessage) []conversation.ContentPart {
+	parts := toContentParts(m)
+	audio, ok := audioPart(ctx)
+	if !ok {
+		return parts
+	}
+	if len(parts) == 0 {
+		parts = append(parts, conversation.ContentPart{Type: conversation.ContentPartText, Text: m.Content})
+	}
+	return append(parts, audio)
+}
+
+// historyMessage 将保存的消息还原为请求消息，带有图片的消息还原为多模态消息。音频片段只用于记录，不发送给模型
+func historyMessage(role string, m *conversation.Message) openai.ChatCompletionMessage {
+	parts := make([]openai.ChatMessagePart, 0, len(m.Parts))
+	images := 0
+	for _, p := range m.Parts {
+		switch p.Type {
+		case conversation.ContentPartText:
+			parts = append(parts, openai.ChatMessagePart{Type: openai.ChatMessagePartTypeText, Text: p.Text})
+		case conversation.ContentPartImage:
+			parts = append(parts, openai.ChatMessagePart{
+				Type:     openai.ChatMessagePartTypeImageURL,
+				ImageURL: &openai.ChatMessageImageURL{URL: p.ImageURL, Detail: openai.ImageURLDetail(p.Detail)},
+			})
+			images++
+		}
+	}
+	if images == 0 {
+		return openai.ChatCompletionMessage{Role: role, Content: m.Content}
+	}
+	return openai.ChatCompletionMessage{Role: role, MultiContent: parts}
+}
+
+// saveContentParts 保存多模态用户消息的内容片段。会话后端没有实现 conversation.ContentPartStore 时图片不会出现在之后的历史消息中
+func (c *Client) saveContentParts(ctx context.Context, session *conversation.Session, msg *conversation.Message, parts []conversation.ContentPart) (err error) {
+	ps, ok := c.ch.(conversation.ContentPartStore)
+	if !ok {
+		c.logger.Debug().Msgf("Conversation handler does not support content parts, message %d parts dropped", msg.ID)
+		return nil
+	}
+	ctx, span := c.startSpan(ctx, "xgpt3.persist", attribute.Int("xgpt3.message.parts", len(parts)))
+	defer func() { endSpan(span, err) }()
+	if err := ps.SaveContentParts(ctx, session, msg, parts); err != nil {
+		return err
+	}
+	msg.Parts = parts
+	return nil
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Embedder 计算文本向量，*openai.Client 和 Provider 都满足该接口
+type Embedder interface {
+	CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error)
+}
+
+// Embed 计算 inputs 的向量，按输入的顺序返回。返回的向量与输入不能一一对应时返回错误
+func Embed(ctx context.Context, e Embedder, model openai.EmbeddingModel, inputs []string) ([][]float32, error) {
+	resp, err := e.CreateEmbeddings(ctx, openai.EmbeddingRequest{Input: inputs, Model: model})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(inputs) {
+		return nil, errors.New("embeddings count mismatch")
+	}
+	result := make([][]float32, len(inputs))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(inputs) {
+			return nil, fmt.Errorf("embedding index %d out of range", d.Index)
+		}
+		result[d.Index] = d.Embedding
+	}
+	for i, v := range result {
+		if v == nil {
+			return nil, fmt.Errorf("embedding %d missing", i)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const defaultAzureAPIVersion = "2023-05-15"
+
+// OpenAI 基于 go-openai 的实现，适用于 OpenAI、Azure OpenAI 以及兼容 OpenAI 接口的服务
+type OpenAI struct {
+	client *openai.Client
+}
+
+// NewOpenAI 使用已有的 go-openai client
+func NewOpenAI(client *openai.Client) *OpenAI {
+	return &OpenAI{client: client}
+}
+
+// NewCompatible 兼容 OpenAI 接口的服务，例如 vLLM、llama.cpp server、Ollama。
+// baseURL 需要包含版本路径，例如 http://127.0.0.1:11434/v1；不需要认证时 apiKey 可以为空
+func NewCompatible(baseURL, apiKey string) *OpenAI {
+	config := openai.DefaultConfig(apiKey)
+	config.BaseURL = strings.TrimRight(baseURL, "/")
+	return NewOpenAI(openai.NewClientWithConfig(config))
+}
+
+// AzureConfig Azure OpenAI 配置
+type AzureConfig struct {
+	// 资源地址，例如 https://xxx.openai.azure.com
+	Endpoint string
+	APIKey   string
+	// 接口版本，为空时使用 2023-05-15
+	APIVersion string
+	// 模型名称到部署名称的映射。没有映射的模型去掉名称中的 . 和 : 作为部署名称
+	Deployments map[string]string
+}
+
+// NewAzure Azure OpenAI 服务，请求中的模型名称会按配置映射为部署名称
+func NewAzure(cfg AzureConfig) *OpenAI {
+	config := openai.DefaultAzureConfig(cfg.APIKey, cfg.Endpoint)
+	if cfg.APIVersion != "" {
+		config.APIVersion = cfg.APIVersion
+	} else {
+		config.APIVersion = defaultAzureAPIVersion
+	}
+	fallback := config.AzureModelMapperFunc
+	config.AzureModelMapperFunc = func(model string) string {
+		if deployment, ok := cfg.Deployments[model]; ok {
+			return deployment
+		}
+		return fallback(model)
+	}
+	return NewOpenAI(openai.NewClientWithConfig(config))
+}
+
+// Client 返回底层的 go-openai client，用于调用 Provider 之外的接口
+func (p *OpenAI) Client() *openai.Client {
+	return p.client
+}
+
+func (p *OpenAI) CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	return p.client.CreateChatCompletion(ctx, request)
+}
+
+func (p *OpenAI) CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (ChatCompletionStream, error) {
+	stream, err := p.client.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (p *OpenAI) CreateCompletion(ctx context.Context, request openai.CompletionRequest) (openai.CompletionResponse, error) {
+	return p.client.CreateCompletion(ctx, request)
+}
+
+func (p *OpenAI) CreateCompletionStream(ctx context.Context, request openai.CompletionRequest) (CompletionStream, error) {
+	stream, err := p.client.CreateCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (p *OpenAI) CreateEmbeddings(ctx context.Context, request openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	return p.client.CreateEmbeddings(ctx, request)
+}
+
+func (p *OpenAI) CreateTranscription(ctx context.Context, request openai.AudioRequest) (openai.AudioResponse, error) {
+	return p.client.CreateTranscription(ctx, request)
+}
+
+func (p *OpenAI) CreateSpeech(ctx context.Context, request openai.CreateSpeechRequest) (io.ReadCloser, error) {
+	return p.client.CreateSpeech(ctx, request)
+}
@@ -0,0 +1,38 @@
+// Package provider 抽象模型服务的接口。请求和返回结果统一使用 go-openai 中的类型。
+// 内置的实现都使用 OpenAI 的接口格式，其他接口格式的厂商需要自行实现 Provider 并负责格式转换
+package provider
+
+import (
+	"context"
+	"io"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Provider 模型服务
+type Provider interface {
+	CreateChatCompletion(ctx context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error)
+	CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (ChatCompletionStream, error)
+	CreateCompletion(ctx context.Context, request openai.CompletionRequest) (openai.CompletionResponse, error)
+	CreateCompletionStream(ctx context.Context, request openai.CompletionRequest) (CompletionStream, error)
+	CreateEmbeddings(ctx context.Context, request openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error)
+}
+
+// AudioProvider 支持语音转写和语音合成的模型服务
+type AudioProvider interface {
+	CreateTranscription(ctx context.Context, request openai.AudioRequest) (openai.AudioResponse, error)
+	// CreateSpeech 返回合成的音频，调用方负责关闭
+	CreateSpeech(ctx context.Context, request openai.CreateSpeechRequest) (io.ReadCloser, error)
+}
+
+// ChatCompletionStream 流式对话结果，Recv 结束时返回 io.EOF
+type ChatCompletionStream interface {
+	Recv() (openai.ChatCompletionStreamResponse, error)
+	Close()
+}
+
+// CompletionStream 流式补全结果，Recv 结束时返回 io.EOF
+type CompletionStream interface {
+	Recv() (openai.CompletionResponse, error)
+	Close()
+}
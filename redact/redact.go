@@ -0,0 +1,343 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Detector 敏感信息检测器
+type Detector struct {
+	// 检测器名称，用于生成占位符，例如 EMAIL 生成 [EMAIL] 或 [EMAIL_3f9a2c1b0d]
+	Name string
+	// 匹配规则
+	Pattern *regexp.Regexp
+	// 匹配结果的二次校验。为空时所有匹配结果都视为敏感信息
+	Validate func(s string) bool
+}
+
+var (
+	// 电子邮箱
+	Email = Detector{
+		Name:    "EMAIL",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	// 中国居民身份证号
+	ChinaResidentID = Detector{
+		Name:     "ID",
+		Pattern:  regexp.MustCompile(`\b\d{17}[\dXx]\b`),
+		Validate: validChinaResidentID,
+	}
+	// 银行卡号
+	CreditCard = Detector{
+		Name:     "CARD",
+		Pattern:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Validate: validLuhn,
+	}
+	// 手机号，支持中国大陆手机号和带国际区号的号码
+	Phone = Detector{
+		Name:    "PHONE",
+		Pattern: regexp.MustCompile(`(?:\+\d{1,3}[ -]?)?\b1[3-9]\d{9}\b|\+\d{1,3}[ -]?\d{2,4}[ -]?\d{3,4}[ -]?\d{3,4}\b`),
+	}
+)
+
+// DefaultDetectors 内置检测器。检测按顺序进行，身份证号和手机号需要先于银行卡号检测，避免被误判
+var DefaultDetectors = []Detector{Email, ChinaResidentID, Phone, CreditCard}
+
+// 占位符中摘要的长度（十六进制字符数）
+const tokenDigestLen = 10
+
+// 占位符的格式，用于在还原时查找文本中的占位符
+var tokenPattern = regexp.MustCompile(fmt.Sprintf(`\[[^\[\]\s]+_[0-9a-f]{%d}\]`, tokenDigestLen))
+
+// Redactor 在消息保存和请求 OpenAI 之前脱敏
+type Redactor struct {
+	detectors []Detector
+	tokenize  bool
+	key       []byte
+	store     TokenStore
+	// 匹配检测器生成的占位符，已有的占位符不再参与检测
+	placeholder *regexp.Regexp
+}
+
+func New(detectors ...Detector) *Redactor {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors
+	}
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("redact: generate token key failed: %s", err))
+	}
+	return &Redactor{detectors: detectors, key: key, store: NewMemoryTokenStore(), placeholder: placeholderPattern(detectors)}
+}
+
+// WithPattern 添加自定义正则检测器
+func (r *Redactor) WithPattern(name string, pattern string) *Redactor {
+	r.detectors = append(r.detectors, Detector{Name: name, Pattern: regexp.MustCompile(pattern)})
+	r.placeholder = placeholderPattern(r.detectors)
+	return r
+}
+
+// WithTokenStore 设置保存占位符映射的存储。默认保存在进程内，重启后历史消息中的占位符无法还原；
+// 多个实例共享会话时需要使用共享的存储
+func (r *Redactor) WithTokenStore(store TokenStore) *Redactor {
+	r.store = store
+	return r
+}
+
+// WithTokenization 开启可逆脱敏：敏感信息替换为编号占位符，回复中的占位符可以还原为原始值
+func (r *Redactor) WithTokenization() *Redactor {
+	r.tokenize = true
+	return r
+}
+
+// WithTokenKey 设置生成占位符的密钥。默认每个进程随机生成，多个实例共享会话时需要设置相同的密钥，
+// 使同一个值在不同实例中得到相同的占位符
+func (r *Redactor) WithTokenKey(key []byte) *Redactor {
+	r.key = key
+	return r
+}
+
+func (r *Redactor) Tokenize() bool {
+	return r.tokenize
+}
+
+// NewVault 返回 owner 的 vault。脱敏时占位符的映射同时保存到 TokenStore，
+// 模型在之后的对话中重复历史消息里的占位符时也能还原
+func (r *Redactor) NewVault(owner string) *Vault {
+	v := NewVault()
+	v.store, v.owner = r.store, owner
+	return v
+}
+
+// Forget 删除用户保存的占位符映射
+func (r *Redactor) Forget(owner string) {
+	if r.store != nil {
+		r.store.DeleteTokens(owner)
+	}
+}
+
+// Redact 脱敏文本。vault 为空时使用不可逆的掩码，否则将原始值保存在 vault 中。
+// 文本中已有的占位符不会被再次检测
+func (r *Redactor) Redact(text string, vault *Vault) string {
+	for _, d := range r.detectors {
+		d := d
+		text = r.outsidePlaceholders(text, func(text string) string {
+			return d.Pattern.ReplaceAllStringFunc(text, func(s string) string {
+				if d.Validate != nil && !d.Validate(s) {
+					return s
+				}
+				if vault == nil {
+					return fmt.Sprintf("[%s]", d.Name)
+				}
+				t := r.token(d.Name, s)
+				vault.add(t, s)
+				return t
+			})
+		})
+	}
+	return text
+}
+
+// outsidePlaceholders 只对占位符之外的文本调用 f，避免自定义检测器匹配到占位符中的摘要
+func (r *Redactor) outsidePlaceholders(text string, f func(string) string) string {
+	locs := r.placeholder.FindAllStringIndex(text, -1)
+	if len(locs) == 0 {
+		return f(text)
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		b.WriteString(f(text[last:loc[0]]))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(f(text[last:]))
+	return b.String()
+}
+
+// placeholderPattern 匹配检测器生成的掩码和占位符，例如 [EMAIL] 和 [EMAIL_3f9a2c1b0d]
+func placeholderPattern(detectors []Detector) *regexp.Regexp {
+	names := make([]string, 0, len(detectors))
+	for _, d := range detectors {
+		names = append(names, regexp.QuoteMeta(d.Name))
+	}
+	return regexp.MustCompile(fmt.Sprintf(`\[(?:%s)(?:_[0-9a-f]{%d})?\]`, strings.Join(names, "|"), tokenDigestLen))
+}
+
+// token 由原始值的 HMAC 摘要生成占位符。同一个值总是得到相同的占位符，不同的值得到不同的占位符，
+// 历史消息中的占位符不会在之后的对话中指向其他值
+func (r *Redactor) token(name, value string) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return fmt.Sprintf("[%s_%s]", name, hex.EncodeToString(mac.Sum(nil))[:tokenDigestLen])
+}
+
+// Vault 保存一次请求内占位符与原始值的映射。通过 Redactor.NewVault 创建时，
+// 本次请求之外的占位符从 TokenStore 中还原
+type Vault struct {
+	mu     sync.Mutex
+	tokens map[string]string
+	store  TokenStore
+	owner  string
+}
+
+func NewVault() *Vault {
+	return &Vault{tokens: make(map[string]string)}
+}
+
+func (v *Vault) add(token, value string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tokens[token] = value
+	if v.store != nil {
+		v.store.SaveToken(v.owner, token, value)
+	}
+}
+
+// Restore 将文本中的占位符还原为原始值，未知的占位符保持不变
+func (v *Vault) Restore(text string) string {
+	if v == nil {
+		return text
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return tokenPattern.ReplaceAllStringFunc(text, func(t string) string {
+		if value, ok := v.tokens[t]; ok {
+			return value
+		}
+		if v.store != nil {
+			if value, ok := v.store.LoadToken(v.owner, t); ok {
+				return value
+			}
+		}
+		return t
+	})
+}
+
+// TokenStore 按用户保存占位符与原始值的映射
+type TokenStore interface {
+	SaveToken(owner, token, value string)
+	LoadToken(owner, token string) (string, bool)
+	// DeleteTokens 删除用户的所有映射
+	DeleteTokens(owner string)
+}
+
+// MemoryTokenStore 进程内的 TokenStore
+type MemoryTokenStore struct {
+	mu     sync.RWMutex
+	owners map[string]map[string]string
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{owners: make(map[string]map[string]string)}
+}
+
+func (s *MemoryTokenStore) SaveToken(owner, token, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, ok := s.owners[owner]
+	if !ok {
+		tokens = make(map[string]string)
+		s.owners[owner] = tokens
+	}
+	tokens[token] = value
+}
+
+func (s *MemoryTokenStore) LoadToken(owner, token string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.owners[owner][token]
+	return value, ok
+}
+
+func (s *MemoryTokenStore) DeleteTokens(owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.owners, owner)
+}
+
+// 占位符的最大长度，流式回复中超过该长度仍未闭合的 [ 不再等待
+const maxTokenLen = 64
+
+// StreamRestorer 还原流式回复中的占位符。占位符可能被拆分在多个分片中，
+// 未闭合的 [ 之后的内容会暂存到下一个分片
+type StreamRestorer struct {
+	vault   *Vault
+	pending string
+}
+
+// Stream 返回流式回复的还原器，每个回复选项使用一个
+func (v *Vault) Stream() *StreamRestorer {
+	return &StreamRestorer{vault: v}
+}
+
+// Write 追加一个分片，返回可以输出的已还原文本
+func (s *StreamRestorer) Write(delta string) string {
+	text := s.pending + delta
+	s.pending = ""
+	if i := strings.LastIndexByte(text, '['); i >= 0 && !strings.ContainsRune(text[i:], ']') && len(text)-i < maxTokenLen {
+		text, s.pending = text[:i], text[i:]
+	}
+	return s.vault.Restore(text)
+}
+
+// Flush 返回暂存的剩余文本，流结束时调用
+func (s *StreamRestorer) Flush() string {
+	text := s.pending
+	s.pending = ""
+	return s.vault.Restore(text)
+}
+
+func digits(s string) []int {
+	result := make([]int, 0, len(s))
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			result = append(result, int(r-'0'))
+		}
+	}
+	return result
+}
+
+func validLuhn(s string) bool {
+	ds := digits(s)
+	if len(ds) < 13 || len(ds) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(ds) - 1; i >= 0; i-- {
+		d := ds[i]
+		if (len(ds)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+var (
+	residentIDWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	residentIDChecks  = "10X98765432"
+)
+
+func validChinaResidentID(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += int(s[i]-'0') * residentIDWeights[i]
+	}
+	return residentIDChecks[sum%11] == strings.ToUpper(s[17:])[0]
+}
@@ -0,0 +1,97 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenStableAcrossTurns(t *testing.T) {
+	r := New(Email).WithTokenization()
+
+	first := r.Redact("联系 a@example.com", r.NewVault("alice"))
+	v2 := r.NewVault("alice")
+	second := r.Redact("换成 b@example.com，原来是 a@example.com", v2)
+
+	tokenA := strings.TrimPrefix(first, "联系 ")
+	if !strings.Contains(second, tokenA) {
+		t.Fatalf("same value got different tokens: %q, %q", first, second)
+	}
+	if strings.Count(second, "[EMAIL_") != 2 || strings.Contains(second, "example.com") {
+		t.Fatalf("unexpected redaction %q", second)
+	}
+	if got := v2.Restore(tokenA); got != "a@example.com" {
+		t.Fatalf("restore %q = %q", tokenA, got)
+	}
+	if got := NewVault().Restore(tokenA); got != tokenA {
+		t.Fatalf("unknown token restored to %q", got)
+	}
+}
+
+func TestRestoreTokenFromHistory(t *testing.T) {
+	r := New(Email).WithTokenization()
+	// 历史消息中的占位符，本轮请求不包含原始值
+	token := r.Redact("a@example.com", r.NewVault("alice"))
+
+	v := r.NewVault("alice")
+	r.Redact("继续", v)
+	if got := v.Restore("发送到 " + token); got != "发送到 a@example.com" {
+		t.Fatalf("got %q", got)
+	}
+	// 其他用户的占位符不会被还原
+	if got := r.NewVault("bob").Restore(token); got != token {
+		t.Fatalf("token of another user restored to %q", got)
+	}
+	r.Forget("alice")
+	if got := r.NewVault("alice").Restore(token); got != token {
+		t.Fatalf("forgotten token restored to %q", got)
+	}
+}
+
+func TestPatternSkipsPlaceholders(t *testing.T) {
+	r := New(Email).WithTokenization().WithPattern("NUM", `[0-9a-f]{5,}`)
+	v := r.NewVault("alice")
+	text := r.Redact("a@example.com", v)
+	// 已有的占位符和其中的摘要不会被再次检测
+	again := r.Redact(text+" 和 12345", v)
+	if !strings.HasPrefix(again, text+" 和 [NUM_") {
+		t.Fatalf("got %q", again)
+	}
+	if got := v.Restore(again); got != "a@example.com 和 12345" {
+		t.Fatalf("restore got %q", got)
+	}
+}
+
+func TestTokenKey(t *testing.T) {
+	key := []byte("shared-key")
+	a := New(Email).WithTokenKey(key).Redact("a@example.com", NewVault())
+	b := New(Email).WithTokenKey(key).Redact("a@example.com", NewVault())
+	if a != b {
+		t.Fatalf("tokens differ with the same key: %q, %q", a, b)
+	}
+	if c := New(Email).Redact("a@example.com", NewVault()); c == a {
+		t.Fatalf("tokens equal with different keys: %q", c)
+	}
+}
+
+func TestStreamRestorer(t *testing.T) {
+	r := New(Email).WithTokenization()
+	v := NewVault()
+	token := r.Redact("a@example.com", v)
+
+	s := v.Stream()
+	var out strings.Builder
+	for _, chunk := range []string{"发送到 " + token[:4], token[4:9], token[9:] + " 了", "，数组 a[0"} {
+		out.WriteString(s.Write(chunk))
+	}
+	out.WriteString(s.Flush())
+	if got, want := out.String(), "发送到 a@example.com 了，数组 a[0"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestMaskWithoutVault(t *testing.T) {
+	got := New().Redact("手机 13812345678，邮箱 a@example.com", nil)
+	if got != "手机 [PHONE]，邮箱 [EMAIL]" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,299 @@
+package xgpt3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/fanchunke/xgpt3/vector"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	defaultEmbeddingModel  = openai.AdaEmbeddingV2
+	semanticCacheNamespace = "semantic_cache"
+	semanticAnswerKey      = "answer"
+	// 进程内最多保留的渠道索引数量
+	defaultSemanticMaxIndexes = 64
+)
+
+// SemanticCacheStats 语义缓存命中统计
+type SemanticCacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// SemanticCache 基于向量相似度的回复缓存。用户问题与已缓存问题的余弦相似度超过阈值时直接返回缓存的回答。
+// 缓存在同一渠道的所有用户之间共享，只比较最后一个用户问题，不考虑历史消息，
+// 适合常见问题解答等回答与上下文无关的渠道
+type SemanticCache struct {
+	store      conversation.EmbeddingStore
+	threshold  float32
+	model      openai.EmbeddingModel
+	channels   map[string]bool
+	maxIndexes int
+
+	mu      sync.Mutex
+	indexes map[string]*semanticIndex
+	stats   map[string]*SemanticCacheStats
+}
+
+type semanticIndex struct {
+	index    *vector.Index
+	entries  map[int]semanticEntry
+	lastUsed time.Time
+}
+
+type semanticEntry struct {
+	// 提问的用户，群聊中为群Id
+	owner  string
+	answer string
+}
+
+func NewSemanticCache(store conversation.EmbeddingStore, threshold float32) *SemanticCache {
+	return &SemanticCache{
+		store:      store,
+		threshold:  threshold,
+		model:      defaultEmbeddingModel,
+		maxIndexes: defaultSemanticMaxIndexes,
+		indexes:    make(map[string]*semanticIndex),
+		stats:      make(map[string]*SemanticCacheStats),
+	}
+}
+
+// WithModel 设置 Embeddings 模型
+func (s *SemanticCache) WithModel(model openai.EmbeddingModel) *SemanticCache {
+	s.model = model
+	return s
+}
+
+// WithChannels 只对指定渠道开启语义缓存。默认对所有渠道开启
+func (s *SemanticCache) WithChannels(channels ...string) *SemanticCache {
+	s.channels = make(map[string]bool, len(channels))
+	for _, ch := range channels {
+		s.channels[ch] = true
+	}
+	return s
+}
+
+// WithMaxIndexes 设置进程内最多保留的渠道索引数量，超出时丢弃最久未使用的索引，之后使用时重新从存储中加载。默认 64
+func (s *SemanticCache) WithMaxIndexes(n int) *SemanticCache {
+	s.maxIndexes = n
+	return s
+}
+
+// Stats 返回各渠道的命中统计
+func (s *SemanticCache) Stats() map[string]SemanticCacheStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]SemanticCacheStats, len(s.stats))
+	for ch, st := range s.stats {
+		result[ch] = *st
+	}
+	return result
+}
+
+func (s *SemanticCache) enabled(channel string) bool {
+	return s.channels == nil || s.channels[channel]
+}
+
+func (s *SemanticCache) record(channel string, hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.stats[channel]
+	if !ok {
+		st = &SemanticCacheStats{}
+		s.stats[channel] = st
+	}
+	if hit {
+		st.Hits++
+	} else {
+		st.Misses++
+	}
+}
+
+// loadIndex 获取渠道的向量索引，首次使用时从存储中加载。加载时不持有锁
+func (s *SemanticCache) loadIndex(ctx context.Context, namespace string) (*semanticIndex, error) {
+	s.mu.Lock()
+	idx, ok := s.indexes[namespace]
+	if ok {
+		idx.lastUsed = time.Now()
+	}
+	s.mu.Unlock()
+	if ok {
+		return idx, nil
+	}
+
+	es, err := s.store.ListEmbeddings(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	idx = &semanticIndex{index: vector.NewIndex(), entries: make(map[int]semanticEntry, len(es))}
+	for _, e := range es {
+		idx.index.Add(e.ID, e.Vector)
+		idx.entries[e.ID] = semanticEntry{owner: e.UserID, answer: e.Metadata[semanticAnswerKey]}
+	}
+	idx.lastUsed = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 并发加载时使用先加载完成的索引
+	if loaded, ok := s.indexes[namespace]; ok {
+		return loaded, nil
+	}
+	s.evict()
+	s.indexes[namespace] = idx
+	return idx, nil
+}
+
+// evict 索引数量达到上限时丢弃最久未使用的索引，调用方需要持有锁
+func (s *SemanticCache) evict() {
+	for s.maxIndexes > 0 && len(s.indexes) >= s.maxIndexes {
+		var oldest string
+		for ns, idx := range s.indexes {
+			if oldest == "" || idx.lastUsed.Before(s.indexes[oldest].lastUsed) {
+				oldest = ns
+			}
+		}
+		delete(s.indexes, oldest)
+	}
+}
+
+// lookup 检索渠道中最相似问题的回答
+func (s *SemanticCache) lookup(ctx context.Context, q semanticQuery, v []float32) (string, bool, error) {
+	idx, err := s.loadIndex(ctx, semanticNamespace(q.channel))
+	if err != nil {
+		return "", false, err
+	}
+
+	var answer string
+	hit := false
+	if rs := idx.index.Search(v, 1); len(rs) > 0 && rs[0].Score >= s.threshold {
+		s.mu.Lock()
+		var e semanticEntry
+		e, hit = idx.entries[rs[0].ID]
+		s.mu.Unlock()
+		answer = e.answer
+	}
+	s.record(q.channel, hit)
+	return answer, hit, nil
+}
+
+func (s *SemanticCache) save(ctx context.Context, q semanticQuery, answer string, v []float32) error {
+	namespace := semanticNamespace(q.channel)
+	idx, err := s.loadIndex(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	e, err := s.store.CreateEmbedding(ctx, &conversation.Embedding{
+		Namespace: namespace,
+		UserID:    q.owner,
+		Content:   q.question,
+		Vector:    v,
+		Metadata:  map[string]string{semanticAnswerKey: answer},
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	idx.entries[e.ID] = semanticEntry{owner: q.owner, answer: answer}
+	s.mu.Unlock()
+	idx.index.Add(e.ID, v)
+	return nil
+}
+
+// forget 从进程内的索引中移除用户的缓存
+func (s *SemanticCache) forget(userId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, idx := range s.indexes {
+		ids := make([]int, 0)
+		for id, e := range idx.entries {
+			if e.owner == userId {
+				ids = append(ids, id)
+				delete(idx.entries, id)
+			}
+		}
+		idx.index.Remove(ids...)
+	}
+}
+
+func semanticNamespace(channel string) string {
+	return fmt.Sprintf("%s:%s", semanticCacheNamespace, channel)
+}
+
+// WithSemanticCache 开启语义缓存，在请求 OpenAI 之前检索相似问题的回答
+func (c *Client) WithSemanticCache(s *SemanticCache) *Client {
+	c.semanticCache = s
+	return c
+}
+
+// semanticQuery 语义缓存的查询条件
+type semanticQuery struct {
+	channel string
+	// 提问的用户，群聊中为群Id。开启加密时缓存使用该用户的数据密钥加密
+	owner    string
+	question string
+}
+
+// semanticQuestion 返回用于语义缓存的用户问题，不支持语义缓存时返回 false
+func (c *Client) semanticQuestion(cc *ChatContext) (semanticQuery, bool) {
+	if c.semanticCache == nil || cc.Request.Stream || !c.semanticCache.enabled(cc.Channel) {
+		return semanticQuery{}, false
+	}
+	msgs := cc.Request.Messages
+	for i := len(msgs) - 1; i >= 0; i-- {
+		m := msgs[i]
+		if m.Role != openai.ChatMessageRoleUser {
+			continue
+		}
+		// 多模态消息不缓存
+		if m.Content == "" || len(m.MultiContent) > 0 {
+			return semanticQuery{}, false
+		}
+		return semanticQuery{channel: cc.Channel, owner: cc.owner(), question: m.Content}, true
+	}
+	return semanticQuery{}, false
+}
+
+// lookupSemanticCache 检索语义缓存，未命中时返回问题的向量用于保存回答
+func (c *Client) lookupSemanticCache(ctx context.Context, q semanticQuery) (string, []float32, bool) {
+	vs, err := c.embed(ctx, c.semanticCache.model, []string{q.question})
+	if err != nil {
+		c.logger.Warn().Msgf("Embed question failed: %s", err)
+		return "", nil, false
+	}
+	answer, hit, err := c.semanticCache.lookup(ctx, q, vs[0])
+	if err != nil {
+		c.logger.Warn().Msgf("Lookup semantic cache failed: %s", err)
+		return "", nil, false
+	}
+	return answer, vs[0], hit
+}
+
+func (c *Client) embed(ctx context.Context, model openai.EmbeddingModel, inputs []string) ([][]float32, error) {
+	return provider.Embed(ctx, c.provider, model, inputs)
+}
+
+func semanticResponse(model, answer string) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		Object: "chat.completion",
+		Model:  model,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Message: openai.ChatCompletionMessage{
+					Role:    openai.ChatMessageRoleAssistant,
+					Content: answer,
+				},
+				FinishReason: openai.FinishReasonStop,
+			},
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package xgpt3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+)
+
+func TestSemanticCacheSharedAcrossUsers(t *testing.T) {
+	ctx := context.Background()
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Respond(func(r xgpt3test.Request) xgpt3test.Response {
+		return xgpt3test.Response{Content: "在设置页面点击“忘记密码”"}
+	})
+	ch := memory.New()
+	sc := xgpt3.NewSemanticCache(ch, 0.95).WithChannels("faq")
+	c := xgpt3.NewClient(srv.Client(), ch).WithSemanticCache(sc)
+
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("alice", "你好"), "faq"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("alice", "怎么重置密码"), "faq"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	// 其他用户在不同的上下文中提出相同的问题
+	resp, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("bob", "怎么重置密码"), "faq")
+	if err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if got := resp.Choices[0].Message.Content; got != "在设置页面点击“忘记密码”" {
+		t.Fatalf("reply = %q", got)
+	}
+	if n := chatRequests(srv); n != 2 {
+		t.Fatalf("chat requests = %d, want 2", n)
+	}
+	if st := sc.Stats()["faq"]; st.Hits != 1 || st.Misses != 2 {
+		t.Fatalf("stats = %+v", st)
+	}
+
+	// 其他渠道不共享缓存
+	if _, err := c.CreateChatCompletionWithChannel(ctx, chatRequest("bob", "怎么重置密码"), "default"); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+	if n := chatRequests(srv); n != 3 {
+		t.Fatalf("chat requests = %d, want 3", n)
+	}
+}
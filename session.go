@@ -0,0 +1,96 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fanchunke/xgpt3/conversation"
+)
+
+// sessionManager 包装会话后端的 conversation.SessionManager，关闭、重新开启和删除会话时触发会话事件
+type sessionManager struct {
+	conversation.Handler
+	sm conversation.SessionManager
+	c  *Client
+}
+
+// SessionManager 返回供会话管理接口 (例如 api.New) 使用的会话后端。通过它关闭、重新开启或删除会话时
+// 会触发 EventSessionClosed 和 EventSessionDeleted 事件，长期记忆等功能依赖这些事件。
+// 会话后端没有实现 conversation.SessionManager 时原样返回
+func (c *Client) SessionManager() conversation.Handler {
+	sm, ok := c.ch.(conversation.SessionManager)
+	if !ok {
+		return c.ch
+	}
+	return &sessionManager{Handler: c.ch, sm: sm, c: c}
+}
+
+func (s *sessionManager) ListSessions(ctx context.Context, userId string, page conversation.Page) ([]*conversation.Session, error) {
+	return s.sm.ListSessions(ctx, userId, page)
+}
+
+func (s *sessionManager) GetSession(ctx context.Context, userId string, sessionId int) (*conversation.Session, error) {
+	return s.sm.GetSession(ctx, userId, sessionId)
+}
+
+func (s *sessionManager) ListMessages(ctx context.Context, session *conversation.Session, page conversation.Page) ([]*conversation.Message, error) {
+	return s.sm.ListMessages(ctx, session, page)
+}
+
+func (s *sessionManager) CloseSessionByID(ctx context.Context, userId string, sessionId int) error {
+	session, err := s.sm.GetSession(ctx, userId, sessionId)
+	if err != nil {
+		return err
+	}
+	if err := s.sm.CloseSessionByID(ctx, userId, sessionId); err != nil {
+		return err
+	}
+	if session.Status {
+		session.Status = false
+		s.c.emit(ctx, Event{Type: EventSessionClosed, UserID: userId, Channel: session.Channel, Session: session})
+	}
+	return nil
+}
+
+// ReopenSession 重新开启会话时，同一渠道中原来开启的会话被关闭
+func (s *sessionManager) ReopenSession(ctx context.Context, userId string, sessionId int) error {
+	session, err := s.sm.GetSession(ctx, userId, sessionId)
+	if err != nil {
+		return err
+	}
+	active, err := s.c.latestSession(ctx, userId, session.Channel)
+	if errors.Is(err, conversation.ErrNotFound) {
+		active = nil
+	} else if err != nil {
+		return fmt.Errorf("get latest session failed: %w", err)
+	}
+	if err := s.sm.ReopenSession(ctx, userId, sessionId); err != nil {
+		return err
+	}
+	if active != nil && active.ID != sessionId {
+		active.Status = false
+		s.c.emit(ctx, Event{Type: EventSessionClosed, UserID: userId, Channel: active.Channel, Session: active})
+	}
+	return nil
+}
+
+func (s *sessionManager) RenameSession(ctx context.Context, userId string, sessionId int, title string) error {
+	return s.sm.RenameSession(ctx, userId, sessionId, title)
+}
+
+func (s *sessionManager) DeleteSession(ctx context.Context, userId string, sessionId int) error {
+	session, err := s.sm.GetSession(ctx, userId, sessionId)
+	if err != nil {
+		return err
+	}
+	if err := s.sm.DeleteSession(ctx, userId, sessionId); err != nil {
+		return err
+	}
+	s.c.emit(ctx, Event{Type: EventSessionDeleted, UserID: userId, Channel: session.Channel, Session: session})
+	return nil
+}
+
+func (s *sessionManager) DeleteMessage(ctx context.Context, session *conversation.Session, messageId int) error {
+	return s.sm.DeleteMessage(ctx, session, messageId)
+}
@@ -0,0 +1,343 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/fanchunke/xgpt3/redact"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ChatCompletionStream 流式对话。读取到流结束时保存回复消息
+type ChatCompletionStream struct {
+	client  *Client
+	ctx     context.Context
+	cc      *ChatContext
+	stream  provider.ChatCompletionStream
+	vault   *redact.Vault
+	restore restorers
+	span    trace.Span
+	start   time.Time
+	content strings.Builder
+	finish  openai.FinishReason
+	eof     bool
+	done    bool
+}
+
+func (c *Client) CreateChatCompletionStream(ctx context.Context, request openai.ChatCompletionRequest) (*ChatCompletionStream, error) {
+	return c.CreateChatCompletionStreamWithChannel(ctx, request, defaultChannel)
+}
+
+func (c *Client) CreateChatCompletionStreamWithChannel(ctx context.Context, request openai.ChatCompletionRequest, channel string) (*ChatCompletionStream, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "xgpt3.CreateChatCompletionStream", attribute.String("xgpt3.model", request.Model), attribute.String("xgpt3.channel", channel))
+
+	// 脱敏
+	vault := c.redactChatRequest(ctx, &request)
+	request.Stream = true
+	c.logger.Debug().Msgf("User: %s, Origin Messages: %s", request.User, marshalMessages(request.Messages))
+	cc := &ChatContext{Channel: channel, Request: request}
+
+	// 预处理
+	if err := c.runStage(ctx, cc, StagePreprocess, c.preprocessStage); err != nil {
+		err = c.failed(ctx, requestKindChat, StagePreprocess, request.User, channel, fmt.Errorf("chat completion preprocess failed: %w", err))
+		span.End()
+		return nil, err
+	}
+	c.logger.Debug().Msgf("User: %s, Messages with conversation: %s", cc.Request.User, marshalMessages(cc.Request.Messages))
+
+	// 请求
+	if err := c.runStage(ctx, cc, StageUpstream, c.upstreamStreamStage); err != nil {
+		err = c.failed(ctx, requestKindChat, StageUpstream, request.User, channel, err)
+		span.End()
+		return nil, err
+	}
+
+	return &ChatCompletionStream{
+		client:  c,
+		ctx:     ctx,
+		cc:      cc,
+		stream:  cc.stream,
+		vault:   vault,
+		restore: restorers{vault: vault},
+		span:    span,
+		start:   start,
+	}, nil
+}
+
+func (c *Client) upstreamStreamStage(ctx context.Context, cc *ChatContext) (err error) {
+	ctx, span := c.startSpan(ctx, "xgpt3.upstream", attribute.String("xgpt3.model", cc.Request.Model), attribute.Int("xgpt3.max_tokens", cc.Request.MaxTokens))
+	defer func() { endSpan(span, err) }()
+
+	cc.stream, err = c.provider.CreateChatCompletionStream(ctx, cc.Request)
+	return err
+}
+
+// Recv 读取下一个分片。流结束时保存回复消息并返回 io.EOF
+func (s *ChatCompletionStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	if s.done {
+		return openai.ChatCompletionStreamResponse{}, io.EOF
+	}
+	if s.eof {
+		return openai.ChatCompletionStreamResponse{}, s.close()
+	}
+
+	resp, err := s.stream.Recv()
+	if errors.Is(err, io.EOF) {
+		// 输出没有结束标记的回复选项中暂存的内容，下次读取时再结束
+		if tails := s.restore.flush(); len(tails) > 0 {
+			s.eof = true
+			resp = openai.ChatCompletionStreamResponse{ID: s.cc.Response.ID, Object: "chat.completion.chunk", Created: s.cc.Response.Created, Model: s.cc.Response.Model}
+			for _, t := range tails {
+				resp.Choices = append(resp.Choices, openai.ChatCompletionStreamChoice{Index: t.index, Delta: openai.ChatCompletionStreamChoiceDelta{Content: t.text}})
+			}
+			return resp, nil
+		}
+		return resp, s.close()
+	}
+	if err != nil {
+		s.done = true
+		err = s.client.failed(s.ctx, requestKindChat, StageUpstream, s.cc.Request.User, s.cc.Channel, err)
+		s.span.End()
+		return resp, err
+	}
+
+	if s.cc.Response.ID == "" {
+		s.cc.Response.ID, s.cc.Response.Created, s.cc.Response.Model = resp.ID, resp.Created, resp.Model
+	}
+	for i, choice := range resp.Choices {
+		if choice.Index == 0 {
+			s.content.WriteString(choice.Delta.Content)
+			if choice.FinishReason != "" {
+				s.finish = choice.FinishReason
+			}
+		}
+		resp.Choices[i].Delta.Content = s.restore.write(choice.Index, choice.Delta.Content, choice.FinishReason != "")
+	}
+	return resp, nil
+}
+
+// close 流结束后保存回复消息
+func (s *ChatCompletionStream) close() error {
+	s.done = true
+	defer s.span.End()
+
+	c, cc := s.client, s.cc
+	finishReason := s.finish
+	if finishReason == "" {
+		finishReason = openai.FinishReasonStop
+	}
+	cc.Response.Object = "chat.completion"
+	cc.Response.Choices = []openai.ChatCompletionChoice{
+		{
+			Message: openai.ChatCompletionMessage{
+				Role:    openai.ChatMessageRoleAssistant,
+				Content: s.content.String(),
+			},
+			FinishReason: finishReason,
+		},
+	}
+
+	// 后处理
+	if err := c.runStage(s.ctx, cc, StagePostprocess, c.postprocessStage); err != nil {
+		return c.failed(s.ctx, requestKindChat, StagePostprocess, cc.Request.User, cc.Channel, fmt.Errorf("chat completion postprocess failed: %w", err))
+	}
+	c.metrics.observe(requestKindChat, cc.Request.Model, s.start, cc.Response.Usage)
+	return io.EOF
+}
+
+// Reply 返回已保存的回复消息，流结束之后可用
+func (s *ChatCompletionStream) Reply() *conversation.Message {
+	return s.cc.Reply
+}
+
+func (s *ChatCompletionStream) Close() {
+	s.stream.Close()
+	if !s.done {
+		s.done = true
+		s.span.End()
+	}
+}
+
+// CompletionStream 流式补全。读取到流结束时保存回复消息
+type CompletionStream struct {
+	client  *Client
+	ctx     context.Context
+	request openai.CompletionRequest
+	channel string
+	session *conversation.Session
+	msg     *conversation.Message
+	stream  provider.CompletionStream
+	vault   *redact.Vault
+	restore restorers
+	span    trace.Span
+	start   time.Time
+	resp    openai.CompletionResponse
+	content strings.Builder
+	eof     bool
+	done    bool
+}
+
+func (c *Client) CreateConversationCompletionStream(ctx context.Context, request openai.CompletionRequest) (*CompletionStream, error) {
+	return c.CreateConversationCompletionStreamWithChannel(ctx, request, defaultChannel)
+}
+
+func (c *Client) CreateConversationCompletionStreamWithChannel(ctx context.Context, request openai.CompletionRequest, channel string) (*CompletionStream, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, "xgpt3.CreateConversationCompletionStream", attribute.String("xgpt3.model", request.Model), attribute.String("xgpt3.channel", channel))
+
+	// 脱敏
+	vault := c.redactCompletionRequest(ctx, &request)
+	request.Stream = true
+	c.logger.Debug().Msgf("User: %s, Origin Prompt: %s", request.User, request.Prompt)
+	// 预处理
+	session, msg, err := c.preCompletion(ctx, &request, channel)
+	if err != nil {
+		err = c.failed(ctx, requestKindCompletion, StagePreprocess, request.User, channel, fmt.Errorf("preprocess failed: %w", err))
+		span.End()
+		return nil, err
+	}
+	c.logger.Debug().Msgf("User: %s, Prompt with conversation: %s", request.User, request.Prompt)
+
+	// 请求
+	upstreamCtx, upstreamSpan := c.startSpan(ctx, "xgpt3.upstream", attribute.String("xgpt3.model", request.Model), attribute.Int("xgpt3.max_tokens", request.MaxTokens))
+	stream, err := c.provider.CreateCompletionStream(upstreamCtx, request)
+	endSpan(upstreamSpan, err)
+	if err != nil {
+		err = c.failed(ctx, requestKindCompletion, StageUpstream, request.User, channel, err)
+		span.End()
+		return nil, err
+	}
+
+	return &CompletionStream{
+		client:  c,
+		ctx:     ctx,
+		request: request,
+		channel: channel,
+		session: session,
+		msg:     msg,
+		stream:  stream,
+		vault:   vault,
+		restore: restorers{vault: vault},
+		span:    span,
+		start:   start,
+	}, nil
+}
+
+// Recv 读取下一个分片。流结束时保存回复消息并返回 io.EOF
+func (s *CompletionStream) Recv() (openai.CompletionResponse, error) {
+	if s.done {
+		return openai.CompletionResponse{}, io.EOF
+	}
+	if s.eof {
+		return openai.CompletionResponse{}, s.close()
+	}
+
+	resp, err := s.stream.Recv()
+	if errors.Is(err, io.EOF) {
+		// 输出没有结束标记的回复选项中暂存的内容，下次读取时再结束
+		if tails := s.restore.flush(); len(tails) > 0 {
+			s.eof = true
+			resp = openai.CompletionResponse{ID: s.resp.ID, Object: "text_completion", Created: s.resp.Created, Model: s.resp.Model}
+			for _, t := range tails {
+				resp.Choices = append(resp.Choices, openai.CompletionChoice{Index: t.index, Text: t.text})
+			}
+			return resp, nil
+		}
+		return resp, s.close()
+	}
+	if err != nil {
+		s.done = true
+		err = s.client.failed(s.ctx, requestKindCompletion, StageUpstream, s.request.User, s.channel, err)
+		s.span.End()
+		return resp, err
+	}
+
+	if s.resp.ID == "" {
+		s.resp.ID, s.resp.Created, s.resp.Model = resp.ID, resp.Created, resp.Model
+	}
+	for i, choice := range resp.Choices {
+		if choice.Index == 0 {
+			s.content.WriteString(choice.Text)
+		}
+		resp.Choices[i].Text = s.restore.write(choice.Index, choice.Text, choice.FinishReason != "")
+	}
+	return resp, nil
+}
+
+// close 流结束后保存回复消息
+func (s *CompletionStream) close() error {
+	s.done = true
+	defer s.span.End()
+
+	s.resp.Object = "text_completion"
+	s.resp.Choices = []openai.CompletionChoice{{Text: s.content.String(), FinishReason: string(openai.FinishReasonStop)}}
+
+	// 后处理
+	if _, err := s.client.postCompletion(s.ctx, s.request, s.resp, s.session, s.msg, s.channel); err != nil {
+		return s.client.failed(s.ctx, requestKindCompletion, StagePostprocess, s.request.User, s.channel, fmt.Errorf("postprocess failed: %w", err))
+	}
+	s.client.metrics.observe(requestKindCompletion, s.request.Model, s.start, s.resp.Usage)
+	return io.EOF
+}
+
+func (s *CompletionStream) Close() {
+	s.stream.Close()
+	if !s.done {
+		s.done = true
+		s.span.End()
+	}
+}
+
+// restorers 按回复选项还原流式分片中的占位符。没有开启可逆脱敏时原样返回
+type restorers struct {
+	vault *redact.Vault
+	byIdx map[int]*redact.StreamRestorer
+}
+
+type restoredTail struct {
+	index int
+	text  string
+}
+
+func (r *restorers) write(index int, delta string, finished bool) string {
+	if r.vault == nil {
+		return delta
+	}
+	sr, ok := r.byIdx[index]
+	if !ok {
+		if r.byIdx == nil {
+			r.byIdx = make(map[int]*redact.StreamRestorer)
+		}
+		sr = r.vault.Stream()
+		r.byIdx[index] = sr
+	}
+	text := sr.Write(delta)
+	if finished {
+		text += sr.Flush()
+		delete(r.byIdx, index)
+	}
+	return text
+}
+
+// flush 返回所有回复选项中暂存的内容
+func (r *restorers) flush() []restoredTail {
+	tails := make([]restoredTail, 0, len(r.byIdx))
+	for index, sr := range r.byIdx {
+		if text := sr.Flush(); text != "" {
+			tails = append(tails, restoredTail{index: index, text: text})
+		}
+	}
+	r.byIdx = nil
+	sort.Slice(tails, func(i, j int) bool { return tails[i].index < tails[j].index })
+	return tails
+}
@@ -0,0 +1,132 @@
+package xgpt3
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/fanchunke/xgpt3"
+
+const (
+	requestKindChat       = "chat"
+	requestKindCompletion = "completion"
+)
+
+// WithTracerProvider 开启 OpenTelemetry 链路追踪
+func (c *Client) WithTracerProvider(tp trace.TracerProvider) *Client {
+	c.tracer = tp.Tracer(instrumentationName)
+	return c
+}
+
+// WithMetrics 开启 Prometheus 指标，指标注册到 reg 中
+func (c *Client) WithMetrics(reg prometheus.Registerer) *Client {
+	c.metrics = newMetrics(reg)
+	return c
+}
+
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func usageAttributes(usage openai.Usage) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int("xgpt3.usage.prompt_tokens", usage.PromptTokens),
+		attribute.Int("xgpt3.usage.completion_tokens", usage.CompletionTokens),
+		attribute.Int("xgpt3.usage.total_tokens", usage.TotalTokens),
+	}
+}
+
+type metrics struct {
+	duration    *prometheus.HistogramVec
+	tokens      *prometheus.CounterVec
+	errors      *prometheus.CounterVec
+	truncations *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "xgpt3",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of conversation completion requests.",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+		}, []string{"kind", "model"}),
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "xgpt3",
+			Name:      "tokens_total",
+			Help:      "Tokens consumed by conversation completion requests.",
+		}, []string{"kind", "model", "type"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "xgpt3",
+			Name:      "errors_total",
+			Help:      "Failed conversation completion requests by error type.",
+		}, []string{"kind", "type"}),
+		truncations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "xgpt3",
+			Name:      "history_truncations_total",
+			Help:      "Requests whose history or prompt was truncated to fit the context length.",
+		}, []string{"kind"}),
+	}
+	reg.MustRegister(m.duration, m.tokens, m.errors, m.truncations)
+	return m
+}
+
+func (m *metrics) observe(kind, model string, start time.Time, usage openai.Usage) {
+	if m == nil {
+		return
+	}
+	m.duration.WithLabelValues(kind, model).Observe(time.Since(start).Seconds())
+	m.tokens.WithLabelValues(kind, model, "prompt").Add(float64(usage.PromptTokens))
+	m.tokens.WithLabelValues(kind, model, "completion").Add(float64(usage.CompletionTokens))
+}
+
+func (m *metrics) failed(kind string, stage Stage, err error) {
+	if m == nil {
+		return
+	}
+	m.errors.WithLabelValues(kind, errorType(stage, err)).Inc()
+}
+
+func (m *metrics) truncated(kind string) {
+	if m == nil {
+		return
+	}
+	m.truncations.WithLabelValues(kind).Inc()
+}
+
+func errorType(stage Stage, err error) string {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if stage != StageUpstream {
+		return string(stage)
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.HTTPStatusCode == 429 {
+			return "rate_limit"
+		}
+		return "api"
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return "request"
+	}
+	return "upstream"
+}
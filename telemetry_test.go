@@ -0,0 +1,148 @@
+package xgpt3_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fanchunke/xgpt3"
+	"github.com/fanchunke/xgpt3/conversation/memory"
+	"github.com/fanchunke/xgpt3/xgpt3test"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedClient(t *testing.T) (*xgpt3.Client, *xgpt3test.Server, *tracetest.SpanRecorder, *prometheus.Registry) {
+	t.Helper()
+	srv := xgpt3test.NewServer()
+	t.Cleanup(srv.Close)
+	recorder := tracetest.NewSpanRecorder()
+	reg := prometheus.NewRegistry()
+	c := xgpt3.NewClient(srv.Client(), memory.New()).
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))).
+		WithMetrics(reg)
+	return c, srv, recorder, reg
+}
+
+func spansByName(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	result := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		result[s.Name()] = s
+	}
+	return result
+}
+
+func spanAttribute(s sdktrace.ReadOnlySpan, key string) (attribute.Value, bool) {
+	for _, kv := range s.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestChatCompletionSpans(t *testing.T) {
+	ctx := context.Background()
+	c, srv, recorder, _ := newTracedClient(t)
+	srv.Enqueue(xgpt3test.Response{Content: "回复", Usage: openai.Usage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10}})
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	spans := spansByName(recorder)
+	root, ok := spans["xgpt3.CreateChatCompletion"]
+	if !ok {
+		t.Fatalf("root span missing, got %v", spans)
+	}
+	for _, name := range []string{"xgpt3.session_lookup", "xgpt3.history_load", "xgpt3.prompt_assembly", "xgpt3.upstream", "xgpt3.persist"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Fatalf("span %s missing", name)
+		}
+		if s.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Fatalf("span %s not in the request trace", name)
+		}
+	}
+	if v, ok := spanAttribute(root, "xgpt3.model"); !ok || v.AsString() != openai.GPT3Dot5Turbo {
+		t.Fatalf("xgpt3.model = %v", v.Emit())
+	}
+	if v, ok := spanAttribute(spans["xgpt3.upstream"], "xgpt3.usage.total_tokens"); !ok || v.AsInt64() != 10 {
+		t.Fatalf("xgpt3.usage.total_tokens = %v", v.Emit())
+	}
+}
+
+func TestChatCompletionErrorSpan(t *testing.T) {
+	ctx := context.Background()
+	c, srv, recorder, reg := newTracedClient(t)
+	srv.Enqueue(xgpt3test.Response{StatusCode: http.StatusTooManyRequests, ErrorMessage: "rate limited", ErrorType: "rate_limit_exceeded"})
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好")); err == nil {
+		t.Fatal("CreateChatCompletion() error = nil")
+	}
+	upstream, ok := spansByName(recorder)["xgpt3.upstream"]
+	if !ok {
+		t.Fatal("upstream span missing")
+	}
+	if upstream.Status().Code != codes.Error {
+		t.Fatalf("upstream span status = %v, want Error", upstream.Status().Code)
+	}
+
+	expected := `
+# HELP xgpt3_errors_total Failed conversation completion requests by error type.
+# TYPE xgpt3_errors_total counter
+xgpt3_errors_total{kind="chat",type="rate_limit"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "xgpt3_errors_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChatCompletionMetrics(t *testing.T) {
+	ctx := context.Background()
+	c, srv, _, reg := newTracedClient(t)
+	srv.Enqueue(xgpt3test.Response{Content: "回复", Usage: openai.Usage{PromptTokens: 7, CompletionTokens: 3, TotalTokens: 10}})
+
+	if _, err := c.CreateChatCompletion(ctx, chatRequest("alice", "你好")); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	expected := `
+# HELP xgpt3_tokens_total Tokens consumed by conversation completion requests.
+# TYPE xgpt3_tokens_total counter
+xgpt3_tokens_total{kind="chat",model="gpt-3.5-turbo",type="completion"} 3
+xgpt3_tokens_total{kind="chat",model="gpt-3.5-turbo",type="prompt"} 7
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "xgpt3_tokens_total"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := testutil.GatherAndCount(reg, "xgpt3_request_duration_seconds"); err != nil || n != 1 {
+		t.Fatalf("request_duration_seconds series = %d, err = %v", n, err)
+	}
+}
+
+func TestHistoryTruncationMetric(t *testing.T) {
+	ctx := context.Background()
+	c, _, _, reg := newTracedClient(t)
+
+	req := chatRequest("alice", strings.Repeat("长", 100))
+	req.MaxTokens = 4090
+	if _, err := c.CreateChatCompletion(ctx, req); err != nil {
+		t.Fatalf("CreateChatCompletion() error = %v", err)
+	}
+
+	expected := `
+# HELP xgpt3_history_truncations_total Requests whose history or prompt was truncated to fit the context length.
+# TYPE xgpt3_history_truncations_total counter
+xgpt3_history_truncations_total{kind="chat"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "xgpt3_history_truncations_total"); err != nil {
+		t.Fatal(err)
+	}
+}
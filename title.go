@@ -0,0 +1,93 @@
+package xgpt3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fanchunke/xgpt3/conversation"
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	titlePrompt     = "用不超过 12 个字概括以下对话的主题，作为对话标题。只输出标题本身，不要加引号和标点。"
+	titleMaxTokens  = 32
+	titleMaxRunes   = 50
+	titleInputRunes = 500
+)
+
+// WithSessionTitles 第一轮对话结束后，异步请求模型为会话生成简短的标题。
+// model 为空时使用 gpt-3.5-turbo。会话后端需要实现 conversation.SessionManager
+func (c *Client) WithSessionTitles(model string) *Client {
+	if model == "" {
+		model = openai.GPT3Dot5Turbo
+	}
+	c.titleModel = model
+	c.SubscribeAsync(EventReplyStored, c.generateTitle)
+	return c
+}
+
+func (c *Client) generateTitle(ctx context.Context, e Event) {
+	sm, ok := c.ch.(conversation.SessionManager)
+	if !ok || e.Session == nil || e.Session.Title != "" {
+		return
+	}
+
+	// 只在第一轮对话之后生成。会话属于 Session.UserID，群聊会话中 e.UserID 只是发言的成员
+	var msgs []*conversation.Message
+	var err error
+	if e.Session.GroupChat {
+		msgs, err = c.listGroupTurns(ctx, e.Session, 2)
+	} else {
+		msgs, err = c.ch.ListLatestMessagesWithSpouse(ctx, e.Session, e.Session.UserID, 2)
+	}
+	if err != nil {
+		c.logger.Warn().Msgf("List session %d messages failed: %s", e.Session.ID, err)
+		return
+	}
+	if len(msgs) != 2 {
+		return
+	}
+
+	resp, err := c.provider.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model:     c.titleModel,
+		MaxTokens: titleMaxTokens,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: titlePrompt},
+			{Role: openai.ChatMessageRoleUser, Content: fmt.Sprintf("user: %s\nassistant: %s", truncateRunes(msgs[0].Content, titleInputRunes), truncateRunes(msgs[1].Content, titleInputRunes))},
+		},
+		User: e.UserID,
+	})
+	if err != nil {
+		c.logger.Warn().Msgf("Generate session %d title failed: %s", e.Session.ID, err)
+		return
+	}
+	if len(resp.Choices) == 0 {
+		return
+	}
+	title := cleanTitle(resp.Choices[0].Message.Content)
+	if title == "" {
+		return
+	}
+	if err := sm.RenameSession(ctx, e.Session.UserID, e.Session.ID, title); err != nil {
+		c.logger.Warn().Msgf("Save session %d title failed: %s", e.Session.ID, err)
+	}
+}
+
+// cleanTitle 去掉模型输出中多余的引号、标点和换行
+func cleanTitle(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimPrefix(s, "标题：")
+	s = strings.Trim(s, " \t\"'“”‘’「」《》。.!！?？")
+	return truncateRunes(s, titleMaxRunes)
+}
+
+func truncateRunes(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package vector
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Result 检索结果
+type Result struct {
+	// 向量Id
+	ID int
+	// 余弦相似度
+	Score float32
+}
+
+type item struct {
+	id     int
+	vector []float32
+}
+
+// Index 进程内的暴力检索向量索引
+type Index struct {
+	mu    sync.RWMutex
+	items []item
+}
+
+func NewIndex() *Index {
+	return &Index{}
+}
+
+// Add 添加向量。向量会被归一化，检索时使用内积计算余弦相似度
+func (idx *Index) Add(id int, v []float32) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.items = append(idx.items, item{id: id, vector: normalize(v)})
+}
+
+// Remove 删除向量
+func (idx *Index) Remove(ids ...int) {
+	removed := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		removed[id] = true
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	kept := idx.items[:0]
+	for _, it := range idx.items {
+		if !removed[it.id] {
+			kept = append(kept, it)
+		}
+	}
+	idx.items = kept
+}
+
+// Len 返回索引中的向量数量
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.items)
+}
+
+// Search 返回与 q 最相似的 k 个向量，按相似度降序排列
+func (idx *Index) Search(q []float32, k int) []Result {
+	q = normalize(q)
+
+	idx.mu.RLock()
+	results := make([]Result, 0, len(idx.items))
+	for _, it := range idx.items {
+		if len(it.vector) != len(q) {
+			continue
+		}
+		results = append(results, Result{ID: it.id, Score: dot(q, it.vector)})
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if k > 0 && len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// Cosine 计算两个向量的余弦相似度
+func Cosine(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+	return dot(normalize(a), normalize(b))
+}
+
+func dot(a, b []float32) float32 {
+	var s float32
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+func normalize(v []float32) []float32 {
+	var s float64
+	for _, f := range v {
+		s += float64(f) * float64(f)
+	}
+	if s == 0 {
+		return v
+	}
+	n := float32(math.Sqrt(s))
+	result := make([]float32, len(v))
+	for i, f := range v {
+		result[i] = f / n
+	}
+	return result
+}
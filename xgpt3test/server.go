@@ -0,0 +1,517 @@
+// Package xgpt3test 提供模拟 OpenAI 接口的测试服务，用于编写确定性的测试。
+//
+// 服务支持对话、补全、流式输出、Embeddings、内容审核、语音转写和语音合成接口，可以预设返回结果、错误、延迟和限流响应头，
+// 并记录收到的每个请求，方便断言拼接好的历史消息。
+package xgpt3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fanchunke/xgpt3/provider"
+	"github.com/sashabaranov/go-openai"
+)
+
+// 默认 Embeddings 向量维度
+const defaultDimensions = 16
+
+// Response 预设的返回结果
+type Response struct {
+	// 对话或补全返回的内容，语音转写接口返回的文本
+	Content string
+	// 流式输出时按顺序返回的片段，为空时整个 Content 作为一个片段返回
+	Chunks []string
+	// 结束原因，为空时为 stop
+	FinishReason string
+	// token 用量
+	Usage openai.Usage
+	// Embeddings 接口返回的向量，为空时根据输入文本生成确定的向量
+	Embeddings [][]float32
+	// 内容审核是否命中
+	Flagged bool
+	// 语音合成接口返回的音频，为空时返回 Content
+	Audio []byte
+
+	// 非 0 时返回错误，例如 429、500
+	StatusCode int
+	// 错误信息
+	ErrorMessage string
+	// 错误类型
+	ErrorType string
+
+	// 附加的响应头
+	Header http.Header
+	// 返回前等待的时间
+	Latency time.Duration
+}
+
+// Request 收到的请求
+type Request struct {
+	// 请求路径，例如 /v1/chat/completions
+	Path   string
+	Header http.Header
+	Body   []byte
+
+	// 按请求路径解析后的请求体，只有对应的字段不为空
+	Chat          *openai.ChatCompletionRequest
+	Completion    *openai.CompletionRequest
+	Embedding     *EmbeddingRequest
+	Moderation    *openai.ModerationRequest
+	Transcription *TranscriptionRequest
+	Speech        *openai.CreateSpeechRequest
+}
+
+// TranscriptionRequest 语音转写请求，从 multipart 表单中解析
+type TranscriptionRequest struct {
+	Model    string
+	Language string
+	Prompt   string
+	// 上传的文件名和内容
+	FileName string
+	Audio    []byte
+}
+
+// EmbeddingRequest Embeddings 请求，Input 统一为字符串列表
+type EmbeddingRequest struct {
+	Input []string
+	Model string
+}
+
+// Messages 对话请求中的消息列表
+func (r Request) Messages() []openai.ChatCompletionMessage {
+	if r.Chat == nil {
+		return nil
+	}
+	return r.Chat.Messages
+}
+
+// Server 模拟 OpenAI 接口的测试服务
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	script     []Response
+	responder  func(r Request) Response
+	latency    time.Duration
+	header     http.Header
+	dimensions int
+	requests   []Request
+}
+
+// NewServer 启动测试服务。没有预设结果时，对话和补全接口原样返回最后一条消息的内容
+func NewServer() *Server {
+	s := &Server{
+		responder:  echo,
+		header:     make(http.Header),
+		dimensions: defaultDimensions,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// BaseURL 接口地址，包含 /v1
+func (s *Server) BaseURL() string {
+	return s.URL + "/v1"
+}
+
+// Client 连接测试服务的 go-openai client
+func (s *Server) Client() *openai.Client {
+	config := openai.DefaultConfig("test")
+	config.BaseURL = s.BaseURL()
+	return openai.NewClientWithConfig(config)
+}
+
+// Provider 连接测试服务的 Provider
+func (s *Server) Provider() provider.Provider {
+	return provider.NewCompatible(s.BaseURL(), "test")
+}
+
+// Enqueue 追加预设结果，请求按顺序依次使用，用完后使用 Respond 设置的处理函数
+func (s *Server) Enqueue(responses ...Response) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, responses...)
+	return s
+}
+
+// Respond 设置没有预设结果时的处理函数
+func (s *Server) Respond(f func(r Request) Response) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responder = f
+	return s
+}
+
+// WithLatency 每个请求返回前等待的时间
+func (s *Server) WithLatency(d time.Duration) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+	return s
+}
+
+// WithRateLimit 在每个响应中加上限流响应头
+func (s *Server) WithRateLimit(limitRequests, remainingRequests, limitTokens, remainingTokens int) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header.Set("x-ratelimit-limit-requests", strconv.Itoa(limitRequests))
+	s.header.Set("x-ratelimit-remaining-requests", strconv.Itoa(remainingRequests))
+	s.header.Set("x-ratelimit-limit-tokens", strconv.Itoa(limitTokens))
+	s.header.Set("x-ratelimit-remaining-tokens", strconv.Itoa(remainingTokens))
+	s.header.Set("x-ratelimit-reset-requests", "1s")
+	s.header.Set("x-ratelimit-reset-tokens", "1s")
+	return s
+}
+
+// WithDimensions 设置自动生成的 Embeddings 向量维度
+func (s *Server) WithDimensions(n int) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dimensions = n
+	return s
+}
+
+// Requests 收到的所有请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// LastRequest 最后一个请求，没有请求时返回零值
+func (s *Server) LastRequest() Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// Reset 清空预设结果和请求记录
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = nil
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+	req, err := parseRequest(r.URL.Path, r.Header, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var resp Response
+	if len(s.script) > 0 {
+		resp, s.script = s.script[0], s.script[1:]
+	} else {
+		resp = s.responder(req)
+	}
+	latency, dimensions := s.latency, s.dimensions
+	for k, v := range s.header {
+		w.Header()[k] = v
+	}
+	s.mu.Unlock()
+
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	if resp.Latency > 0 {
+		latency = resp.Latency
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if resp.StatusCode != 0 && resp.StatusCode != http.StatusOK {
+		writeError(w, resp.StatusCode, resp.ErrorType, resp.ErrorMessage)
+		return
+	}
+
+	switch {
+	case req.Chat != nil && req.Chat.Stream:
+		writeChatStream(w, req.Chat, resp)
+	case req.Chat != nil:
+		writeJSON(w, chatResponse(req.Chat, resp))
+	case req.Completion != nil && req.Completion.Stream:
+		writeCompletionStream(w, req.Completion, resp)
+	case req.Completion != nil:
+		writeJSON(w, completionResponse(req.Completion, resp))
+	case req.Embedding != nil:
+		writeJSON(w, embeddingResponse(req.Embedding, resp, dimensions))
+	case req.Moderation != nil:
+		writeJSON(w, openai.ModerationResponse{
+			ID:      "modr-test",
+			Model:   "text-moderation-latest",
+			Results: []openai.Result{{Flagged: resp.Flagged}},
+		})
+	case req.Transcription != nil:
+		writeJSON(w, map[string]string{"text": resp.Content})
+	case req.Speech != nil:
+		audio := resp.Audio
+		if audio == nil {
+			audio = []byte(resp.Content)
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(audio)
+	}
+}
+
+func parseRequest(path string, header http.Header, body []byte) (Request, error) {
+	req := Request{Path: path, Header: header.Clone(), Body: body}
+	var err error
+	switch path {
+	case "/v1/chat/completions":
+		req.Chat = &openai.ChatCompletionRequest{}
+		err = json.Unmarshal(body, req.Chat)
+	case "/v1/completions":
+		req.Completion = &openai.CompletionRequest{}
+		err = json.Unmarshal(body, req.Completion)
+	case "/v1/embeddings":
+		var raw struct {
+			Input json.RawMessage `json:"input"`
+			Model string          `json:"model"`
+		}
+		if err = json.Unmarshal(body, &raw); err != nil {
+			break
+		}
+		req.Embedding = &EmbeddingRequest{Model: raw.Model}
+		if err = json.Unmarshal(raw.Input, &req.Embedding.Input); err != nil {
+			var input string
+			if err = json.Unmarshal(raw.Input, &input); err == nil {
+				req.Embedding.Input = []string{input}
+			}
+		}
+	case "/v1/moderations":
+		req.Moderation = &openai.ModerationRequest{}
+		err = json.Unmarshal(body, req.Moderation)
+	case "/v1/audio/transcriptions":
+		req.Transcription, err = parseTranscription(header.Get("Content-Type"), body)
+	case "/v1/audio/speech":
+		req.Speech = &openai.CreateSpeechRequest{}
+		err = json.Unmarshal(body, req.Speech)
+	default:
+		return req, fmt.Errorf("unsupported path %s", path)
+	}
+	return req, err
+}
+
+func parseTranscription(contentType string, body []byte) (*TranscriptionRequest, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	form, err := multipart.NewReader(bytes.NewReader(body), params["boundary"]).ReadForm(int64(len(body)) + 1)
+	if err != nil {
+		return nil, err
+	}
+	defer form.RemoveAll()
+
+	req := &TranscriptionRequest{}
+	get := func(key string) string {
+		if v := form.Value[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	req.Model, req.Language, req.Prompt = get("model"), get("language"), get("prompt")
+	if files := form.File["file"]; len(files) > 0 {
+		f, err := files[0].Open()
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		req.FileName = files[0].Filename
+		if req.Audio, err = io.ReadAll(f); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// echo 返回最后一条消息或 prompt 的内容
+func echo(r Request) Response {
+	switch {
+	case r.Chat != nil && len(r.Chat.Messages) > 0:
+		return Response{Content: r.Chat.Messages[len(r.Chat.Messages)-1].Content}
+	case r.Completion != nil:
+		if prompt, ok := r.Completion.Prompt.(string); ok {
+			return Response{Content: prompt}
+		}
+	}
+	return Response{}
+}
+
+func finishReason(resp Response) string {
+	if resp.FinishReason != "" {
+		return resp.FinishReason
+	}
+	return "stop"
+}
+
+func chunks(resp Response) []string {
+	if len(resp.Chunks) > 0 {
+		return resp.Chunks
+	}
+	return []string{resp.Content}
+}
+
+func chatResponse(req *openai.ChatCompletionRequest, resp Response) openai.ChatCompletionResponse {
+	return openai.ChatCompletionResponse{
+		ID:      "chatcmpl-test",
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []openai.ChatCompletionChoice{{
+			Message:      openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: resp.Content},
+			FinishReason: openai.FinishReason(finishReason(resp)),
+		}},
+		Usage: resp.Usage,
+	}
+}
+
+func completionResponse(req *openai.CompletionRequest, resp Response) openai.CompletionResponse {
+	return openai.CompletionResponse{
+		ID:      "cmpl-test",
+		Object:  "text_completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []openai.CompletionChoice{{Text: resp.Content, FinishReason: finishReason(resp)}},
+		Usage:   resp.Usage,
+	}
+}
+
+func embeddingResponse(req *EmbeddingRequest, resp Response, dimensions int) openai.EmbeddingResponse {
+	result := openai.EmbeddingResponse{Object: "list", Model: openai.AdaEmbeddingV2, Usage: resp.Usage}
+	for i, input := range req.Input {
+		var v []float32
+		if i < len(resp.Embeddings) {
+			v = resp.Embeddings[i]
+		} else {
+			v = Vector(input, dimensions)
+		}
+		result.Data = append(result.Data, openai.Embedding{Object: "embedding", Embedding: v, Index: i})
+	}
+	return result
+}
+
+// Vector 根据文本生成确定的向量，相同的文本得到相同的向量
+func Vector(text string, dimensions int) []float32 {
+	v := make([]float32, dimensions)
+	for i := range v {
+		h := fnv.New32a()
+		fmt.Fprintf(h, "%d:%s", i, text)
+		v[i] = float32(h.Sum32()%2000)/1000 - 1
+	}
+	return v
+}
+
+func writeChatStream(w http.ResponseWriter, req *openai.ChatCompletionRequest, resp Response) {
+	stream := newStreamWriter(w)
+	for i, chunk := range chunks(resp) {
+		delta := openai.ChatCompletionStreamChoiceDelta{Content: chunk}
+		if i == 0 {
+			delta.Role = openai.ChatMessageRoleAssistant
+		}
+		stream.write(openai.ChatCompletionStreamResponse{
+			ID:      "chatcmpl-test",
+			Object:  "chat.completion.chunk",
+			Model:   req.Model,
+			Choices: []openai.ChatCompletionStreamChoice{{Delta: delta}},
+		})
+	}
+	stream.write(openai.ChatCompletionStreamResponse{
+		ID:      "chatcmpl-test",
+		Object:  "chat.completion.chunk",
+		Model:   req.Model,
+		Choices: []openai.ChatCompletionStreamChoice{{FinishReason: openai.FinishReason(finishReason(resp))}},
+	})
+	stream.done()
+}
+
+func writeCompletionStream(w http.ResponseWriter, req *openai.CompletionRequest, resp Response) {
+	stream := newStreamWriter(w)
+	for _, chunk := range chunks(resp) {
+		stream.write(openai.CompletionResponse{
+			ID:      "cmpl-test",
+			Object:  "text_completion",
+			Model:   req.Model,
+			Choices: []openai.CompletionChoice{{Text: chunk}},
+		})
+	}
+	stream.write(openai.CompletionResponse{
+		ID:      "cmpl-test",
+		Object:  "text_completion",
+		Model:   req.Model,
+		Choices: []openai.CompletionChoice{{FinishReason: finishReason(resp)}},
+	})
+	stream.done()
+}
+
+type streamWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func newStreamWriter(w http.ResponseWriter) *streamWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	return &streamWriter{w: w, flusher: flusher}
+}
+
+func (s *streamWriter) write(v any) {
+	b, _ := json.Marshal(v)
+	fmt.Fprintf(s.w, "data: %s\n\n", b)
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func (s *streamWriter) done() {
+	fmt.Fprint(s.w, "data: [DONE]\n\n")
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, typ, message string) {
+	if typ == "" {
+		typ = "server_error"
+	}
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"message": message, "type": typ, "code": status},
+	})
+}